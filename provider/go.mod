@@ -4,9 +4,13 @@ go 1.16
 
 require (
 	github.com/alecthomas/jsonschema v0.0.0-20211022214203-8b29eab41725
+	github.com/evanphx/json-patch/v5 v5.2.0
 	github.com/golang/protobuf v1.5.2
 	github.com/pkg/errors v0.9.1
 	github.com/pulumi/pulumi/pkg/v3 v3.17.0
 	github.com/pulumi/pulumi/sdk/v3 v3.17.0
+	gopkg.in/yaml.v3 v3.0.0-20210107192922-496545a6307b
+	k8s.io/apimachinery v0.20.2
 	sigs.k8s.io/kind v0.11.1
+	sigs.k8s.io/yaml v1.2.0
 )
@@ -0,0 +1,72 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package container
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ArchiveImage is an image in an image archive
+type ArchiveImage struct {
+	// ID is the image ID, the digest of the image config
+	ID string
+	// Tags are the references the image is tagged with, if any
+	Tags []string
+}
+
+// ArchiveImages lists the images of an image archive created by docker/podman save
+func ArchiveImages(archive string) ([]ArchiveImage, error) {
+	f, err := os.Open(archive)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open image archive %s", archive)
+	}
+	defer f.Close()
+
+	reader := tar.NewReader(f)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return nil, errors.Errorf("%s is not an image archive, it has no manifest.json", archive)
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read image archive %s", archive)
+		}
+		if header.Name != "manifest.json" {
+			continue
+		}
+
+		var manifest []struct {
+			Config   string
+			RepoTags []string
+		}
+		if err = json.NewDecoder(reader).Decode(&manifest); err != nil {
+			return nil, errors.Wrapf(err, "failed to decode manifest of image archive %s", archive)
+		}
+		images := make([]ArchiveImage, 0, len(manifest))
+		for _, m := range manifest {
+			// the config is either <digest>.json or blobs/sha256/<digest>
+			digest := strings.TrimSuffix(path.Base(m.Config), ".json")
+			images = append(images, ArchiveImage{ID: "sha256:" + digest, Tags: m.RepoTags})
+		}
+		return images, nil
+	}
+}
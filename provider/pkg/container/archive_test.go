@@ -0,0 +1,41 @@
+package container
+
+import (
+	"archive/tar"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestArchiveImages(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "images.tar")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	manifest := `[{"Config":"0123abcd.json","RepoTags":["app:dev"]},{"Config":"blobs/sha256/4567ef01","RepoTags":null}]`
+	w := tar.NewWriter(f)
+	if err = w.WriteHeader(&tar.Header{Name: "manifest.json", Mode: 0644, Size: int64(len(manifest))}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = w.Write([]byte(manifest)); err != nil {
+		t.Fatal(err)
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	images, err := ArchiveImages(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []ArchiveImage{
+		{ID: "sha256:0123abcd", Tags: []string{"app:dev"}},
+		{ID: "sha256:4567ef01"},
+	}
+	if !reflect.DeepEqual(images, expected) {
+		t.Errorf("expected %+v, got %+v", expected, images)
+	}
+}
@@ -0,0 +1,635 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package container wraps the docker/podman cli for the operations the kind library does not expose
+package container
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/kind/pkg/exec"
+)
+
+// Runtime runs commands against a container runtime cli, either docker or podman
+// both of which support the same subset of commands used here
+type Runtime struct {
+	binary string
+}
+
+// NewRuntime returns a runtime using the given cli binary
+func NewRuntime(binary string) *Runtime {
+	return &Runtime{binary: binary}
+}
+
+func (r *Runtime) command(args ...string) exec.Cmd {
+	return exec.Command(r.binary, args...)
+}
+
+// output runs the command and returns the trimmed stdout
+func (r *Runtime) output(args ...string) (string, error) {
+	lines, err := exec.OutputLines(r.command(args...))
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to run %s %s", r.binary, strings.Join(args, " "))
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n")), nil
+}
+
+// Local checks if the containers run on this machine, so the ports they publish are bound here.
+// Remote docker hosts and podman machines publish them on another machine.
+func (r *Runtime) Local() bool {
+	if r.binary == "podman" {
+		out, err := r.output("info", "--format", "{{.Host.ServiceIsRemote}}")
+		return err != nil || out != "true"
+	}
+	host := os.Getenv("DOCKER_HOST")
+	if host == "" {
+		out, err := r.output("context", "inspect", "--format", "{{.Endpoints.docker.Host}}")
+		if err != nil {
+			return true
+		}
+		host = out
+	}
+	return localEndpoint(host)
+}
+
+// localEndpoint checks if the docker endpoint is a local socket or a TCP port of the loopback interface
+func localEndpoint(host string) bool {
+	u, err := url.Parse(host)
+	if err != nil {
+		return false
+	}
+	switch u.Scheme {
+	case "", "unix", "npipe":
+		return true
+	case "tcp", "http", "https":
+		if u.Hostname() == "localhost" {
+			return true
+		}
+		ip := net.ParseIP(u.Hostname())
+		return ip != nil && ip.IsLoopback()
+	}
+	return false
+}
+
+// inspect returns the result of evaluating the go template format against the container
+func (r *Runtime) inspect(container, format string) (string, error) {
+	return r.output("inspect", "--format", format, container)
+}
+
+// MountSource returns the host path mounted into the container at the destination, empty when nothing is mounted there
+func (r *Runtime) MountSource(container, destination string) (string, error) {
+	return r.inspect(container, `{{range .Mounts}}{{if eq .Destination "`+destination+`"}}{{.Source}}{{end}}{{end}}`)
+}
+
+// ReadFile returns the content of the file in the container, which can be stopped
+func (r *Runtime) ReadFile(container, path string) (string, error) {
+	var out bytes.Buffer
+	if err := r.command("cp", container+":"+path, "-").SetStdout(&out).Run(); err != nil {
+		return "", errors.Wrapf(err, "failed to read %s of %s", path, container)
+	}
+	return readTarFile(&out)
+}
+
+// readTarFile returns the content of the first file of the tar archive, which is what cp writes to stdout
+func readTarFile(archive io.Reader) (string, error) {
+	reader := tar.NewReader(archive)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return "", errors.New("no file in archive")
+		}
+		if err != nil {
+			return "", err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		data, err := ioutil.ReadAll(reader)
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	}
+}
+
+// Image returns the image the container was created from
+func (r *Runtime) Image(container string) (string, error) {
+	return r.inspect(container, "{{.Config.Image}}")
+}
+
+// PortBinding is a container port published on the host
+type PortBinding struct {
+	ContainerPort int
+	Protocol      string
+	HostIP        string
+	HostPort      int
+}
+
+// PortBindings returns the ports published by the container with the host ports actually allocated by the runtime,
+// sorted by container port and protocol. Stopped containers publish no ports, so the bindings they were created
+// with are returned instead, which hold the allocated ports when the host ports were picked by KIND.
+func (r *Runtime) PortBindings(container string) ([]PortBinding, error) {
+	out, err := r.inspect(container, "{{json .NetworkSettings.Ports}}")
+	if err != nil {
+		return nil, err
+	}
+	bindings, err := parsePortBindings(out)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to decode published ports of %s", container)
+	}
+	if len(bindings) > 0 {
+		return bindings, nil
+	}
+
+	if out, err = r.inspect(container, "{{json .HostConfig.PortBindings}}"); err != nil {
+		return nil, err
+	}
+	if bindings, err = parsePortBindings(out); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode port bindings of %s", container)
+	}
+	return bindings, nil
+}
+
+// parsePortBindings decodes the port bindings of docker and podman inspect, keyed by <port>/<protocol>.
+// Bindings without a host port are left to the runtime to allocate when the container starts and are skipped.
+func parsePortBindings(out string) ([]PortBinding, error) {
+	var ports map[string][]struct {
+		HostIP   string `json:"HostIp"`
+		HostPort string `json:"HostPort"`
+	}
+	if err := json.Unmarshal([]byte(out), &ports); err != nil {
+		return nil, err
+	}
+
+	var bindings []PortBinding
+	for port, hostBindings := range ports {
+		parts := strings.SplitN(port, "/", 2)
+		containerPort, err := strconv.Atoi(parts[0])
+		if err != nil {
+			return nil, errors.Wrapf(err, "unexpected port %s", port)
+		}
+		protocol := "tcp"
+		if len(parts) == 2 {
+			protocol = parts[1]
+		}
+		for _, hostBinding := range hostBindings {
+			if hostBinding.HostPort == "" {
+				continue
+			}
+			hostPort, err := strconv.Atoi(hostBinding.HostPort)
+			if err != nil {
+				return nil, errors.Wrapf(err, "unexpected host port %s", hostBinding.HostPort)
+			}
+			bindings = append(bindings, PortBinding{
+				ContainerPort: containerPort,
+				Protocol:      protocol,
+				HostIP:        hostBinding.HostIP,
+				HostPort:      hostPort,
+			})
+		}
+	}
+
+	sort.Slice(bindings, func(i, j int) bool {
+		if bindings[i].ContainerPort != bindings[j].ContainerPort {
+			return bindings[i].ContainerPort < bindings[j].ContainerPort
+		}
+		if bindings[i].Protocol != bindings[j].Protocol {
+			return bindings[i].Protocol < bindings[j].Protocol
+		}
+		return bindings[i].HostIP < bindings[j].HostIP
+	})
+	return bindings, nil
+}
+
+// Networks returns the names of the networks the container is attached to
+func (r *Runtime) Networks(container string) ([]string, error) {
+	out, err := r.inspect(container, "{{range $name, $network := .NetworkSettings.Networks}}{{$name}} {{end}}")
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(out), nil
+}
+
+// networkInspect covers the subnet fields of the docker, podman v4 and podman v3 (CNI) network formats
+type networkInspect struct {
+	// docker
+	IPAM struct {
+		Config []struct {
+			Subnet string
+		}
+	}
+	// podman v4+
+	Subnets []struct {
+		Subnet string
+	}
+	// podman v3
+	Plugins []struct {
+		IPAM struct {
+			Ranges [][]struct {
+				Subnet string
+			}
+		}
+	}
+}
+
+// NetworkSubnets returns the subnets of the network
+func (r *Runtime) NetworkSubnets(network string) ([]string, error) {
+	out, err := r.output("network", "inspect", network)
+	if err != nil {
+		return nil, err
+	}
+	var networks []networkInspect
+	if err = json.Unmarshal([]byte(out), &networks); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode network %s", network)
+	}
+
+	var subnets []string
+	for _, n := range networks {
+		for _, config := range n.IPAM.Config {
+			subnets = append(subnets, config.Subnet)
+		}
+		for _, subnet := range n.Subnets {
+			subnets = append(subnets, subnet.Subnet)
+		}
+		for _, plugin := range n.Plugins {
+			for _, ranges := range plugin.IPAM.Ranges {
+				for _, r := range ranges {
+					subnets = append(subnets, r.Subnet)
+				}
+			}
+		}
+	}
+	return subnets, nil
+}
+
+// Running checks if the container is running
+func (r *Runtime) Running(container string) (bool, error) {
+	out, err := r.inspect(container, "{{.State.Running}}")
+	if err != nil {
+		return false, err
+	}
+	return strconv.ParseBool(out)
+}
+
+// Start starts the containers, running containers are left as is
+func (r *Runtime) Start(containers ...string) error {
+	_, err := r.output(append([]string{"start"}, containers...)...)
+	return err
+}
+
+// Stop stops the containers, stopped containers are left as is
+func (r *Runtime) Stop(containers ...string) error {
+	_, err := r.output(append([]string{"stop"}, containers...)...)
+	return err
+}
+
+const (
+	// ClusterLabelKey is the label KIND uses to find the node containers of a cluster
+	ClusterLabelKey = "io.x-k8s.kind.cluster"
+	// RoleLabelKey is the label KIND uses for the role of a node container
+	RoleLabelKey = "io.x-k8s.kind.role"
+)
+
+// NodeSpec describes a KIND node container
+type NodeSpec struct {
+	Name    string
+	Cluster string
+	Role    string
+	Image   string
+	Network string
+	// Env are KEY=VALUE pairs
+	Env []string
+	// Sysctls are KEY=VALUE pairs
+	Sysctls    []string
+	UsernsHost bool
+	// Volumes and Ports are --volume and --publish values
+	Volumes []string
+	Ports   []string
+}
+
+// NodeSettings returns the settings KIND computed for all the nodes of the cluster from one of its node containers,
+// the node specific fields are left empty. The network is the one of the cluster, node containers of peered clusters
+// are attached to more than one.
+func (r *Runtime) NodeSettings(container, network string) (*NodeSpec, error) {
+	out, err := r.inspect(container, `{{json .Config.Env}}|{{json .HostConfig.Sysctls}}|{{.HostConfig.UsernsMode}}|{{range .Mounts}}{{.Destination}},{{end}}|{{index .Config.Labels "`+ClusterLabelKey+`"}}`)
+	if err != nil {
+		return nil, err
+	}
+	fields := strings.Split(out, "|")
+	if len(fields) != 5 {
+		return nil, errors.Errorf("unexpected settings of %s: %s", container, out)
+	}
+
+	var env []string
+	if err = json.Unmarshal([]byte(fields[0]), &env); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode environment of %s", container)
+	}
+	var sysctls map[string]string
+	if err = json.Unmarshal([]byte(fields[1]), &sysctls); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode sysctls of %s", container)
+	}
+
+	spec := &NodeSpec{
+		Cluster:    fields[4],
+		Network:    network,
+		UsernsHost: fields[2] == "host",
+	}
+	// only the proxy settings are set by KIND, the rest comes from the image
+	for _, e := range env {
+		switch key := strings.SplitN(e, "=", 2)[0]; strings.ToUpper(key) {
+		case "HTTP_PROXY", "HTTPS_PROXY", "NO_PROXY":
+			spec.Env = append(spec.Env, e)
+		}
+	}
+	for key, value := range sysctls {
+		spec.Sysctls = append(spec.Sysctls, key+"="+value)
+	}
+	sort.Strings(spec.Sysctls)
+	for _, mount := range strings.Split(fields[3], ",") {
+		// docker on btrfs or zfs
+		if mount == "/dev/mapper" {
+			spec.Volumes = append(spec.Volumes, "/dev/mapper:/dev/mapper")
+		}
+	}
+	return spec, nil
+}
+
+// RunNode creates and starts a node container with the same arguments KIND uses
+// ref: https://github.com/kubernetes-sigs/kind/blob/v0.11.1/pkg/cluster/internal/providers/docker/provision.go#L220
+func (r *Runtime) RunNode(spec *NodeSpec) error {
+	args := []string{
+		"run",
+		"--hostname", spec.Name,
+		"--name", spec.Name,
+		"--label", RoleLabelKey + "=" + spec.Role,
+		"--label", ClusterLabelKey + "=" + spec.Cluster,
+		"--privileged",
+		"--tmpfs", "/tmp",
+		"--tmpfs", "/run",
+		"--volume", "/lib/modules:/lib/modules:ro",
+		"--detach",
+		"--tty",
+		"--net", spec.Network,
+	}
+	if r.binary == "podman" {
+		// podman needs a named volume to set the mount options docker uses by default
+		// ref: https://github.com/kubernetes-sigs/kind/blob/v0.11.1/pkg/cluster/internal/providers/podman/provision.go#L174
+		volume, err := r.output("volume", "create", "--label", spec.Name+"=true")
+		if err != nil {
+			return err
+		}
+		args = append(args, "--volume", volume+":/var:suid,exec,dev", "-e", "container=podman")
+	} else {
+		args = append(args,
+			"--security-opt", "seccomp=unconfined",
+			"--security-opt", "apparmor=unconfined",
+			"--volume", "/var",
+			"--restart=on-failure:1",
+			"--init=false",
+		)
+	}
+	if spec.UsernsHost {
+		args = append(args, "--userns=host")
+	}
+	for _, sysctl := range spec.Sysctls {
+		args = append(args, "--sysctl="+sysctl)
+	}
+	for _, env := range spec.Env {
+		args = append(args, "-e", env)
+	}
+	for _, volume := range spec.Volumes {
+		args = append(args, "--volume="+volume)
+	}
+	for _, port := range spec.Ports {
+		args = append(args, "--publish="+port)
+	}
+	_, err := r.output(append(args, spec.Image)...)
+	return err
+}
+
+// RemoveNode removes a node container along with its volumes
+func (r *Runtime) RemoveNode(container string) error {
+	if _, err := r.output("rm", "--force", "--volumes", container); err != nil {
+		return err
+	}
+	if r.binary != "podman" {
+		return nil
+	}
+	volumes, err := r.output("volume", "ls", "--filter", "label="+container, "--quiet")
+	if err != nil {
+		return err
+	}
+	if volumes = strings.TrimSpace(volumes); volumes == "" {
+		return nil
+	}
+	_, err = r.output(append([]string{"volume", "rm", "--force"}, strings.Fields(volumes)...)...)
+	return err
+}
+
+// CopyFromImage copies the files at the paths of the image to the directory on the host
+// without running the image
+func (r *Runtime) CopyFromImage(image, dir string, paths ...string) error {
+	id, err := r.output("create", image)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_, _ = r.output("rm", "--force", id)
+	}()
+	for _, path := range paths {
+		if _, err = r.output("cp", id+":"+path, dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CopyTo copies the file on the host to the destination in the container
+func (r *Runtime) CopyTo(container, src, dest string) error {
+	_, err := r.output("cp", src, container+":"+dest)
+	return err
+}
+
+// ImageID returns the ID of the local image
+func (r *Runtime) ImageID(image string) (string, error) {
+	return r.output("image", "inspect", "--format", "{{.Id}}", image)
+}
+
+// Pull pulls the image
+func (r *Runtime) Pull(image string) error {
+	_, err := r.output("pull", image)
+	return err
+}
+
+// Save writes the local images to an image archive
+func (r *Runtime) Save(path string, images ...string) error {
+	_, err := r.output(append([]string{"save", "-o", path}, images...)...)
+	return err
+}
+
+// Exists checks if the container exists, running or not
+func (r *Runtime) Exists(container string) bool {
+	_, err := r.inspect(container, "{{.Id}}")
+	return err == nil
+}
+
+// NetworkExists checks if the network exists
+func (r *Runtime) NetworkExists(network string) bool {
+	_, err := r.output("network", "inspect", network)
+	return err == nil
+}
+
+// ManagedNetworkLabelKey marks the networks created by the provider, which it removes once they are unused
+const ManagedNetworkLabelKey = "io.pulumi.kind.network"
+
+// NetworkSpec describes a bridge network of node containers
+type NetworkSpec struct {
+	Name       string
+	Subnet     string
+	IPv6Subnet string
+	MTU        int
+}
+
+// CreateNetwork creates a bridge network with IP masquerading like the one KIND creates, labelled as managed
+// ref: https://github.com/kubernetes-sigs/kind/blob/v0.11.1/pkg/cluster/internal/providers/docker/network.go
+func (r *Runtime) CreateNetwork(spec *NetworkSpec) error {
+	args := []string{"network", "create", "--driver=bridge", "--label", ManagedNetworkLabelKey + "=true"}
+	if r.binary != "podman" {
+		args = append(args, "--opt", "com.docker.network.bridge.enable_ip_masquerade=true")
+	}
+	if spec.MTU > 0 {
+		mtuOption := "com.docker.network.driver.mtu"
+		if r.binary == "podman" {
+			mtuOption = "mtu"
+		}
+		args = append(args, "--opt", fmt.Sprintf("%s=%d", mtuOption, spec.MTU))
+	}
+	if spec.Subnet != "" {
+		args = append(args, "--subnet", spec.Subnet)
+	}
+	if spec.IPv6Subnet != "" {
+		args = append(args, "--ipv6", "--subnet", spec.IPv6Subnet)
+	}
+	_, err := r.output(append(args, spec.Name)...)
+	return err
+}
+
+// NetworkManaged checks if the network was created by CreateNetwork
+func (r *Runtime) NetworkManaged(network string) (bool, error) {
+	out, err := r.output("network", "ls", "--filter", "label="+ManagedNetworkLabelKey+"=true", "--format", "{{.Name}}")
+	if err != nil {
+		return false, err
+	}
+	for _, name := range strings.Fields(out) {
+		if name == network {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// NetworkContainers returns the containers attached to the network, stopped ones included
+func (r *Runtime) NetworkContainers(network string) ([]string, error) {
+	out, err := r.output("ps", "--all", "--filter", "network="+network, "--format", "{{.Names}}")
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(out), nil
+}
+
+// ConnectNetwork attaches the container to the network
+func (r *Runtime) ConnectNetwork(network, container string) error {
+	_, err := r.output("network", "connect", network, container)
+	return err
+}
+
+// DisconnectNetwork detaches the container from the network
+func (r *Runtime) DisconnectNetwork(network, container string) error {
+	_, err := r.output("network", "disconnect", network, container)
+	return err
+}
+
+// NetworkAddresses returns the IPv4 and IPv6 addresses of the container on the network,
+// empty when the container is not attached to it or stopped
+func (r *Runtime) NetworkAddresses(container, network string) (string, string, error) {
+	out, err := r.inspect(container, "{{json .NetworkSettings.Networks}}")
+	if err != nil {
+		return "", "", err
+	}
+	ipv4, ipv6, err := parseNetworkAddresses(out, network)
+	if err != nil {
+		return "", "", errors.Wrapf(err, "failed to decode networks of %s", container)
+	}
+	return ipv4, ipv6, nil
+}
+
+// parseNetworkAddresses returns the addresses on the network from the networks of a container
+func parseNetworkAddresses(out, network string) (string, string, error) {
+	var networks map[string]struct {
+		IPAddress         string
+		GlobalIPv6Address string
+	}
+	if err := json.Unmarshal([]byte(out), &networks); err != nil {
+		return "", "", err
+	}
+	settings := networks[network]
+	return settings.IPAddress, settings.GlobalIPv6Address, nil
+}
+
+// RemoveNetwork removes the network
+func (r *Runtime) RemoveNetwork(network string) error {
+	_, err := r.output("network", "rm", network)
+	return err
+}
+
+// RegistrySpec describes a registry container
+type RegistrySpec struct {
+	Name    string
+	Image   string
+	Network string
+	// Port is the --publish value of the registry port
+	Port string
+}
+
+// RunRegistry creates and starts a registry container that is restarted along with the runtime
+// ref: https://kind.sigs.k8s.io/docs/user/local-registry/
+func (r *Runtime) RunRegistry(spec *RegistrySpec) error {
+	_, err := r.output("run",
+		"--detach",
+		"--restart=always",
+		"--name", spec.Name,
+		"--net", spec.Network,
+		"--publish", spec.Port,
+		spec.Image,
+	)
+	return err
+}
+
+// Remove removes the container along with its anonymous volumes
+func (r *Runtime) Remove(container string) error {
+	_, err := r.output("rm", "--force", "--volumes", container)
+	return err
+}
@@ -0,0 +1,93 @@
+package container
+
+import (
+	"archive/tar"
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestParsePortBindings(t *testing.T) {
+	out := `{"53/udp":[{"HostIp":"127.0.0.1","HostPort":"40053"}],` +
+		`"6443/tcp":[{"HostIp":"127.0.0.1","HostPort":"38211"}],` +
+		`"80/tcp":[{"HostIp":"::","HostPort":"32768"},{"HostIp":"0.0.0.0","HostPort":"32768"}],` +
+		`"8080/tcp":[{"HostIp":"","HostPort":""}]}`
+	bindings, err := parsePortBindings(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []PortBinding{
+		{ContainerPort: 53, Protocol: "udp", HostIP: "127.0.0.1", HostPort: 40053},
+		{ContainerPort: 80, Protocol: "tcp", HostIP: "0.0.0.0", HostPort: 32768},
+		{ContainerPort: 80, Protocol: "tcp", HostIP: "::", HostPort: 32768},
+		{ContainerPort: 6443, Protocol: "tcp", HostIP: "127.0.0.1", HostPort: 38211},
+	}
+	if !reflect.DeepEqual(bindings, expected) {
+		t.Errorf("expected %v, got %v", expected, bindings)
+	}
+
+	// stopped containers have no published ports
+	if bindings, err = parsePortBindings("{}"); err != nil || len(bindings) != 0 {
+		t.Errorf("expected no bindings, got %v, %v", bindings, err)
+	}
+}
+
+func TestParseNetworkAddresses(t *testing.T) {
+	// a node of a peered cluster, the peering network sorts first
+	out := `{"kind-peering":{"IPAddress":"172.30.0.2","GlobalIPv6Address":""},` +
+		`"isolated":{"IPAddress":"172.18.0.2","GlobalIPv6Address":"fc00:f853:ccd:e793::2"}}`
+	for network, expected := range map[string][2]string{
+		"isolated":     {"172.18.0.2", "fc00:f853:ccd:e793::2"},
+		"kind-peering": {"172.30.0.2", ""},
+		"other":        {"", ""},
+	} {
+		ipv4, ipv6, err := parseNetworkAddresses(out, network)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ipv4 != expected[0] || ipv6 != expected[1] {
+			t.Errorf("expected %v on %s, got %s and %s", expected, network, ipv4, ipv6)
+		}
+	}
+}
+
+func TestReadTarFile(t *testing.T) {
+	var archive bytes.Buffer
+	writer := tar.NewWriter(&archive)
+	content := "kindest/node:v1.21.1\n"
+	if err := writer.WriteHeader(&tar.Header{Name: "pulumi-upgraded-image", Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(content))}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := writer.Write([]byte(content)); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := readTarFile(&archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data != content {
+		t.Errorf("expected %q, got %q", content, data)
+	}
+	if _, err = readTarFile(&bytes.Buffer{}); err == nil {
+		t.Error("expected an empty archive to fail")
+	}
+}
+
+func TestLocalEndpoint(t *testing.T) {
+	for host, expected := range map[string]bool{
+		"unix:///var/run/docker.sock":    true,
+		"npipe:////./pipe/docker_engine": true,
+		"tcp://127.0.0.1:2375":           true,
+		"tcp://localhost:2375":           true,
+		"tcp://[::1]:2375":               true,
+		"tcp://192.168.64.2:2376":        false,
+		"ssh://user@build-host":          false,
+	} {
+		if actual := localEndpoint(host); actual != expected {
+			t.Errorf("expected %v for %s, got %v", expected, host, actual)
+		}
+	}
+}
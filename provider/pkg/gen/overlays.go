@@ -0,0 +1,906 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gen
+
+import (
+	"sort"
+
+	"github.com/pulumi/pulumi/pkg/v3/codegen/schema"
+)
+
+const (
+	clusterPeeringToken = "kind:network:ClusterPeering"
+	kindClusterToken    = "kind:cluster:Cluster"
+	loadedImageToken    = "kind:image:LoadedImage"
+	localRegistryToken  = "kind:registry:LocalRegistry"
+	nodeTypeToken       = "kind:node:Node"
+)
+
+// clusterInputOverlays are Cluster inputs handled by the provider itself
+// that are not part of the KIND cluster config
+var clusterInputOverlays = map[string]schema.PropertySpec{
+	"adoptExisting": {
+		Description: "Take over a pre-existing KIND cluster with the same name if its nodes match the config instead of failing. Default: false",
+		TypeSpec:    schema.TypeSpec{Type: "boolean"},
+	},
+	"apiServer": {
+		Description: "Configuration of the API servers rendered into kubeadm patches after kubeadmConfigPatches, with the files it reads mounted into the control plane nodes. Changes recreate the cluster",
+		TypeSpec:    schema.TypeSpec{Ref: "#/types/kind:cluster:APIServer"},
+	},
+	"hostAliases": {
+		Description: "host names resolving to IP addresses in the nodes and in the cluster, written to /etc/hosts of the nodes and to the CoreDNS config. Changes are applied in place",
+		TypeSpec: schema.TypeSpec{
+			Type:  "array",
+			Items: &schema.TypeSpec{Ref: "#/types/kind:cluster:HostAlias"},
+		},
+	},
+	"extraCACertificates": {
+		Description: "PEM encoded CA certificates, or paths of PEM files, trusted by the nodes and containerd from the moment the nodes start. Changes to the list are installed in place, changes to the content of the files are not detected",
+		TypeSpec: schema.TypeSpec{
+			Type:  "array",
+			Items: &schema.TypeSpec{Type: "string"},
+		},
+	},
+	"kubeconfigExport": {
+		Description: "Where to export the kubeconfig of the cluster: none keeps it only in the kubeconfig output, file writes it to kubeconfigPath and merge merges it into kubeconfigPath without switching the current context. Default: KIND's behaviour of merging into the default kubeconfig and switching the current context",
+		TypeSpec: schema.TypeSpec{
+			OneOf: []schema.TypeSpec{
+				{Type: "string"},
+				{Type: "string", Ref: "#/types/kind:cluster:KubeconfigExport"},
+			},
+		},
+	},
+	"kubernetesVersion": {
+		Description: "Kubernetes version like 1.21 or v1.21.1 of the nodes without an image or kubernetesVersion of their own, resolved to the node image pinned by digest for the KIND release of the provider",
+		TypeSpec:    schema.TypeSpec{Type: "string"},
+	},
+	"kubeconfigPath": {
+		Description: "Kubeconfig file used by the file and merge kubeconfig exports. Required for file. Default for merge: the default kubeconfig",
+		TypeSpec:    schema.TypeSpec{Type: "string"},
+	},
+	"kubeconfigContext": {
+		Description: "Name of the context, cluster and user entries of the cluster in the kubeconfig. Default: kind-<name>",
+		TypeSpec:    schema.TypeSpec{Type: "string"},
+	},
+	"state": {
+		Description: "Desired state of the node containers: running or stopped. Stopped clusters keep their nodes and can be started again. Default: running",
+		TypeSpec: schema.TypeSpec{
+			OneOf: []schema.TypeSpec{
+				{Type: "string"},
+				{Type: "string", Ref: "#/types/kind:cluster:ClusterState"},
+			},
+		},
+	},
+	"preloadImages": {
+		Description: "Local images or paths of local image archives imported into every node right after the cluster is created, and into new nodes",
+		TypeSpec: schema.TypeSpec{
+			Type:  "array",
+			Items: &schema.TypeSpec{Type: "string"},
+		},
+	},
+	"proxy": {
+		Description: "Proxy settings of the node containers and containerd instead of the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables of the provider. NO_PROXY is extended with the pod and service subnets, the node network and the node names. Changes are applied to containerd in place, the node containers keep the settings they were created with",
+		TypeSpec:    schema.TypeSpec{Ref: "#/types/kind:cluster:ProxySettings"},
+	},
+	"network": {
+		Description: "docker/podman network of the node containers instead of the kind network or KIND_EXPERIMENTAL_DOCKER_NETWORK, created when missing and removed with the last cluster using it if the provider created it. Changes recreate the cluster",
+		TypeSpec:    schema.TypeSpec{Ref: "#/types/kind:cluster:NetworkSettings"},
+	},
+	"registryMirrors": {
+		Description: "Mirrors of registries by registry host like docker.io, rendered into the containerd config of every node after containerdConfigPatches. Changes recreate the cluster",
+		TypeSpec: schema.TypeSpec{
+			Type:                 "object",
+			AdditionalProperties: &schema.TypeSpec{Ref: "#/types/kind:cluster:RegistryMirror"},
+		},
+	},
+	"upgradeStrategy": {
+		Description: "How node image changes are rolled out: replace recreates the cluster, inPlace upgrades the control plane and then the workers with kubeadm when all nodes move to the same version at most one minor version newer and recreates the cluster otherwise. Default: replace",
+		TypeSpec: schema.TypeSpec{
+			OneOf: []schema.TypeSpec{
+				{Type: "string"},
+				{Type: "string", Ref: "#/types/kind:cluster:UpgradeStrategy"},
+			},
+		},
+	},
+}
+
+// clusterOutputOverlays are Cluster outputs populated by the provider
+var clusterOutputOverlays = map[string]schema.PropertySpec{
+	"nodeDetails": {
+		Description: "live information of the node containers of the cluster",
+		TypeSpec: schema.TypeSpec{
+			Type:  "array",
+			Items: &schema.TypeSpec{Ref: "#/types/kind:cluster:NodeDetail"},
+		},
+	},
+	"state": {
+		Description: "live state of the node containers, stopped if any of them is not running",
+		TypeSpec:    schema.TypeSpec{Type: "string"},
+	},
+	"apiServerEndpoint": {
+		Description: "API server endpoint reachable from the host",
+		TypeSpec:    schema.TypeSpec{Type: "string"},
+	},
+	"apiServerPort": {
+		Description: "host port of the API server, resolved when networking.apiServerPort is 0",
+		TypeSpec:    schema.TypeSpec{Type: "integer"},
+	},
+	"internalApiServerEndpoint": {
+		Description: "API server endpoint reachable from containers on the cluster network",
+		TypeSpec:    schema.TypeSpec{Type: "string"},
+	},
+	"caCertificate": {
+		Description: "PEM encoded certificate authority of the cluster",
+		TypeSpec:    schema.TypeSpec{Type: "string"},
+	},
+	"networkName": {
+		Description: "name of the docker/podman network the nodes joined",
+		TypeSpec:    schema.TypeSpec{Type: "string"},
+	},
+	"networkSubnets": {
+		Description: "subnets of the docker/podman network the nodes joined",
+		TypeSpec: schema.TypeSpec{
+			Type:  "array",
+			Items: &schema.TypeSpec{Type: "string"},
+		},
+	},
+}
+
+// optionalClusterOutputs are the outputs that are not set for clusters stopped before kubeadm
+// or whose nodes are not running
+var optionalClusterOutputs = map[string]bool{
+	"apiServerEndpoint":         true,
+	"apiServerPort":             true,
+	"internalApiServerEndpoint": true,
+	"caCertificate":             true,
+	"networkName":               true,
+	"networkSubnets":            true,
+}
+
+// nodePropertyOverlays are node properties handled by the provider itself
+var nodePropertyOverlays = map[string]schema.PropertySpec{
+	"kubernetesVersion": {
+		Description: "Kubernetes version like 1.21 or v1.21.1 of the node, resolved to the node image pinned by digest for the KIND release of the provider. Conflicts with image",
+		TypeSpec:    schema.TypeSpec{Type: "string"},
+	},
+	"kubelet": {
+		Description: "kubelet settings of the node, rendered into the kubeadm config of the node for its role when it joins the cluster",
+		TypeSpec:    schema.TypeSpec{Ref: "#/types/kind:node:Kubelet"},
+	},
+	"taints": {
+		Description: "taints the node registers with, on top of the taint kubeadm gives control plane nodes",
+		TypeSpec: schema.TypeSpec{
+			Type:  "array",
+			Items: &schema.TypeSpec{Ref: "#/types/kind:node:Taint"},
+		},
+	},
+}
+
+// typeOverlays are types referenced by the overlay properties
+var typeOverlays = map[string]schema.ComplexTypeSpec{
+	"kind:cluster:UpgradeStrategy": {
+		ObjectTypeSpec: schema.ObjectTypeSpec{
+			Type: "string",
+		},
+		Enum: []schema.EnumValueSpec{
+			{
+				Name:        "Replace",
+				Value:       "replace",
+				Description: "recreate the cluster with the new node images",
+			},
+			{
+				Name:        "InPlace",
+				Value:       "inPlace",
+				Description: "upgrade the nodes with kubeadm",
+			},
+		},
+	},
+	"kind:cluster:ClusterState": {
+		ObjectTypeSpec: schema.ObjectTypeSpec{
+			Type: "string",
+		},
+		Enum: []schema.EnumValueSpec{
+			{
+				Name:        "Running",
+				Value:       "running",
+				Description: "all node containers are running",
+			},
+			{
+				Name:        "Stopped",
+				Value:       "stopped",
+				Description: "the node containers are stopped",
+			},
+		},
+	},
+	"kind:cluster:KubeconfigExport": {
+		ObjectTypeSpec: schema.ObjectTypeSpec{
+			Type: "string",
+		},
+		Enum: []schema.EnumValueSpec{
+			{
+				Name:        "None",
+				Value:       "none",
+				Description: "only keep the kubeconfig in the resource outputs",
+			},
+			{
+				Name:        "File",
+				Value:       "file",
+				Description: "write the kubeconfig to a dedicated file",
+			},
+			{
+				Name:        "Merge",
+				Value:       "merge",
+				Description: "merge the kubeconfig into a file without switching the current context",
+			},
+		},
+	},
+	"kind:cluster:APIServer": {
+		ObjectTypeSpec: schema.ObjectTypeSpec{
+			Description: "configuration of the API servers of the control plane nodes",
+			Type:        "object",
+			Properties: map[string]schema.PropertySpec{
+				"extraArgs": {
+					Description: "API server flags without the leading dashes, overriding the flags rendered from the other settings. feature-gates and runtime-config are set with the featureGates and runtimeConfig inputs",
+					TypeSpec: schema.TypeSpec{
+						Type:                 "object",
+						AdditionalProperties: &schema.TypeSpec{Type: "string"},
+					},
+				},
+				"certSANs": {
+					Description: "extra IP addresses and DNS names of the API server serving certificate",
+					TypeSpec: schema.TypeSpec{
+						Type:  "array",
+						Items: &schema.TypeSpec{Type: "string"},
+					},
+				},
+				"oidc": {
+					Description: "OpenID Connect authentication",
+					TypeSpec:    schema.TypeSpec{Ref: "#/types/kind:cluster:OIDC"},
+				},
+				"auditPolicy": {
+					Description: "audit policy YAML, enables audit logging to /var/log/kubernetes/audit/audit.log in the control plane nodes",
+					TypeSpec:    schema.TypeSpec{Type: "string"},
+				},
+				"encryptionConfig": {
+					Description: "EncryptionConfiguration YAML for the encryption of resources at rest",
+					TypeSpec:    schema.TypeSpec{Type: "string"},
+					Secret:      true,
+				},
+			},
+		},
+	},
+	"kind:node:Kubelet": {
+		ObjectTypeSpec: schema.ObjectTypeSpec{
+			Description: "kubelet settings of a node",
+			Type:        "object",
+			Properties: map[string]schema.PropertySpec{
+				"maxPods": {
+					Description: "maximum number of pods on the node",
+					TypeSpec:    schema.TypeSpec{Type: "integer"},
+				},
+				"evictionHard": {
+					Description: "hard eviction thresholds by signal like memory.available, as quantities like 100Mi or percentages like 10%",
+					TypeSpec: schema.TypeSpec{
+						Type:                 "object",
+						AdditionalProperties: &schema.TypeSpec{Type: "string"},
+					},
+				},
+				"evictionSoft": {
+					Description: "soft eviction thresholds by signal, as quantities or percentages",
+					TypeSpec: schema.TypeSpec{
+						Type:                 "object",
+						AdditionalProperties: &schema.TypeSpec{Type: "string"},
+					},
+				},
+				"evictionSoftGracePeriod": {
+					Description: "grace periods of the soft eviction thresholds by signal, as durations like 1m30s",
+					TypeSpec: schema.TypeSpec{
+						Type:                 "object",
+						AdditionalProperties: &schema.TypeSpec{Type: "string"},
+					},
+				},
+				"nodeLabels": {
+					Description: "labels the node registers with, conflicts with the labels of the node",
+					TypeSpec: schema.TypeSpec{
+						Type:                 "object",
+						AdditionalProperties: &schema.TypeSpec{Type: "string"},
+					},
+				},
+				"extraArgs": {
+					Description: "kubelet flags without the leading dashes, overriding the flags rendered from the other settings",
+					TypeSpec: schema.TypeSpec{
+						Type:                 "object",
+						AdditionalProperties: &schema.TypeSpec{Type: "string"},
+					},
+				},
+			},
+		},
+	},
+	"kind:node:Taint": {
+		ObjectTypeSpec: schema.ObjectTypeSpec{
+			Description: "taint of a node",
+			Type:        "object",
+			Properties: map[string]schema.PropertySpec{
+				"key": {
+					Description: "taint key",
+					TypeSpec:    schema.TypeSpec{Type: "string"},
+				},
+				"value": {
+					Description: "taint value",
+					TypeSpec:    schema.TypeSpec{Type: "string"},
+				},
+				"effect": {
+					Description: "NoSchedule, PreferNoSchedule or NoExecute",
+					TypeSpec:    schema.TypeSpec{Type: "string"},
+				},
+			},
+			Required: []string{"key", "effect"},
+		},
+	},
+	"kind:cluster:HostAlias": {
+		ObjectTypeSpec: schema.ObjectTypeSpec{
+			Description: "host names resolving to an IP address",
+			Type:        "object",
+			Properties: map[string]schema.PropertySpec{
+				"ip": {
+					Description: "IP address the host names resolve to",
+					TypeSpec:    schema.TypeSpec{Type: "string"},
+				},
+				"hostnames": {
+					Description: "host names resolving to the IP address",
+					TypeSpec: schema.TypeSpec{
+						Type:  "array",
+						Items: &schema.TypeSpec{Type: "string"},
+					},
+				},
+			},
+			Required: []string{"ip", "hostnames"},
+		},
+	},
+	"kind:cluster:OIDC": {
+		ObjectTypeSpec: schema.ObjectTypeSpec{
+			Description: "OpenID Connect settings of the API server",
+			Type:        "object",
+			Properties: map[string]schema.PropertySpec{
+				"issuerUrl": {
+					Description: "https URL of the provider",
+					TypeSpec:    schema.TypeSpec{Type: "string"},
+				},
+				"clientId": {
+					Description: "client ID the tokens are issued for",
+					TypeSpec:    schema.TypeSpec{Type: "string"},
+				},
+				"usernameClaim": {
+					Description: "claim used as the user name",
+					TypeSpec:    schema.TypeSpec{Type: "string"},
+				},
+				"usernamePrefix": {
+					Description: "prefix of the user names",
+					TypeSpec:    schema.TypeSpec{Type: "string"},
+				},
+				"groupsClaim": {
+					Description: "claim used as the groups of the user",
+					TypeSpec:    schema.TypeSpec{Type: "string"},
+				},
+				"groupsPrefix": {
+					Description: "prefix of the groups",
+					TypeSpec:    schema.TypeSpec{Type: "string"},
+				},
+				"requiredClaims": {
+					Description: "claims the tokens must have with the values",
+					TypeSpec: schema.TypeSpec{
+						Type:                 "object",
+						AdditionalProperties: &schema.TypeSpec{Type: "string"},
+					},
+				},
+				"signingAlgs": {
+					Description: "accepted signing algorithms",
+					TypeSpec: schema.TypeSpec{
+						Type:  "array",
+						Items: &schema.TypeSpec{Type: "string"},
+					},
+				},
+				"caCertificate": {
+					Description: "PEM encoded CA certificate of the provider, or the path of a PEM file",
+					TypeSpec:    schema.TypeSpec{Type: "string"},
+				},
+			},
+			Required: []string{"issuerUrl", "clientId"},
+		},
+	},
+	"kind:cluster:NetworkSettings": {
+		ObjectTypeSpec: schema.ObjectTypeSpec{
+			Description: "container network of the node containers",
+			Type:        "object",
+			Properties: map[string]schema.PropertySpec{
+				"name": {
+					Description: "network name",
+					TypeSpec:    schema.TypeSpec{Type: "string"},
+				},
+				"subnet": {
+					Description: "IPv4 subnet of the network when it is created, picked by the container runtime otherwise",
+					TypeSpec:    schema.TypeSpec{Type: "string"},
+				},
+				"ipv6Subnet": {
+					Description: "IPv6 subnet of the network when it is created, required for ipv6 and dual stack clusters",
+					TypeSpec:    schema.TypeSpec{Type: "string"},
+				},
+				"mtu": {
+					Description: "MTU of the network when it is created",
+					TypeSpec:    schema.TypeSpec{Type: "integer"},
+				},
+			},
+			Required: []string{"name"},
+		},
+	},
+	"kind:network:PeeredCluster": {
+		ObjectTypeSpec: schema.ObjectTypeSpec{
+			Description: "cluster attached to the peering network",
+			Type:        "object",
+			Properties: map[string]schema.PropertySpec{
+				"name": {
+					Description: "cluster name",
+					TypeSpec:    schema.TypeSpec{Type: "string"},
+				},
+				"podSubnet": {
+					Description: "pod subnet of the cluster, comma separated for dual stack clusters",
+					TypeSpec:    schema.TypeSpec{Type: "string"},
+				},
+				"serviceSubnet": {
+					Description: "service subnet of the cluster, comma separated for dual stack clusters",
+					TypeSpec:    schema.TypeSpec{Type: "string"},
+				},
+				"nodes": {
+					Description: "nodes of the cluster on the peering network",
+					TypeSpec: schema.TypeSpec{
+						Type:  "array",
+						Items: &schema.TypeSpec{Ref: "#/types/kind:network:PeeredNode"},
+					},
+				},
+			},
+			Required: []string{"name", "podSubnet", "serviceSubnet", "nodes"},
+		},
+	},
+	"kind:network:PeeredNode": {
+		ObjectTypeSpec: schema.ObjectTypeSpec{
+			Description: "node attached to the peering network",
+			Type:        "object",
+			Properties: map[string]schema.PropertySpec{
+				"name": {
+					Description: "node container name",
+					TypeSpec:    schema.TypeSpec{Type: "string"},
+				},
+				"ipv4Address": {
+					Description: "IPv4 address of the node on the peering network",
+					TypeSpec:    schema.TypeSpec{Type: "string"},
+				},
+				"ipv6Address": {
+					Description: "IPv6 address of the node on the peering network",
+					TypeSpec:    schema.TypeSpec{Type: "string"},
+				},
+				"podCidrs": {
+					Description: "pod CIDRs assigned to the node, routed via its address by the nodes of the other clusters",
+					TypeSpec: schema.TypeSpec{
+						Type:  "array",
+						Items: &schema.TypeSpec{Type: "string"},
+					},
+				},
+			},
+			Required: []string{"name"},
+		},
+	},
+	"kind:cluster:ProxySettings": {
+		ObjectTypeSpec: schema.ObjectTypeSpec{
+			Description: "proxy settings of the nodes",
+			Type:        "object",
+			Properties: map[string]schema.PropertySpec{
+				"httpProxy": {
+					Description: "proxy URL for HTTP requests",
+					TypeSpec:    schema.TypeSpec{Type: "string"},
+				},
+				"httpsProxy": {
+					Description: "proxy URL for HTTPS requests",
+					TypeSpec:    schema.TypeSpec{Type: "string"},
+				},
+				"noProxy": {
+					Description: "comma separated hosts, domains and CIDRs that are not proxied",
+					TypeSpec:    schema.TypeSpec{Type: "string"},
+				},
+			},
+		},
+	},
+	"kind:cluster:RegistryMirror": {
+		ObjectTypeSpec: schema.ObjectTypeSpec{
+			Description: "pull-through mirror of a registry",
+			Type:        "object",
+			Properties: map[string]schema.PropertySpec{
+				"endpoints": {
+					Description: "http or https URLs of the mirrors, tried in order before the registry itself",
+					TypeSpec: schema.TypeSpec{
+						Type:  "array",
+						Items: &schema.TypeSpec{Type: "string"},
+					},
+				},
+				"insecureSkipVerify": {
+					Description: "skip the TLS verification of the endpoints",
+					TypeSpec:    schema.TypeSpec{Type: "boolean"},
+				},
+				"caFile": {
+					Description: "absolute path in the nodes of the CA certificate of the endpoints, mounted with extraMounts",
+					TypeSpec:    schema.TypeSpec{Type: "string"},
+				},
+				"auth": {
+					Description: "credentials of the endpoints",
+					TypeSpec:    schema.TypeSpec{Ref: "#/types/kind:cluster:RegistryAuth"},
+				},
+			},
+			Required: []string{"endpoints"},
+		},
+	},
+	"kind:cluster:RegistryAuth": {
+		ObjectTypeSpec: schema.ObjectTypeSpec{
+			Description: "credentials containerd sends to registry mirror endpoints, either username and password or an identity token",
+			Type:        "object",
+			Properties: map[string]schema.PropertySpec{
+				"username": {
+					Description: "user name",
+					TypeSpec:    schema.TypeSpec{Type: "string"},
+				},
+				"password": {
+					Description: "password of the user",
+					TypeSpec:    schema.TypeSpec{Type: "string"},
+					Secret:      true,
+				},
+				"identityToken": {
+					Description: "identity token",
+					TypeSpec:    schema.TypeSpec{Type: "string"},
+					Secret:      true,
+				},
+			},
+		},
+	},
+	"kind:cluster:NodeDetail": {
+		ObjectTypeSpec: schema.ObjectTypeSpec{
+			Description: "live information of a KIND node container",
+			Type:        "object",
+			Properties: map[string]schema.PropertySpec{
+				"name": {
+					Description: "node container name",
+					TypeSpec:    schema.TypeSpec{Type: "string"},
+				},
+				"role": {
+					Description: "node role",
+					TypeSpec:    schema.TypeSpec{Type: "string"},
+				},
+				"image": {
+					Description: "node image the container was created from",
+					TypeSpec:    schema.TypeSpec{Type: "string"},
+				},
+				"ipv4Address": {
+					Description: "IPv4 address of the node on the KIND network",
+					TypeSpec:    schema.TypeSpec{Type: "string"},
+				},
+				"ipv6Address": {
+					Description: "IPv6 address of the node on the KIND network",
+					TypeSpec:    schema.TypeSpec{Type: "string"},
+				},
+				"portMappings": {
+					Description: "container ports published on the host with the host ports allocated by the container runtime",
+					TypeSpec: schema.TypeSpec{
+						Type:  "array",
+						Items: &schema.TypeSpec{Ref: "#/types/kind:cluster:NodePortMapping"},
+					},
+				},
+				"hostPorts": {
+					Description: "host address and port of the published container ports keyed by <containerPort>/<protocol> like 80/tcp, with the ports allocated for a hostPort of 0",
+					TypeSpec: schema.TypeSpec{
+						Type:                 "object",
+						AdditionalProperties: &schema.TypeSpec{Type: "string"},
+					},
+				},
+			},
+			Required: []string{"name", "role", "image"},
+		},
+	},
+	"kind:cluster:NodePortMapping": {
+		ObjectTypeSpec: schema.ObjectTypeSpec{
+			Description: "container port of a KIND node published on the host",
+			Type:        "object",
+			Properties: map[string]schema.PropertySpec{
+				"containerPort": {
+					Description: "port in the node container",
+					TypeSpec:    schema.TypeSpec{Type: "integer"},
+				},
+				"protocol": {
+					Description: "port protocol",
+					TypeSpec:    schema.TypeSpec{Type: "string"},
+				},
+				"listenAddress": {
+					Description: "host address the port is published on",
+					TypeSpec:    schema.TypeSpec{Type: "string"},
+				},
+				"hostPort": {
+					Description: "host port the container port is published on",
+					TypeSpec:    schema.TypeSpec{Type: "integer"},
+				},
+			},
+			Required: []string{"containerPort", "protocol", "listenAddress", "hostPort"},
+		},
+	},
+}
+
+// resourceOverlays are resources implemented by the provider that are not part of the KIND config
+var resourceOverlays = map[string]schema.ResourceSpec{
+	clusterPeeringToken: {
+		ObjectTypeSpec: schema.ObjectTypeSpec{
+			Description: "Shared docker/podman network the nodes of KIND clusters are attached to, with static routes on every node to the pod and service subnets of the other clusters",
+			Type:        "object",
+			Properties: map[string]schema.PropertySpec{
+				"clusterNames": {
+					Description: "names of the peered clusters",
+					TypeSpec: schema.TypeSpec{
+						Type:  "array",
+						Items: &schema.TypeSpec{Type: "string"},
+					},
+				},
+				"network": {
+					Description: "docker/podman network the nodes are attached to",
+					TypeSpec:    schema.TypeSpec{Type: "string"},
+				},
+				"subnet": {
+					Description: "IPv4 subnet of the network when it is created",
+					TypeSpec:    schema.TypeSpec{Type: "string"},
+				},
+				"clusters": {
+					Description: "clusters attached to the network with their subnets and nodes",
+					TypeSpec: schema.TypeSpec{
+						Type:  "array",
+						Items: &schema.TypeSpec{Ref: "#/types/kind:network:PeeredCluster"},
+					},
+				},
+			},
+			Required: []string{"clusterNames", "network"},
+		},
+		InputProperties: map[string]schema.PropertySpec{
+			"clusterNames": {
+				Description: "Names of at least two KIND clusters to peer. Their pod and service subnets, and the subnets of the network, must not overlap. Changes are applied in place",
+				TypeSpec: schema.TypeSpec{
+					Type:  "array",
+					Items: &schema.TypeSpec{Type: "string"},
+				},
+			},
+			"network": {
+				Description: "docker/podman network to attach the nodes to, created when missing and removed with the peering when it was created by the provider. Default: kind-peering",
+				TypeSpec:    schema.TypeSpec{Type: "string"},
+			},
+			"subnet": {
+				Description: "IPv4 subnet of the network when it is created, picked by the container runtime otherwise",
+				TypeSpec:    schema.TypeSpec{Type: "string"},
+			},
+		},
+		RequiredInputs: []string{"clusterNames"},
+	},
+	loadedImageToken: {
+		ObjectTypeSpec: schema.ObjectTypeSpec{
+			Description: "Local image or image archive loaded into the nodes of a KIND cluster, like kind load",
+			Type:        "object",
+			Properties: map[string]schema.PropertySpec{
+				"clusterName": {
+					Description: "cluster name",
+					TypeSpec:    schema.TypeSpec{Type: "string"},
+				},
+				"image": {
+					Description: "local image reference",
+					TypeSpec:    schema.TypeSpec{Type: "string"},
+				},
+				"archive": {
+					Description: "path of the local image archive",
+					TypeSpec:    schema.TypeSpec{Type: "string"},
+				},
+				"nodes": {
+					Description: "names of the nodes the image is loaded into",
+					TypeSpec: schema.TypeSpec{
+						Type:  "array",
+						Items: &schema.TypeSpec{Type: "string"},
+					},
+				},
+				"digest": {
+					Description: "local image ID, or the IDs of the images in the archive",
+					TypeSpec:    schema.TypeSpec{Type: "string"},
+				},
+				"loadedNodes": {
+					Description: "names of the nodes the image was loaded into",
+					TypeSpec: schema.TypeSpec{
+						Type:  "array",
+						Items: &schema.TypeSpec{Type: "string"},
+					},
+				},
+				"references": {
+					Description: "references the image is known by in the nodes",
+					TypeSpec: schema.TypeSpec{
+						Type:  "array",
+						Items: &schema.TypeSpec{Type: "string"},
+					},
+				},
+			},
+			Required: []string{"clusterName", "loadedNodes", "references"},
+		},
+		InputProperties: map[string]schema.PropertySpec{
+			"clusterName": {
+				Description: "Name of the KIND cluster to load the image into",
+				TypeSpec:    schema.TypeSpec{Type: "string"},
+			},
+			"image": {
+				Description: "Local image to load, reloaded whenever its image ID changes. Either image or archive is required",
+				TypeSpec:    schema.TypeSpec{Type: "string"},
+			},
+			"archive": {
+				Description: "Path of a local image archive to load, reloaded whenever the images in it change. Either image or archive is required",
+				TypeSpec:    schema.TypeSpec{Type: "string"},
+			},
+			"nodes": {
+				Description: "Names of the nodes to load the image into. Default: all nodes",
+				TypeSpec: schema.TypeSpec{
+					Type:  "array",
+					Items: &schema.TypeSpec{Type: "string"},
+				},
+			},
+		},
+		RequiredInputs: []string{"clusterName"},
+	},
+	localRegistryToken: {
+		ObjectTypeSpec: schema.ObjectTypeSpec{
+			Description: "Registry container on the KIND network that the nodes of KIND clusters pull the images of its host address from",
+			Type:        "object",
+			Properties: map[string]schema.PropertySpec{
+				"name": {
+					Description: "registry container name",
+					TypeSpec:    schema.TypeSpec{Type: "string"},
+				},
+				"image": {
+					Description: "registry image",
+					TypeSpec:    schema.TypeSpec{Type: "string"},
+				},
+				"hostPort": {
+					Description: "host port the registry is published on, 0 for a random port",
+					TypeSpec:    schema.TypeSpec{Type: "integer"},
+				},
+				"listenAddress": {
+					Description: "host address the registry is published on",
+					TypeSpec:    schema.TypeSpec{Type: "string"},
+				},
+				"network": {
+					Description: "docker/podman network the registry joins",
+					TypeSpec:    schema.TypeSpec{Type: "string"},
+				},
+				"clusterNames": {
+					Description: "names of the clusters using the registry",
+					TypeSpec: schema.TypeSpec{
+						Type:  "array",
+						Items: &schema.TypeSpec{Type: "string"},
+					},
+				},
+				"hostAddress": {
+					Description: "host:port of the registry on the host, images tagged with it are pulled from the registry by the nodes",
+					TypeSpec:    schema.TypeSpec{Type: "string"},
+				},
+				"clusterAddress": {
+					Description: "host:port of the registry on the KIND network",
+					TypeSpec:    schema.TypeSpec{Type: "string"},
+				},
+				"configuredClusters": {
+					Description: "names of the clusters the registry is configured in",
+					TypeSpec: schema.TypeSpec{
+						Type:  "array",
+						Items: &schema.TypeSpec{Type: "string"},
+					},
+				},
+			},
+			Required: []string{"name", "image", "hostPort", "listenAddress", "network", "hostAddress", "clusterAddress"},
+		},
+		InputProperties: map[string]schema.PropertySpec{
+			"name": {
+				Description: "Name of the registry container. Default: kind-registry",
+				TypeSpec:    schema.TypeSpec{Type: "string"},
+			},
+			"image": {
+				Description: "Registry image. Default: registry:2",
+				TypeSpec:    schema.TypeSpec{Type: "string"},
+			},
+			"hostPort": {
+				Description: "Host port to publish the registry on, 0 for a random port. Default: 5001",
+				TypeSpec:    schema.TypeSpec{Type: "integer"},
+			},
+			"listenAddress": {
+				Description: "Host address to publish the registry on. Default: 127.0.0.1",
+				TypeSpec:    schema.TypeSpec{Type: "string"},
+			},
+			"network": {
+				Description: "docker/podman network of the KIND clusters, which has to exist. Default: kind, or KIND_EXPERIMENTAL_DOCKER_NETWORK when set",
+				TypeSpec:    schema.TypeSpec{Type: "string"},
+			},
+			"clusterNames": {
+				Description: "Names of the KIND clusters whose nodes pull the images of the host address from the registry, configured with a containerd mirror and the local-registry-hosting ConfigMap",
+				TypeSpec: schema.TypeSpec{
+					Type:  "array",
+					Items: &schema.TypeSpec{Type: "string"},
+				},
+			},
+		},
+	},
+}
+
+// functionOverlays are functions implemented by the provider
+var functionOverlays = map[string]schema.FunctionSpec{
+	"kind:index:getNodeImage": {
+		Description: "Node image pinned by digest for a Kubernetes version, for the KIND release of the provider",
+		Inputs: &schema.ObjectTypeSpec{
+			Type: "object",
+			Properties: map[string]schema.PropertySpec{
+				"kubernetesVersion": {
+					Description: "Kubernetes version like 1.21 or v1.21.1. Default: the version of KIND's default node image",
+					TypeSpec:    schema.TypeSpec{Type: "string"},
+				},
+			},
+		},
+		Outputs: &schema.ObjectTypeSpec{
+			Type: "object",
+			Properties: map[string]schema.PropertySpec{
+				"image": {
+					Description: "node image reference with its digest",
+					TypeSpec:    schema.TypeSpec{Type: "string"},
+				},
+				"kubernetesVersion": {
+					Description: "exact Kubernetes version of the node image",
+					TypeSpec:    schema.TypeSpec{Type: "string"},
+				},
+			},
+			Required: []string{"image", "kubernetesVersion"},
+		},
+	},
+}
+
+// applyOverlays adds the provider specific types and properties to the generated schema
+func applyOverlays(pkg *schema.PackageSpec) {
+	for tok, typeSpec := range typeOverlays {
+		pkg.Types[tok] = typeSpec
+	}
+	for tok, resourceSpec := range resourceOverlays {
+		pkg.Resources[tok] = resourceSpec
+	}
+	for tok, functionSpec := range functionOverlays {
+		pkg.Functions[tok] = functionSpec
+	}
+	if node, ok := pkg.Types[nodeTypeToken]; ok {
+		for name, property := range nodePropertyOverlays {
+			node.Properties[name] = property
+		}
+		pkg.Types[nodeTypeToken] = node
+	}
+	if cluster, ok := pkg.Resources[kindClusterToken]; ok {
+		for name, property := range clusterInputOverlays {
+			cluster.InputProperties[name] = property
+		}
+		for name, property := range clusterOutputOverlays {
+			cluster.Properties[name] = property
+			if !optionalClusterOutputs[name] {
+				cluster.Required = append(cluster.Required, name)
+			}
+		}
+		// keep the generated schema stable
+		sort.Strings(cluster.Required)
+		pkg.Resources[kindClusterToken] = cluster
+	}
+}
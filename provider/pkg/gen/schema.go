@@ -42,6 +42,22 @@ func PulumiSchema(swagger *jsonschema.Schema) schema.PackageSpec {
 
 		Config: schema.ConfigSpec{
 			Variables: map[string]schema.PropertySpec{
+				"autonameDeterministic": {
+					TypeSpec:    schema.TypeSpec{Type: "boolean"},
+					Description: "Derive the suffix of generated cluster names from the stack and resource name instead of picking it at random. Default: false. Optional",
+				},
+				"autonameMaxLength": {
+					TypeSpec:    schema.TypeSpec{Type: "integer"},
+					Description: "Maximum length of generated cluster names, longer resource names are truncated. Default: 40. Maximum: 50. Optional",
+				},
+				"autonamePrefix": {
+					TypeSpec:    schema.TypeSpec{Type: "string"},
+					Description: "Prefix to add to generated cluster names. Optional",
+				},
+				"autonameSuffixLength": {
+					TypeSpec:    schema.TypeSpec{Type: "integer"},
+					Description: "Length of the random suffix added to generated cluster names, 0 disables the suffix. Default: 8. Optional",
+				},
 				"configFile": {
 					TypeSpec:    schema.TypeSpec{Type: "string"},
 					Description: "Kind config file to use. Optional",
@@ -54,6 +70,10 @@ func PulumiSchema(swagger *jsonschema.Schema) schema.PackageSpec {
 					TypeSpec:    schema.TypeSpec{Type: "string"},
 					Description: "Node image to use. Optional",
 				},
+				"offline": {
+					TypeSpec:    schema.TypeSpec{Type: "boolean"},
+					Description: "Never pull images. Node and registry images that are not available locally fail the preview instead. Default: false. Optional",
+				},
 				"provider": {
 					TypeSpec:    schema.TypeSpec{Type: "string"},
 					Description: "Provider to use. Supports docker/podman. Default: docker. Optional",
@@ -67,8 +87,13 @@ func PulumiSchema(swagger *jsonschema.Schema) schema.PackageSpec {
 					Description: "Stop before running kubeadm commands. This would need the user to manually retrieve the Kubeconfig. Default: false. Optional",
 				},
 				"waitForNodeReady": {
-					TypeSpec:    schema.TypeSpec{Type: "integer"},
-					Description: "Time in seconds to wait for nodes to become ready. Default: none. Optional",
+					TypeSpec: schema.TypeSpec{
+						OneOf: []schema.TypeSpec{
+							{Type: "integer"},
+							{Type: "string"},
+						},
+					},
+					Description: "Time to wait for nodes to become ready, either in seconds or as a duration like 90s or 5m. Default: none. Optional",
 				},
 			},
 		},
@@ -78,6 +103,22 @@ func PulumiSchema(swagger *jsonschema.Schema) schema.PackageSpec {
 				Type:        "object",
 			},
 			InputProperties: map[string]schema.PropertySpec{
+				"autonameDeterministic": {
+					TypeSpec:    schema.TypeSpec{Type: "boolean"},
+					Description: "Derive the suffix of generated cluster names from the stack and resource name instead of picking it at random. Default: false. Optional",
+				},
+				"autonameMaxLength": {
+					TypeSpec:    schema.TypeSpec{Type: "integer"},
+					Description: "Maximum length of generated cluster names, longer resource names are truncated. Default: 40. Maximum: 50. Optional",
+				},
+				"autonamePrefix": {
+					TypeSpec:    schema.TypeSpec{Type: "string"},
+					Description: "Prefix to add to generated cluster names. Optional",
+				},
+				"autonameSuffixLength": {
+					TypeSpec:    schema.TypeSpec{Type: "integer"},
+					Description: "Length of the random suffix added to generated cluster names, 0 disables the suffix. Default: 8. Optional",
+				},
 				"configFile": {
 					TypeSpec:    schema.TypeSpec{Type: "string"},
 					Description: "Kind config file to use. Optional",
@@ -90,6 +131,10 @@ func PulumiSchema(swagger *jsonschema.Schema) schema.PackageSpec {
 					TypeSpec:    schema.TypeSpec{Type: "string"},
 					Description: "Node image to use. Optional",
 				},
+				"offline": {
+					TypeSpec:    schema.TypeSpec{Type: "boolean"},
+					Description: "Never pull images. Node and registry images that are not available locally fail the preview instead. Default: false. Optional",
+				},
 				"provider": {
 					TypeSpec:    schema.TypeSpec{Type: "string"},
 					Description: "Provider to use. Supports docker/podman. Default: docker. Optional",
@@ -103,8 +148,13 @@ func PulumiSchema(swagger *jsonschema.Schema) schema.PackageSpec {
 					Description: "Stop before running kubeadm commands. This would need the user to manually retrieve the Kubeconfig. Default: false. Optional",
 				},
 				"waitForNodeReady": {
-					TypeSpec:    schema.TypeSpec{Type: "integer"},
-					Description: "Time in seconds to wait for nodes to become ready. Default: none. Optional",
+					TypeSpec: schema.TypeSpec{
+						OneOf: []schema.TypeSpec{
+							{Type: "integer"},
+							{Type: "string"},
+						},
+					},
+					Description: "Time to wait for nodes to become ready, either in seconds or as a duration like 90s or 5m. Default: none. Optional",
 				},
 			},
 		},
@@ -239,6 +289,13 @@ func PulumiSchema(swagger *jsonschema.Schema) schema.PackageSpec {
 
 	}
 
+	applyOverlays(&pkg)
+
+	for tok := range resourceOverlays {
+		module := strings.Split(tok, ":")[1]
+		pkgImportAliases[fmt.Sprintf("%s/%s", goImportPath, module)] = module
+	}
+
 	pkg.Language["go"] = rawMessage(map[string]interface{}{
 		"importBasePath":                 goImportPath,
 		"packageImportAliases":           pkgImportAliases,
@@ -0,0 +1,329 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Copied and modified from https://github.com/kubernetes-sigs/kind/tree/v0.11.1/pkg/cluster/internal/kubeconfig/internal/kubeconfig
+// since kind only exposes exporting to the default context names and always switches the current context
+package kubeconfig
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v3"
+	kubeyaml "sigs.k8s.io/yaml"
+)
+
+const kubeconfigEnv = "KUBECONFIG"
+
+// Config represents a KUBECONFIG, with only the fields needed to manage entries
+// Other fields are handled as unstructured data purely read for writing back
+// to disk via the OtherFields field
+type Config struct {
+	Clusters       []NamedCluster         `yaml:"clusters,omitempty"`
+	Users          []NamedUser            `yaml:"users,omitempty"`
+	Contexts       []NamedContext         `yaml:"contexts,omitempty"`
+	CurrentContext string                 `yaml:"current-context,omitempty"`
+	OtherFields    map[string]interface{} `yaml:",inline,omitempty"`
+}
+
+// NamedCluster relates nicknames to cluster information
+type NamedCluster struct {
+	Name    string                 `yaml:"name"`
+	Cluster map[string]interface{} `yaml:"cluster"`
+}
+
+// NamedUser relates nicknames to user information
+type NamedUser struct {
+	Name string                 `yaml:"name"`
+	User map[string]interface{} `yaml:"user"`
+}
+
+// NamedContext relates nicknames to context information
+type NamedContext struct {
+	Name    string  `yaml:"name"`
+	Context Context `yaml:"context"`
+}
+
+// Context is a tuple of references to a cluster and a user
+type Context struct {
+	Cluster     string                 `yaml:"cluster"`
+	User        string                 `yaml:"user"`
+	OtherFields map[string]interface{} `yaml:",inline,omitempty"`
+}
+
+// Parse decodes the kubeconfig content
+func Parse(content string) (*Config, error) {
+	cfg := &Config{}
+	if err := yaml.Unmarshal([]byte(content), cfg); err != nil {
+		return nil, errors.Wrap(err, "failed to decode KUBECONFIG")
+	}
+	return cfg, nil
+}
+
+// Encode encodes the cfg to yaml normalized with kubernetes's yaml library
+// to keep the diffs minimal when modifying kubeconfig files
+func (cfg *Config) Encode() (string, error) {
+	encoded, err := yaml.Marshal(cfg)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to encode KUBECONFIG")
+	}
+	var unstructured interface{}
+	if err = kubeyaml.Unmarshal(encoded, &unstructured); err != nil {
+		return "", errors.Wrap(err, "failed to normalize KUBECONFIG encoding")
+	}
+	encoded, err = kubeyaml.Marshal(&unstructured)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to normalize KUBECONFIG encoding")
+	}
+	// special case: don't write anything when empty
+	if bytes.Equal(encoded, []byte("{}\n")) {
+		return "", nil
+	}
+	return string(encoded), nil
+}
+
+// Rename renames the cluster, user and context of a single cluster kubeconfig like
+// the ones generated by KIND to name and makes it the current context
+func Rename(content, name string) (string, error) {
+	cfg, err := Parse(content)
+	if err != nil {
+		return "", err
+	}
+	if len(cfg.Clusters) != 1 || len(cfg.Users) != 1 || len(cfg.Contexts) != 1 {
+		return "", errors.Errorf("expected a kubeconfig with a single cluster, user and context, got %d clusters, %d users and %d contexts",
+			len(cfg.Clusters), len(cfg.Users), len(cfg.Contexts))
+	}
+	cfg.Clusters[0].Name = name
+	cfg.Users[0].Name = name
+	cfg.Contexts[0].Name = name
+	cfg.Contexts[0].Context.Cluster = name
+	cfg.Contexts[0].Context.User = name
+	cfg.CurrentContext = name
+	return cfg.Encode()
+}
+
+// DefaultPath returns the kubeconfig file kubectl would merge new entries into
+// ref: https://github.com/kubernetes-sigs/kind/blob/v0.11.1/pkg/cluster/internal/kubeconfig/internal/kubeconfig/paths.go#L58
+func DefaultPath() string {
+	var paths []string
+	for _, path := range filepath.SplitList(os.Getenv(kubeconfigEnv)) {
+		if path != "" {
+			paths = append(paths, path)
+		}
+	}
+	if len(paths) == 0 {
+		return filepath.Join(homeDir(), ".kube", "config")
+	}
+	for _, path := range paths {
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+	}
+	return paths[len(paths)-1]
+}
+
+func homeDir() string {
+	if runtime.GOOS == "windows" {
+		if home := os.Getenv("USERPROFILE"); home != "" {
+			return home
+		}
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return home
+}
+
+// WriteFile replaces the file at path with content
+func WriteFile(path, content string) error {
+	// NOTE: 0755 / 0600 are to match client-go
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return errors.Wrap(err, "failed to create directory for KUBECONFIG")
+	}
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		return errors.Wrap(err, "failed to write KUBECONFIG")
+	}
+	return nil
+}
+
+// read loads the kubeconfig file at path, returning an empty config if it does not exist
+func read(path string) (*Config, error) {
+	content, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &Config{}, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read KUBECONFIG")
+	}
+	return Parse(string(content))
+}
+
+// Merge adds or replaces the entries from content into the file at path.
+// The current context of the file is left untouched unless it is not set.
+func Merge(path, content string) error {
+	return withLock(path, func() error {
+		existing, err := read(path)
+		if err != nil {
+			return err
+		}
+		cfg, err := Parse(content)
+		if err != nil {
+			return err
+		}
+
+		for _, c := range cfg.Clusters {
+			existing.removeCluster(c.Name)
+			existing.Clusters = append(existing.Clusters, c)
+		}
+		for _, u := range cfg.Users {
+			existing.removeUser(u.Name)
+			existing.Users = append(existing.Users, u)
+		}
+		for _, c := range cfg.Contexts {
+			existing.removeContext(c.Name)
+			existing.Contexts = append(existing.Contexts, c)
+		}
+		if existing.CurrentContext == "" {
+			existing.CurrentContext = cfg.CurrentContext
+		}
+
+		encoded, err := existing.Encode()
+		if err != nil {
+			return err
+		}
+		return WriteFile(path, encoded)
+	})
+}
+
+// Remove drops the cluster, user and context entries named name from the file at path.
+// The file itself is removed if no contexts are left and removeIfEmpty is set.
+func Remove(path, name string, removeIfEmpty bool) error {
+	return withLock(path, func() error {
+		existing, err := read(path)
+		if err != nil {
+			return err
+		}
+
+		mutated := existing.removeCluster(name)
+		mutated = existing.removeUser(name) || mutated
+		mutated = existing.removeContext(name) || mutated
+		if existing.CurrentContext == name {
+			existing.CurrentContext = ""
+			mutated = true
+		}
+
+		if removeIfEmpty && len(existing.Contexts) == 0 {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				return errors.Wrap(err, "failed to remove KUBECONFIG")
+			}
+			return nil
+		}
+		if !mutated {
+			return nil
+		}
+		encoded, err := existing.Encode()
+		if err != nil {
+			return err
+		}
+		return WriteFile(path, encoded)
+	})
+}
+
+func (cfg *Config) removeCluster(name string) bool {
+	kept := cfg.Clusters[:0]
+	for _, c := range cfg.Clusters {
+		if c.Name != name {
+			kept = append(kept, c)
+		}
+	}
+	removed := len(kept) != len(cfg.Clusters)
+	cfg.Clusters = kept
+	return removed
+}
+
+func (cfg *Config) removeUser(name string) bool {
+	kept := cfg.Users[:0]
+	for _, u := range cfg.Users {
+		if u.Name != name {
+			kept = append(kept, u)
+		}
+	}
+	removed := len(kept) != len(cfg.Users)
+	cfg.Users = kept
+	return removed
+}
+
+func (cfg *Config) removeContext(name string) bool {
+	kept := cfg.Contexts[:0]
+	for _, c := range cfg.Contexts {
+		if c.Name != name {
+			kept = append(kept, c)
+		}
+	}
+	removed := len(kept) != len(cfg.Contexts)
+	cfg.Contexts = kept
+	return removed
+}
+
+// withLock runs f while holding the same lock file kind and client-go use for kubeconfig files
+// ref: https://github.com/kubernetes-sigs/kind/blob/v0.11.1/pkg/cluster/internal/kubeconfig/internal/kubeconfig/lock.go
+func withLock(path string, f func() error) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return errors.Wrap(err, "failed to create directory for KUBECONFIG")
+	}
+	lockPath := path + ".lock"
+	lock, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL, 0)
+	if err != nil {
+		return errors.Wrapf(err, "failed to lock KUBECONFIG, remove %s if no other process is using it", lockPath)
+	}
+	_ = lock.Close()
+	defer func() {
+		_ = os.Remove(lockPath)
+	}()
+	return f()
+}
+
+// CurrentCluster returns the server and the PEM encoded certificate authority
+// of the cluster referenced by the current context
+func CurrentCluster(content string) (string, string, error) {
+	cfg, err := Parse(content)
+	if err != nil {
+		return "", "", err
+	}
+	clusterName := ""
+	for _, c := range cfg.Contexts {
+		if c.Name == cfg.CurrentContext {
+			clusterName = c.Context.Cluster
+		}
+	}
+	for _, c := range cfg.Clusters {
+		if c.Name != clusterName {
+			continue
+		}
+		server, _ := c.Cluster["server"].(string)
+		caData, _ := c.Cluster["certificate-authority-data"].(string)
+		ca, err := base64.StdEncoding.DecodeString(caData)
+		if err != nil {
+			return "", "", errors.Wrap(err, "failed to decode certificate authority data")
+		}
+		return server, string(ca), nil
+	}
+	return "", "", errors.Errorf("no cluster found for current context %q", cfg.CurrentContext)
+}
@@ -0,0 +1,114 @@
+package kubeconfig
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+const existingKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    server: https://prod.example.com
+  name: prod
+contexts:
+- context:
+    cluster: prod
+    user: prod
+  name: prod
+current-context: prod
+users:
+- name: prod
+  user:
+    token: secret
+`
+
+const kindKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    certificate-authority-data: Y2E=
+    server: https://127.0.0.1:40000
+  name: kind-dev
+contexts:
+- context:
+    cluster: kind-dev
+    user: kind-dev
+  name: kind-dev
+current-context: kind-dev
+users:
+- name: kind-dev
+  user:
+    client-certificate-data: Y2VydA==
+`
+
+func TestMergeAndRemove(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	if err := WriteFile(path, existingKubeconfig); err != nil {
+		t.Fatal(err)
+	}
+
+	renamed, err := Rename(kindKubeconfig, "dev")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = Merge(path, renamed); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := mustRead(t, path)
+	if cfg.CurrentContext != "prod" {
+		t.Errorf("expected current context to stay prod, got: %s", cfg.CurrentContext)
+	}
+	if len(cfg.Contexts) != 2 || cfg.Contexts[1].Name != "dev" || cfg.Contexts[1].Context.User != "dev" {
+		t.Errorf("expected dev context to be merged, got: %+v", cfg.Contexts)
+	}
+
+	if err = Remove(path, "dev", true); err != nil {
+		t.Fatal(err)
+	}
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err = Parse(string(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Clusters) != 1 || len(cfg.Users) != 1 || len(cfg.Contexts) != 1 || cfg.Contexts[0].Name != "prod" {
+		t.Errorf("expected only the prod entries to be left, got: %s", content)
+	}
+	if token := cfg.Users[0].User["token"]; token != "secret" {
+		t.Errorf("expected prod user to be preserved, got: %v", cfg.Users[0].User)
+	}
+
+	if err = Remove(path, "prod", true); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = ioutil.ReadFile(path); err == nil {
+		t.Error("expected kubeconfig without contexts to be removed")
+	}
+}
+
+func mustRead(t *testing.T, path string) *Config {
+	t.Helper()
+	cfg, err := read(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cfg
+}
+
+func TestCurrentCluster(t *testing.T) {
+	server, ca, err := CurrentCluster(kindKubeconfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if server != "https://127.0.0.1:40000" {
+		t.Errorf("expected kind-dev server, got: %s", server)
+	}
+	if ca != "ca" {
+		t.Errorf("expected decoded certificate authority, got: %s", ca)
+	}
+}
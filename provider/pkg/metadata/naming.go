@@ -17,20 +17,61 @@ package metadata
 
 import (
 	"fmt"
+	"hash/fnv"
 	"math/rand"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
-	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
 	"sigs.k8s.io/kind/pkg/apis/config/v1alpha4"
 )
 
+const (
+	// DefaultAutonameSuffixLength is the length of the random suffix appended to autonamed clusters
+	DefaultAutonameSuffixLength = 8
+	// DefaultAutonameMaxLength leaves enough room for the longest node container
+	// name kind generates (`<name>-external-load-balancer`) to still be a valid hostname
+	DefaultAutonameMaxLength = 40
+	// MaxClusterNameLength is the cluster name length above which kind warns that the
+	// name is probably too long to work on some systems, autonamed clusters stay below it
+	// ref: https://github.com/kubernetes-sigs/kind/blob/v0.11.1/pkg/cluster/internal/create/create.go#L48
+	MaxClusterNameLength = 50
+)
+
 var dns1123Alphabet = []rune("abcdefghijklmnopqrstuvwxyz0123456789")
 
+// validClusterNameRE is the cluster name validation used by kind
+// ref: https://github.com/kubernetes-sigs/kind/blob/v0.11.1/pkg/internal/apis/config/validate.go#L32
+var validClusterNameRE = regexp.MustCompile(`^[a-z0-9.-]+$`)
+
+var invalidDNS1123RE = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// AutonameOptions controls how names are generated for clusters that are not explicitly named
+type AutonameOptions struct {
+	// Prefix is prepended to the generated name
+	Prefix string
+	// SuffixLength is the length of the random suffix, 0 disables the suffix
+	SuffixLength int
+	// MaxLength is the maximum length of the generated name
+	MaxLength int
+	// Deterministic derives the suffix from the resource URN so the same
+	// resource in the same stack always gets the same name
+	Deterministic bool
+}
+
+// DefaultAutonameOptions returns the autoname options used when the provider is not configured otherwise
+func DefaultAutonameOptions() AutonameOptions {
+	return AutonameOptions{
+		SuffixLength: DefaultAutonameSuffixLength,
+		MaxLength:    DefaultAutonameMaxLength,
+	}
+}
+
 // AssignNameIfAutonamable generates a name for an object. Uses DNS-1123-compliant characters.
-func AssignNameIfAutonamable(obj map[string]interface{}, propMap resource.PropertyMap, base tokens.QName) {
-	contract.Assert(base != "")
+func AssignNameIfAutonamable(obj map[string]interface{}, propMap resource.PropertyMap, urn resource.URN, opts AutonameOptions) {
+	contract.Assert(urn.Name() != "")
 
 	// Check if the name is set and is a computed value. If so, do not auto-name.
 	if name, ok := propMap["name"]; ok && name.IsComputed() {
@@ -38,10 +79,65 @@ func AssignNameIfAutonamable(obj map[string]interface{}, propMap resource.Proper
 	}
 
 	if name, ok := obj["name"]; !ok || name.(string) == "" {
-		obj["name"] = fmt.Sprintf("%s-%s", base, randString(8))
+		obj["name"] = GenerateName(urn, opts)
 	}
 }
 
+// GenerateName builds a DNS-1123 label from the prefix, the sanitized resource name and a random suffix,
+// truncating the resource name so that the result fits in the configured maximum length.
+func GenerateName(urn resource.URN, opts AutonameOptions) string {
+	maxLength := opts.MaxLength
+	if maxLength <= 0 || maxLength > MaxClusterNameLength {
+		maxLength = MaxClusterNameLength
+	}
+
+	suffix := ""
+	if opts.SuffixLength > 0 {
+		seed := ""
+		if opts.Deterministic {
+			seed = string(urn)
+		}
+		suffix = "-" + randString(opts.SuffixLength, seed)
+	}
+
+	name := sanitizeDNS1123(opts.Prefix + string(urn.Name()))
+	if available := maxLength - len(suffix); len(name) > available {
+		if available < 0 {
+			available = 0
+		}
+		name = strings.TrimRight(name[:available], "-")
+	}
+	if name == "" {
+		// nothing left of the base, drop the separator of the suffix too
+		return strings.TrimPrefix(suffix, "-")
+	}
+	return name + suffix
+}
+
+// ValidateClusterName checks if the name meets kind's cluster name constraints
+func ValidateClusterName(name string) error {
+	if !validClusterNameRE.MatchString(name) {
+		return fmt.Errorf("%q is not a valid cluster name, cluster names must match `%s`", name, validClusterNameRE.String())
+	}
+	return nil
+}
+
+// ClusterNameLengthWarning returns kind's warning for names longer than MaxClusterNameLength, empty otherwise
+func ClusterNameLengthWarning(name string) string {
+	if len(name) <= MaxClusterNameLength {
+		return ""
+	}
+	return fmt.Sprintf("cluster name %q is %d characters long, kind warns that names longer than %d characters might not work properly on some systems",
+		name, len(name), MaxClusterNameLength)
+}
+
+// sanitizeDNS1123 lower cases the name, replaces every run of invalid characters
+// with a single `-` and trims leading and trailing dashes
+func sanitizeDNS1123(name string) string {
+	name = invalidDNS1123RE.ReplaceAllString(strings.ToLower(name), "-")
+	return strings.Trim(name, "-")
+}
+
 // AdoptOldAutonameIfUnnamed checks if `newObj` has a name, and if not, "adopts" the name of `oldObj`
 // instead. If `oldObj` was autonamed, then we mark `newObj` as autonamed, too.
 func AdoptOldAutonameIfUnnamed(newObj map[string]interface{}, oldObj *v1alpha4.Cluster) {
@@ -50,11 +146,20 @@ func AdoptOldAutonameIfUnnamed(newObj map[string]interface{}, oldObj *v1alpha4.C
 		newObj["name"] = oldObj.Name
 	}
 }
-func randString(n int) string {
+
+func randString(n int, seed string) string {
+	// nolint:gosec
+	intn := rand.Intn
+	if seed != "" {
+		h := fnv.New64a()
+		_, err := h.Write([]byte(seed))
+		contract.AssertNoError(err)
+		// nolint:gosec
+		intn = rand.New(rand.NewSource(int64(h.Sum64()))).Intn
+	}
 	b := make([]rune, n)
 	for i := range b {
-		// nolint:gosec
-		b[i] = dns1123Alphabet[rand.Intn(len(dns1123Alphabet))]
+		b[i] = dns1123Alphabet[intn(len(dns1123Alphabet))]
 	}
 	return string(b)
 }
@@ -0,0 +1,61 @@
+package metadata
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+func TestGenerateName(t *testing.T) {
+	urn := resource.NewURN("dev", "project", "", "kind:cluster:Cluster", "My_Really.Long-Resource-Name-For-A-Cluster")
+
+	name := GenerateName(urn, DefaultAutonameOptions())
+	if len(name) > DefaultAutonameMaxLength {
+		t.Errorf("expected name to be at most %d characters, got: %s", DefaultAutonameMaxLength, name)
+	}
+	if !strings.HasPrefix(name, "my-really-long-resource-name-fo-") {
+		t.Errorf("expected sanitized and truncated name, got: %s", name)
+	}
+	if err := ValidateClusterName(name); err != nil {
+		t.Error(err)
+	}
+
+	name = GenerateName(urn, AutonameOptions{Prefix: "ci-", MaxLength: 12})
+	if name != "ci-my-really" {
+		t.Errorf("expected: ci-my-really, got: %s", name)
+	}
+
+	deterministic := AutonameOptions{SuffixLength: 5, MaxLength: 20, Deterministic: true}
+	if a, b := GenerateName(urn, deterministic), GenerateName(urn, deterministic); a != b {
+		t.Errorf("expected deterministic names to match, got: %s and %s", a, b)
+	}
+	otherStack := resource.NewURN("prod", "project", "", "kind:cluster:Cluster", "My_Really.Long-Resource-Name-For-A-Cluster")
+	if a, b := GenerateName(urn, deterministic), GenerateName(otherStack, deterministic); a == b {
+		t.Errorf("expected names in different stacks to differ, got: %s", a)
+	}
+}
+
+func TestValidateClusterName(t *testing.T) {
+	for _, name := range []string{"kind", "kind.example-1"} {
+		if err := ValidateClusterName(name); err != nil {
+			t.Errorf("expected %s to be valid, got: %v", name, err)
+		}
+	}
+	for _, name := range []string{"Kind", "kind_example"} {
+		if err := ValidateClusterName(name); err == nil {
+			t.Errorf("expected %s to be invalid", name)
+		}
+	}
+	// kind only warns about long names
+	long := strings.Repeat("k", MaxClusterNameLength+1)
+	if err := ValidateClusterName(long); err != nil {
+		t.Errorf("expected %s to be valid, got: %v", long, err)
+	}
+	if ClusterNameLengthWarning(long) == "" {
+		t.Errorf("expected a warning for %s", long)
+	}
+	if warning := ClusterNameLengthWarning("kind"); warning != "" {
+		t.Errorf("expected no warning for kind, got: %s", warning)
+	}
+}
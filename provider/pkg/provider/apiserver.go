@@ -0,0 +1,345 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	rpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+	"sigs.k8s.io/kind/pkg/apis/config/v1alpha4"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// apiServerFilesPath is where the files of the API server are mounted in the control plane nodes and the API server
+	apiServerFilesPath = "/etc/kubernetes/pulumi-kind"
+	// auditLogPath is the directory of the audit log in the control plane nodes
+	auditLogPath = "/var/log/kubernetes/audit"
+
+	auditPolicyFile      = "audit-policy.yaml"
+	encryptionConfigFile = "encryption-config.yaml"
+	oidcCAFile           = "oidc-ca.crt"
+)
+
+// dnsNameRE matches DNS names and wildcard DNS names
+var dnsNameRE = regexp.MustCompile(`^(\*\.)?[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+
+// apiServerConfig is the configuration of the API servers of the control plane nodes
+type apiServerConfig struct {
+	// ExtraArgs are API server flags without the leading dashes
+	ExtraArgs map[string]string `json:"extraArgs,omitempty"`
+	// CertSANs are added to the ones KIND sets on the serving certificate
+	CertSANs []string `json:"certSANs,omitempty"`
+	// OIDC configures OpenID Connect authentication
+	OIDC *oidcConfig `json:"oidc,omitempty"`
+	// AuditPolicy is the content of an audit policy, which enables audit logging
+	AuditPolicy string `json:"auditPolicy,omitempty"`
+	// EncryptionConfig is the content of an EncryptionConfiguration for encryption at rest
+	EncryptionConfig string `json:"encryptionConfig,omitempty"`
+}
+
+// oidcConfig are the OpenID Connect flags of the API server
+// ref: https://kubernetes.io/docs/reference/access-authn-authz/authentication/#configuring-the-api-server
+type oidcConfig struct {
+	IssuerURL      string            `json:"issuerUrl,omitempty"`
+	ClientID       string            `json:"clientId,omitempty"`
+	UsernameClaim  string            `json:"usernameClaim,omitempty"`
+	UsernamePrefix string            `json:"usernamePrefix,omitempty"`
+	GroupsClaim    string            `json:"groupsClaim,omitempty"`
+	GroupsPrefix   string            `json:"groupsPrefix,omitempty"`
+	RequiredClaims map[string]string `json:"requiredClaims,omitempty"`
+	SigningAlgs    []string          `json:"signingAlgs,omitempty"`
+	// CACertificate is a PEM certificate or the path of a PEM file
+	CACertificate string `json:"caCertificate,omitempty"`
+}
+
+// managedAPIServerArgs are the API server flags set from other inputs
+var managedAPIServerArgs = map[string]string{
+	"feature-gates":  "featureGates",
+	"runtime-config": "runtimeConfig",
+}
+
+// apiServerFilesDir is the host directory of the files of the API server mounted into the control plane nodes
+func apiServerFilesDir(dataDir string) string {
+	return filepath.Join(dataDir, "apiserver")
+}
+
+// hasFiles checks if the API server reads any files of the config
+func (a *apiServerConfig) hasFiles() bool {
+	return a.AuditPolicy != "" || a.EncryptionConfig != "" || (a.OIDC != nil && a.OIDC.CACertificate != "")
+}
+
+// files returns the files the API server reads by name
+func (a *apiServerConfig) files() (map[string][]byte, error) {
+	files := map[string][]byte{}
+	if a.AuditPolicy != "" {
+		files[auditPolicyFile] = []byte(a.AuditPolicy)
+	}
+	if a.EncryptionConfig != "" {
+		files[encryptionConfigFile] = []byte(a.EncryptionConfig)
+	}
+	if a.OIDC != nil && a.OIDC.CACertificate != "" {
+		ca, err := readCACertificate(a.OIDC.CACertificate)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read OIDC CA certificate")
+		}
+		files[oidcCAFile] = ca
+	}
+	return files, nil
+}
+
+// args returns the API server flags of the config, the extra args override the rendered ones
+func (a *apiServerConfig) args() map[string]string {
+	args := map[string]string{}
+	if a.AuditPolicy != "" {
+		args["audit-policy-file"] = path.Join(apiServerFilesPath, auditPolicyFile)
+		args["audit-log-path"] = path.Join(auditLogPath, "audit.log")
+	}
+	if a.EncryptionConfig != "" {
+		args["encryption-provider-config"] = path.Join(apiServerFilesPath, encryptionConfigFile)
+	}
+	if oidc := a.OIDC; oidc != nil {
+		for flag, value := range map[string]string{
+			"oidc-issuer-url":      oidc.IssuerURL,
+			"oidc-client-id":       oidc.ClientID,
+			"oidc-username-claim":  oidc.UsernameClaim,
+			"oidc-username-prefix": oidc.UsernamePrefix,
+			"oidc-groups-claim":    oidc.GroupsClaim,
+			"oidc-groups-prefix":   oidc.GroupsPrefix,
+			"oidc-signing-algs":    strings.Join(oidc.SigningAlgs, ","),
+		} {
+			if value != "" {
+				args[flag] = value
+			}
+		}
+		if oidc.CACertificate != "" {
+			args["oidc-ca-file"] = path.Join(apiServerFilesPath, oidcCAFile)
+		}
+		if len(oidc.RequiredClaims) > 0 {
+			claims := make([]string, 0, len(oidc.RequiredClaims))
+			for claim, value := range oidc.RequiredClaims {
+				claims = append(claims, claim+"="+value)
+			}
+			sort.Strings(claims)
+			args["oidc-required-claim"] = strings.Join(claims, ",")
+		}
+	}
+	for flag, value := range a.ExtraArgs {
+		args[flag] = value
+	}
+	return args
+}
+
+// kubeadmPatches renders the config as a merge patch of the kubeadm ClusterConfiguration,
+// and the certificate SANs as JSON patches as merge patches would replace the ones of KIND
+// ref: https://github.com/kubernetes-sigs/kind/blob/v0.11.1/pkg/cluster/internal/kubeadm/config.go#L304
+func (a *apiServerConfig) kubeadmPatches() (string, []v1alpha4.PatchJSON6902, error) {
+	apiServer := map[string]interface{}{}
+	if args := a.args(); len(args) > 0 {
+		apiServer["extraArgs"] = args
+	}
+	var volumes []map[string]interface{}
+	if a.hasFiles() {
+		volumes = append(volumes, map[string]interface{}{
+			"name":      "pulumi-kind",
+			"hostPath":  apiServerFilesPath,
+			"mountPath": apiServerFilesPath,
+			"readOnly":  true,
+			"pathType":  "Directory",
+		})
+	}
+	if a.AuditPolicy != "" {
+		volumes = append(volumes, map[string]interface{}{
+			"name":      "audit-log",
+			"hostPath":  auditLogPath,
+			"mountPath": auditLogPath,
+			"pathType":  "DirectoryOrCreate",
+		})
+	}
+	if len(volumes) > 0 {
+		apiServer["extraVolumes"] = volumes
+	}
+
+	var patch string
+	if len(apiServer) > 0 {
+		data, err := yaml.Marshal(map[string]interface{}{
+			"kind":      "ClusterConfiguration",
+			"apiServer": apiServer,
+		})
+		if err != nil {
+			return "", nil, err
+		}
+		patch = string(data)
+	}
+
+	var jsonPatches []v1alpha4.PatchJSON6902
+	if len(a.CertSANs) > 0 {
+		var ops []map[string]interface{}
+		for _, san := range a.CertSANs {
+			ops = append(ops, map[string]interface{}{"op": "add", "path": "/apiServer/certSANs/-", "value": san})
+		}
+		data, err := json.Marshal(ops)
+		if err != nil {
+			return "", nil, err
+		}
+		jsonPatches = append(jsonPatches, v1alpha4.PatchJSON6902{Kind: "ClusterConfiguration", Patch: string(data)})
+	}
+	return patch, jsonPatches, nil
+}
+
+// applyAPIServer renders the API server config into kubeadm patches of the cluster
+func applyAPIServer(config *v1alpha4.Cluster, apiServer *apiServerConfig) error {
+	if apiServer == nil {
+		return nil
+	}
+	patch, jsonPatches, err := apiServer.kubeadmPatches()
+	if err != nil {
+		return errors.Wrap(err, "failed to render the apiServer kubeadm patches")
+	}
+	if patch != "" {
+		config.KubeadmConfigPatches = append(config.KubeadmConfigPatches, patch)
+	}
+	config.KubeadmConfigPatchesJSON6902 = append(config.KubeadmConfigPatchesJSON6902, jsonPatches...)
+	return nil
+}
+
+// mountAPIServerFiles mounts the host directory of the files of the API server into the control plane nodes
+func mountAPIServerFiles(config *v1alpha4.Cluster, apiServer *apiServerConfig, dataDir string) {
+	if apiServer == nil || !apiServer.hasFiles() {
+		return
+	}
+	config.Nodes = desiredNodes(config)
+	for i := range config.Nodes {
+		if config.Nodes[i].Role == v1alpha4.ControlPlaneRole {
+			config.Nodes[i].ExtraMounts = append(config.Nodes[i].ExtraMounts,
+				v1alpha4.Mount{HostPath: apiServerFilesDir(dataDir), ContainerPath: apiServerFilesPath, Readonly: true})
+		}
+	}
+}
+
+// writeAPIServerFiles writes the files of the API server to the host directory mounted into the control plane nodes
+func writeAPIServerFiles(dataDir string, apiServer *apiServerConfig) error {
+	if apiServer == nil {
+		return nil
+	}
+	files, err := apiServer.files()
+	if err != nil || len(files) == 0 {
+		return err
+	}
+	dir := apiServerFilesDir(dataDir)
+	if err = os.MkdirAll(dir, 0755); err != nil {
+		return errors.Wrapf(err, "failed to create API server files directory %s", dir)
+	}
+	for name, data := range files {
+		// the encryption config holds the keys for the data at rest
+		if err = ioutil.WriteFile(filepath.Join(dir, name), data, 0600); err != nil {
+			return errors.Wrapf(err, "failed to write API server file %s", name)
+		}
+	}
+	return nil
+}
+
+// checkAPIServer validates the known API server config
+func checkAPIServer(news resource.PropertyMap) []*rpc.CheckFailure {
+	apiServer := news["apiServer"]
+	if !apiServer.IsObject() {
+		return nil
+	}
+	settings := apiServer.ObjectValue()
+	var failures []*rpc.CheckFailure
+	fail := func(property, reason string, args ...interface{}) {
+		failures = append(failures, &rpc.CheckFailure{Property: "apiServer." + property, Reason: fmt.Sprintf(reason, args...)})
+	}
+
+	if extraArgs := settings["extraArgs"]; extraArgs.IsObject() {
+		for _, flag := range extraArgs.ObjectValue().StableKeys() {
+			switch {
+			case strings.HasPrefix(string(flag), "-"):
+				fail(fmt.Sprintf("extraArgs[%q]", flag), "flags are set without the leading dashes")
+			case managedAPIServerArgs[string(flag)] != "":
+				fail(fmt.Sprintf("extraArgs[%q]", flag), "%s is set by KIND, use the %s input instead", flag, managedAPIServerArgs[string(flag)])
+			}
+		}
+	}
+
+	if sans := settings["certSANs"]; sans.IsArray() {
+		for i, san := range sans.ArrayValue() {
+			if san.IsString() && net.ParseIP(san.StringValue()) == nil && !dnsNameRE.MatchString(san.StringValue()) {
+				fail(fmt.Sprintf("certSANs[%d]", i), "%q is neither an IP address nor a DNS name", san.StringValue())
+			}
+		}
+	}
+
+	if oidc := settings["oidc"]; oidc.IsObject() {
+		oidcSettings := oidc.ObjectValue()
+		switch issuer := oidcSettings["issuerUrl"]; {
+		case issuer.IsString():
+			if u, err := url.Parse(issuer.StringValue()); err != nil || u.Scheme != "https" || u.Host == "" {
+				fail("oidc.issuerUrl", "%q is not an https URL", issuer.StringValue())
+			}
+		case !issuer.IsComputed() && !issuer.IsOutput():
+			fail("oidc.issuerUrl", "issuerUrl is required")
+		}
+		if clientID := oidcSettings["clientId"]; clientID.IsNull() {
+			fail("oidc.clientId", "clientId is required")
+		}
+		if ca := oidcSettings["caCertificate"]; ca.IsString() {
+			if _, err := readCACertificate(ca.StringValue()); err != nil {
+				fail("oidc.caCertificate", err.Error())
+			}
+		}
+	}
+
+	for property, expected := range map[resource.PropertyKey]string{
+		"auditPolicy":      "Policy",
+		"encryptionConfig": "EncryptionConfiguration",
+	} {
+		if content := settings[property]; content.IsString() {
+			if err := checkManifestKind(content.StringValue(), expected); err != nil {
+				fail(string(property), err.Error())
+			}
+		}
+	}
+	// map iteration order
+	sort.Slice(failures, func(i, j int) bool { return failures[i].Property < failures[j].Property })
+	return failures
+}
+
+// checkManifestKind makes sure the YAML manifest is of the kind
+func checkManifestKind(manifest, kind string) error {
+	var typeMeta struct {
+		APIVersion string `json:"apiVersion"`
+		Kind       string `json:"kind"`
+	}
+	if err := yaml.Unmarshal([]byte(manifest), &typeMeta); err != nil {
+		return errors.Wrap(err, "invalid YAML")
+	}
+	if typeMeta.Kind != kind || typeMeta.APIVersion == "" {
+		return errors.Errorf("expected a %s manifest with an apiVersion, got kind %q", kind, typeMeta.Kind)
+	}
+	return nil
+}
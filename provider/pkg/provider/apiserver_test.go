@@ -0,0 +1,96 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"sigs.k8s.io/kind/pkg/apis/config/v1alpha4"
+)
+
+func TestAPIServerKubeadmPatches(t *testing.T) {
+	apiServer := &apiServerConfig{
+		ExtraArgs: map[string]string{"enable-admission-plugins": "NodeRestriction", "audit-log-maxage": "7"},
+		CertSANs:  []string{"api.example.com", "10.0.0.1"},
+		OIDC: &oidcConfig{
+			IssuerURL:      "https://issuer.example.com",
+			ClientID:       "kubernetes",
+			RequiredClaims: map[string]string{"b": "2", "a": "1"},
+		},
+		AuditPolicy: "apiVersion: audit.k8s.io/v1\nkind: Policy\n",
+	}
+	patch, jsonPatches, err := apiServer.kubeadmPatches()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `apiServer:
+  extraArgs:
+    audit-log-maxage: "7"
+    audit-log-path: /var/log/kubernetes/audit/audit.log
+    audit-policy-file: /etc/kubernetes/pulumi-kind/audit-policy.yaml
+    enable-admission-plugins: NodeRestriction
+    oidc-client-id: kubernetes
+    oidc-issuer-url: https://issuer.example.com
+    oidc-required-claim: a=1,b=2
+  extraVolumes:
+  - hostPath: /etc/kubernetes/pulumi-kind
+    mountPath: /etc/kubernetes/pulumi-kind
+    name: pulumi-kind
+    pathType: Directory
+    readOnly: true
+  - hostPath: /var/log/kubernetes/audit
+    mountPath: /var/log/kubernetes/audit
+    name: audit-log
+    pathType: DirectoryOrCreate
+kind: ClusterConfiguration
+`
+	if patch != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, patch)
+	}
+	expectedJSON := `[{"op":"add","path":"/apiServer/certSANs/-","value":"api.example.com"},{"op":"add","path":"/apiServer/certSANs/-","value":"10.0.0.1"}]`
+	if len(jsonPatches) != 1 || jsonPatches[0].Kind != "ClusterConfiguration" || jsonPatches[0].Patch != expectedJSON {
+		t.Errorf("unexpected JSON patches %v", jsonPatches)
+	}
+
+	config := &v1alpha4.Cluster{Name: "dev", Nodes: []v1alpha4.Node{{Role: v1alpha4.ControlPlaneRole}, {Role: v1alpha4.WorkerRole}}}
+	if err = applyAPIServer(config, apiServer); err != nil {
+		t.Fatal(err)
+	}
+	if len(config.Nodes[0].ExtraMounts) != 0 {
+		t.Errorf("expected the host directory to be left out of the KIND config, got %v", config.Nodes)
+	}
+	mountAPIServerFiles(config, apiServer, "/data/dev")
+	if len(config.Nodes[0].ExtraMounts) != 1 || config.Nodes[0].ExtraMounts[0].HostPath != apiServerFilesDir("/data/dev") || len(config.Nodes[1].ExtraMounts) != 0 {
+		t.Errorf("expected the files to be mounted into the control plane only, got %v", config.Nodes)
+	}
+}
+
+func TestCheckAPIServer(t *testing.T) {
+	news := resource.NewPropertyMapFromMap(map[string]interface{}{
+		"apiServer": map[string]interface{}{
+			"extraArgs": map[string]interface{}{"--v": "4", "feature-gates": "A=true", "v": "4"},
+			"certSANs":  []interface{}{"api.example.com", "10.0.0.1", "not a name"},
+			"oidc": map[string]interface{}{
+				"issuerUrl": "http://issuer.example.com",
+			},
+			"auditPolicy":      "kind: Policy",
+			"encryptionConfig": "apiVersion: apiserver.config.k8s.io/v1\nkind: EncryptionConfiguration\n",
+		},
+	})
+	expected := []string{
+		"apiServer.auditPolicy",
+		"apiServer.certSANs[2]",
+		`apiServer.extraArgs["--v"]`,
+		`apiServer.extraArgs["feature-gates"]`,
+		"apiServer.oidc.clientId",
+		"apiServer.oidc.issuerUrl",
+	}
+	failures := checkAPIServer(news)
+	if len(failures) != len(expected) {
+		t.Fatalf("expected %d failures, got %v", len(expected), failures)
+	}
+	for i, failure := range failures {
+		if failure.Property != expected[i] {
+			t.Errorf("expected failure on %s, got %s", expected[i], failure.Property)
+		}
+	}
+}
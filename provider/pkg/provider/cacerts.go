@@ -0,0 +1,208 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/frezbo/pulumi-provider-kind/provider/v3/pkg/container"
+	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	pulumilog "github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+	rpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+	"sigs.k8s.io/kind/pkg/apis/config/v1alpha4"
+	"sigs.k8s.io/kind/pkg/cluster"
+)
+
+const (
+	// caCertificatesPath is where update-ca-certificates picks up local certificates in the node images
+	caCertificatesPath = "/usr/local/share/ca-certificates/pulumi-kind"
+	// caCertificatesGoPath is a directory Go programs like containerd and the kubelet load certificates from
+	// on top of the system bundle, which trusts the certificates from the moment the nodes start
+	// ref: https://github.com/golang/go/blob/go1.16/src/crypto/x509/root_linux.go
+	caCertificatesGoPath = "/etc/pki/tls/certs"
+)
+
+// defaultClusterDataDir is the host directory of files of new clusters mounted into their nodes,
+// which has to stay around for as long as the nodes do
+func defaultClusterDataDir(clusterName string) string {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		base = os.TempDir()
+	}
+	return filepath.Join(base, "pulumi-kind", clusterName)
+}
+
+// clusterDataDir returns the host directory of files mounted into the nodes of the cluster. It depends on the
+// user running the provider when the cluster was created, so it's taken from the mounts of existing nodes.
+func (k *kindProvider) clusterDataDir(provider *cluster.Provider, clusterName string) string {
+	internalNodes, err := selectNodes(provider, clusterName, nil)
+	if err != nil || len(internalNodes) == 0 {
+		return defaultClusterDataDir(clusterName)
+	}
+	source, err := container.NewRuntime(k.opts.Provider).MountSource(internalNodes[0].String(), caCertificatesPath)
+	if err != nil || source == "" {
+		return defaultClusterDataDir(clusterName)
+	}
+	return filepath.Dir(source)
+}
+
+// removeClusterData removes the host directory of files mounted into the nodes of the cluster
+func removeClusterData(dataDir string) error {
+	return os.RemoveAll(dataDir)
+}
+
+// caCertificatesMounted checks if the host directory of the extra CA certificates is mounted into the nodes,
+// which is not the case for clusters created by older versions of the provider without certificates
+func (k *kindProvider) caCertificatesMounted(provider *cluster.Provider, clusterName string) bool {
+	internalNodes, err := selectNodes(provider, clusterName, nil)
+	if err != nil {
+		return false
+	}
+	runtime := container.NewRuntime(k.opts.Provider)
+	for _, node := range internalNodes {
+		if source, err := runtime.MountSource(node.String(), caCertificatesPath); err != nil || source == "" {
+			return false
+		}
+	}
+	return len(internalNodes) > 0
+}
+
+// caCertificatesDir is the host directory of the extra CA certificates of the cluster mounted into the nodes
+func caCertificatesDir(dataDir string) string {
+	return filepath.Join(dataDir, "ca-certificates")
+}
+
+// readCACertificate returns the PEM certificates of the entry, either PEM data or the path of a PEM file
+func readCACertificate(entry string) ([]byte, error) {
+	data := []byte(entry)
+	if !strings.Contains(entry, "-----BEGIN") {
+		var err error
+		if data, err = ioutil.ReadFile(entry); err != nil {
+			return nil, errors.Wrapf(err, "failed to read CA certificate %s", entry)
+		}
+	}
+
+	found := false
+	for rest := data; ; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+			return nil, errors.Wrap(err, "invalid CA certificate")
+		}
+		found = true
+	}
+	if !found {
+		return nil, errors.New("no PEM encoded certificate found")
+	}
+	return data, nil
+}
+
+// mountCACertificates mounts the host directory of the extra CA certificates into every node. It's mounted
+// even without certificates, so they can be added to the nodes later on.
+func mountCACertificates(config *v1alpha4.Cluster, dataDir string) {
+	dir := caCertificatesDir(dataDir)
+	config.Nodes = desiredNodes(config)
+	for i := range config.Nodes {
+		config.Nodes[i].ExtraMounts = append(config.Nodes[i].ExtraMounts,
+			v1alpha4.Mount{HostPath: dir, ContainerPath: caCertificatesPath, Readonly: true},
+			v1alpha4.Mount{HostPath: dir, ContainerPath: caCertificatesGoPath, Readonly: true},
+		)
+	}
+}
+
+// writeCACertificates replaces the certificates in the host directory mounted into the nodes
+func writeCACertificates(dataDir string, certificates []string) error {
+	dir := caCertificatesDir(dataDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return errors.Wrapf(err, "failed to create CA certificates directory %s", dir)
+	}
+	previous, err := filepath.Glob(filepath.Join(dir, "*.crt"))
+	if err != nil {
+		return err
+	}
+	for _, file := range previous {
+		if err = os.Remove(file); err != nil {
+			return errors.Wrapf(err, "failed to remove CA certificate %s", file)
+		}
+	}
+	for i, entry := range certificates {
+		data, err := readCACertificate(entry)
+		if err != nil {
+			return err
+		}
+		// update-ca-certificates only picks up .crt files
+		file := filepath.Join(dir, fmt.Sprintf("pulumi-kind-%d.crt", i))
+		if err = ioutil.WriteFile(file, data, 0644); err != nil {
+			return errors.Wrapf(err, "failed to write CA certificate %s", file)
+		}
+	}
+	return nil
+}
+
+// installCACertificates adds the mounted certificates to the trust store of the nodes, restarting
+// containerd picks up certificates that changed since it started
+func installCACertificates(provider *cluster.Provider, clusterName string, restartContainerd bool) error {
+	internalNodes, err := selectNodes(provider, clusterName, nil)
+	if err != nil {
+		return err
+	}
+	for _, node := range internalNodes {
+		pulumilog.V(3).Infof("installing CA certificates on node %s", node.String())
+		if err = node.Command("update-ca-certificates", "--fresh").Run(); err != nil {
+			return errors.Wrapf(err, "failed to update CA certificates on %s", node.String())
+		}
+		if !restartContainerd {
+			continue
+		}
+		if err = node.Command("systemctl", "restart", "containerd").Run(); err != nil {
+			return errors.Wrapf(err, "failed to restart containerd on %s", node.String())
+		}
+	}
+	return nil
+}
+
+// checkExtraCACertificates makes sure the known extra CA certificates are PEM certificates or PEM files
+func checkExtraCACertificates(news resource.PropertyMap) []*rpc.CheckFailure {
+	certificates := news["extraCACertificates"]
+	if !certificates.IsArray() {
+		return nil
+	}
+	var failures []*rpc.CheckFailure
+	for i, certificate := range certificates.ArrayValue() {
+		if !certificate.IsString() {
+			continue
+		}
+		if _, err := readCACertificate(certificate.StringValue()); err != nil {
+			failures = append(failures, &rpc.CheckFailure{
+				Property: fmt.Sprintf("extraCACertificates[%d]", i),
+				Reason:   err.Error(),
+			})
+		}
+	}
+	return failures
+}
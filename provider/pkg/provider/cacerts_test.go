@@ -0,0 +1,93 @@
+package provider
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"sigs.k8s.io/kind/pkg/apis/config/v1alpha4"
+)
+
+func testCACertificate(t *testing.T) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "pulumi-kind test CA"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func TestReadCACertificate(t *testing.T) {
+	certificate := testCACertificate(t)
+	file := filepath.Join(t.TempDir(), "ca.pem")
+	if err := ioutil.WriteFile(file, []byte(certificate), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		entry string
+		fails bool
+	}{
+		{"PEM", certificate, false},
+		{"file", file, false},
+		{"missing file", filepath.Join(t.TempDir(), "missing.pem"), true},
+		{"not a certificate", "-----BEGIN CERTIFICATE-----\nbm90IGEgY2VydA==\n-----END CERTIFICATE-----\n", true},
+		{"no certificate", "-----BEGIN PUBLIC KEY-----\n-----END PUBLIC KEY-----\n", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := readCACertificate(tt.entry); tt.fails != (err != nil) {
+				t.Errorf("expected failure %v, got %v", tt.fails, err)
+			}
+		})
+	}
+}
+
+func TestMountCACertificates(t *testing.T) {
+	config := &v1alpha4.Cluster{Name: "dev"}
+	mountCACertificates(config, "/data/dev")
+	if len(config.Nodes) != 1 {
+		t.Fatalf("expected the default node, got %v", config.Nodes)
+	}
+	mounts := config.Nodes[0].ExtraMounts
+	if len(mounts) != 2 || mounts[0].HostPath != caCertificatesDir("/data/dev") || mounts[0].ContainerPath != caCertificatesPath || !mounts[1].Readonly {
+		t.Errorf("unexpected mounts %v", mounts)
+	}
+}
+
+func TestExtraCACertificatesNotDiffed(t *testing.T) {
+	inputs := map[string]interface{}{"name": "dev"}
+	olds, err := propMapToKindClusterConfig(inputs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	inputs["extraCACertificates"] = []interface{}{testCACertificate(t)}
+	news, err := propMapToKindClusterConfig(inputs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(olds, news) {
+		t.Errorf("expected the certificates to be left out of the KIND config, got %v", news)
+	}
+}
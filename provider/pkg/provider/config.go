@@ -0,0 +1,258 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/frezbo/pulumi-provider-kind/provider/v3/pkg/metadata"
+	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	rpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+)
+
+// configVariablePrefix is the prefix the engine adds to every provider config variable
+// when it is passed using the legacy `variables` map
+const configVariablePrefix = "kind:config:"
+
+var dns1123PrefixRE = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// configDecoder decodes the provider configuration from a property map
+// collecting every malformed value as a check failure on the offending key
+type configDecoder struct {
+	props    resource.PropertyMap
+	failures []*rpc.CheckFailure
+	// unknown is set when at least one config value is not known yet, which can only happen during preview
+	unknown bool
+}
+
+func newConfigDecoder(props resource.PropertyMap) *configDecoder {
+	return &configDecoder{props: props}
+}
+
+// configVariablesToPropertyMap converts the legacy string variables map sent by older engines
+// into a property map so that both representations are decoded the same way
+func configVariablesToPropertyMap(vars map[string]string) resource.PropertyMap {
+	props := resource.PropertyMap{}
+	for key, value := range vars {
+		props[resource.PropertyKey(strings.TrimPrefix(key, configVariablePrefix))] = resource.NewStringProperty(value)
+	}
+	return props
+}
+
+func (d *configDecoder) fail(key, reason string) {
+	d.failures = append(d.failures, &rpc.CheckFailure{
+		Property: key,
+		Reason:   reason,
+	})
+}
+
+// value returns the plain value for key unwrapping secrets and outputs.
+// The second return value is false if the value is not set or is not known yet.
+func (d *configDecoder) value(key string) (resource.PropertyValue, bool) {
+	v, ok := d.props[resource.PropertyKey(key)]
+	for ok {
+		switch {
+		case v.IsSecret():
+			v = v.SecretValue().Element
+		case v.IsOutput():
+			if !v.OutputValue().Known {
+				d.unknown = true
+				return resource.PropertyValue{}, false
+			}
+			v = v.OutputValue().Element
+		case v.IsComputed():
+			d.unknown = true
+			return resource.PropertyValue{}, false
+		case v.IsNull():
+			return resource.PropertyValue{}, false
+		default:
+			return v, true
+		}
+	}
+	return resource.PropertyValue{}, false
+}
+
+func (d *configDecoder) stringValue(key, defaultValue string) string {
+	v, ok := d.value(key)
+	if !ok {
+		return defaultValue
+	}
+	if !v.IsString() {
+		d.fail(key, fmt.Sprintf("expected a string, got %s", v.TypeString()))
+		return defaultValue
+	}
+	return v.StringValue()
+}
+
+func (d *configDecoder) boolValue(key string, defaultValue bool) bool {
+	v, ok := d.value(key)
+	if !ok {
+		return defaultValue
+	}
+	switch {
+	case v.IsBool():
+		return v.BoolValue()
+	case v.IsString():
+		b, err := strconv.ParseBool(v.StringValue())
+		if err != nil {
+			d.fail(key, fmt.Sprintf("expected a boolean, got %q", v.StringValue()))
+			return defaultValue
+		}
+		return b
+	default:
+		d.fail(key, fmt.Sprintf("expected a boolean, got %s", v.TypeString()))
+		return defaultValue
+	}
+}
+
+func (d *configDecoder) intValue(key string, defaultValue int) int {
+	v, ok := d.value(key)
+	if !ok {
+		return defaultValue
+	}
+	switch {
+	case v.IsNumber() && v.NumberValue() == math.Trunc(v.NumberValue()):
+		return int(v.NumberValue())
+	case v.IsString():
+		i, err := strconv.Atoi(strings.TrimSpace(v.StringValue()))
+		if err != nil {
+			d.fail(key, fmt.Sprintf("expected an integer, got %q", v.StringValue()))
+			return defaultValue
+		}
+		return i
+	default:
+		d.fail(key, fmt.Sprintf("expected an integer, got %v", v))
+		return defaultValue
+	}
+}
+
+// durationValue accepts either an integer number of seconds or a Go duration string like "90s" or "5m"
+func (d *configDecoder) durationValue(key string, defaultValue time.Duration) time.Duration {
+	v, ok := d.value(key)
+	if !ok {
+		return defaultValue
+	}
+	duration, err := parseDuration(v)
+	if err != nil {
+		d.fail(key, err.Error())
+		return defaultValue
+	}
+	return duration
+}
+
+func parseDuration(v resource.PropertyValue) (time.Duration, error) {
+	var duration time.Duration
+	switch {
+	case v.IsNumber():
+		seconds := v.NumberValue()
+		if seconds != math.Trunc(seconds) {
+			return 0, errors.Errorf("expected a whole number of seconds, got %v", seconds)
+		}
+		duration = time.Duration(seconds) * time.Second
+	case v.IsString():
+		s := strings.TrimSpace(v.StringValue())
+		if seconds, err := strconv.Atoi(s); err == nil {
+			duration = time.Duration(seconds) * time.Second
+		} else {
+			parsed, err := time.ParseDuration(s)
+			if err != nil {
+				return 0, errors.Errorf("expected a duration like \"90s\" or \"5m\" or a number of seconds, got %q", s)
+			}
+			duration = parsed
+		}
+	default:
+		return 0, errors.Errorf("expected a duration or a number of seconds, got %s", v.TypeString())
+	}
+	if duration < 0 {
+		return 0, errors.Errorf("duration must not be negative, got %s", duration)
+	}
+	return duration, nil
+}
+
+// inPlaceConfigKeys are the provider settings that don't apply to existing clusters: offline only
+// affects pulling images and autonamed clusters keep their names as long as they are not replaced
+var inPlaceConfigKeys = map[string]bool{
+	"offline":               true,
+	"autonamePrefix":        true,
+	"autonameSuffixLength":  true,
+	"autonameMaxLength":     true,
+	"autonameDeterministic": true,
+}
+
+// decodeKindCreateOpts decodes the provider configuration into kind create options, applying defaults
+// for anything not set. Malformed values are recorded as failures on the decoder.
+func (d *configDecoder) decodeKindCreateOpts() kindCreateOpts {
+	opts := kindCreateOpts{
+		ConfigFile:           d.stringValue("configFile", ""),
+		KubeconfigFile:       d.stringValue("kubeconfigFile", ""),
+		NodeImage:            d.stringValue("nodeImage", ""),
+		Offline:              d.boolValue("offline", false),
+		Provider:             d.stringValue("provider", kindDefaultProvider),
+		RetainNodesOnFailure: d.boolValue("retainNodesOnFailure", false),
+		StopBeforeSettingK8s: d.boolValue("stopBeforeSettingK8s", false),
+		WaitForNodeReady:     d.durationValue("waitForNodeReady", 0*time.Second),
+	}
+
+	switch opts.Provider {
+	case kindDockerProvider, kindPodmanProvider:
+	case "":
+		opts.Provider = kindDefaultProvider
+	default:
+		d.fail("provider", "Valid provider values are docker/podman")
+	}
+
+	return opts
+}
+
+// decodeAutonameOptions decodes the options used to generate names for clusters without an explicit name
+func (d *configDecoder) decodeAutonameOptions() metadata.AutonameOptions {
+	opts := metadata.AutonameOptions{
+		Prefix:        d.stringValue("autonamePrefix", ""),
+		SuffixLength:  d.intValue("autonameSuffixLength", metadata.DefaultAutonameSuffixLength),
+		MaxLength:     d.intValue("autonameMaxLength", metadata.DefaultAutonameMaxLength),
+		Deterministic: d.boolValue("autonameDeterministic", false),
+	}
+
+	if opts.Prefix != "" && !dns1123PrefixRE.MatchString(opts.Prefix) {
+		d.fail("autonamePrefix", fmt.Sprintf("%q must only contain lower case alphanumeric characters or '-' and start with an alphanumeric character", opts.Prefix))
+	}
+	if opts.SuffixLength < 0 {
+		d.fail("autonameSuffixLength", fmt.Sprintf("must not be negative, got %d", opts.SuffixLength))
+	}
+	if opts.MaxLength <= opts.SuffixLength || opts.MaxLength > metadata.MaxClusterNameLength {
+		d.fail("autonameMaxLength", fmt.Sprintf("must be greater than autonameSuffixLength (%d) and at most %d, got %d",
+			opts.SuffixLength, metadata.MaxClusterNameLength, opts.MaxLength))
+	}
+
+	return opts
+}
+
+// err returns all the collected failures as a single error or nil if there are none
+func (d *configDecoder) err() error {
+	if len(d.failures) == 0 {
+		return nil
+	}
+	reasons := make([]string, 0, len(d.failures))
+	for _, failure := range d.failures {
+		reasons = append(reasons, fmt.Sprintf("%s: %s", failure.Property, failure.Reason))
+	}
+	return errors.Errorf("invalid provider configuration: %s", strings.Join(reasons, "; "))
+}
@@ -0,0 +1,152 @@
+package provider
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	rpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+)
+
+func TestDecodeKindCreateOpts(t *testing.T) {
+	tests := []struct {
+		name     string
+		props    resource.PropertyMap
+		expected kindCreateOpts
+		failures []string
+		unknown  bool
+	}{
+		{
+			name:     "defaults",
+			props:    resource.PropertyMap{},
+			expected: kindCreateOpts{Provider: kindDefaultProvider},
+		},
+		{
+			name: "typed values",
+			props: resource.NewPropertyMapFromMap(map[string]interface{}{
+				"provider":             "podman",
+				"retainNodesOnFailure": true,
+				"waitForNodeReady":     90,
+				"offline":              true,
+			}),
+			expected: kindCreateOpts{Provider: kindPodmanProvider, RetainNodesOnFailure: true, WaitForNodeReady: 90 * time.Second, Offline: true},
+		},
+		{
+			name: "legacy string variables",
+			props: configVariablesToPropertyMap(map[string]string{
+				"kind:config:stopBeforeSettingK8s": "true",
+				"kind:config:waitForNodeReady":     "120",
+			}),
+			expected: kindCreateOpts{Provider: kindDefaultProvider, StopBeforeSettingK8s: true, WaitForNodeReady: 2 * time.Minute},
+		},
+		{
+			name: "duration string",
+			props: resource.NewPropertyMapFromMap(map[string]interface{}{
+				"waitForNodeReady": "5m",
+			}),
+			expected: kindCreateOpts{Provider: kindDefaultProvider, WaitForNodeReady: 5 * time.Minute},
+		},
+		{
+			name: "secret value",
+			props: resource.PropertyMap{
+				"nodeImage": resource.MakeSecret(resource.NewStringProperty("kindest/node:v1.21.1")),
+			},
+			expected: kindCreateOpts{Provider: kindDefaultProvider, NodeImage: "kindest/node:v1.21.1"},
+		},
+		{
+			name: "unknown value",
+			props: resource.PropertyMap{
+				"nodeImage": resource.MakeComputed(resource.NewStringProperty("")),
+			},
+			expected: kindCreateOpts{Provider: kindDefaultProvider},
+			unknown:  true,
+		},
+		{
+			name: "malformed values",
+			props: resource.NewPropertyMapFromMap(map[string]interface{}{
+				"provider":             "containerd",
+				"retainNodesOnFailure": "yes please",
+				"waitForNodeReady":     "soon",
+			}),
+			expected: kindCreateOpts{Provider: "containerd"},
+			failures: []string{"retainNodesOnFailure", "waitForNodeReady", "provider"},
+		},
+		{
+			name: "negative duration",
+			props: resource.NewPropertyMapFromMap(map[string]interface{}{
+				"waitForNodeReady": "-1m",
+			}),
+			expected: kindCreateOpts{Provider: kindDefaultProvider},
+			failures: []string{"waitForNodeReady"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoder := newConfigDecoder(tt.props)
+			opts := decoder.decodeKindCreateOpts()
+			if opts != tt.expected {
+				t.Errorf("expected: %+v, got: %+v", tt.expected, opts)
+			}
+			if decoder.unknown != tt.unknown {
+				t.Errorf("expected unknown: %v, got: %v", tt.unknown, decoder.unknown)
+			}
+			if len(decoder.failures) != len(tt.failures) {
+				t.Fatalf("expected failures on %v, got: %v", tt.failures, decoder.failures)
+			}
+			for i, failure := range decoder.failures {
+				if failure.Property != tt.failures[i] {
+					t.Errorf("expected failure on %s, got: %s", tt.failures[i], failure.Property)
+				}
+			}
+		})
+	}
+}
+
+func TestDiffConfig(t *testing.T) {
+	k := &kindProvider{}
+	olds := resource.NewPropertyMapFromMap(map[string]interface{}{"provider": "docker", "offline": false})
+	tests := []struct {
+		name     string
+		news     map[string]interface{}
+		replaces bool
+		diffs    []string
+	}{
+		{name: "same", news: map[string]interface{}{"provider": "docker", "offline": false}},
+		{
+			name:  "offline and autonaming",
+			news:  map[string]interface{}{"provider": "docker", "offline": true, "autonamePrefix": "dev"},
+			diffs: []string{"autonamePrefix", "offline"},
+		},
+		{
+			name:     "provider",
+			news:     map[string]interface{}{"provider": "podman", "offline": true},
+			replaces: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldStruct, err := plugin.MarshalProperties(olds, plugin.MarshalOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			newStruct, err := plugin.MarshalProperties(resource.NewPropertyMapFromMap(tt.news), plugin.MarshalOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := k.DiffConfig(context.Background(), &rpc.DiffRequest{Urn: "urn:pulumi:dev::test::pulumi:providers:kind::default", Olds: oldStruct, News: newStruct})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if replaces := len(resp.Replaces) > 0; replaces != tt.replaces {
+				t.Errorf("expected replaces %v, got %v", tt.replaces, resp.Replaces)
+			}
+			if !tt.replaces && !reflect.DeepEqual(resp.Diffs, tt.diffs) {
+				t.Errorf("expected diffs %v, got %v", tt.diffs, resp.Diffs)
+			}
+		})
+	}
+}
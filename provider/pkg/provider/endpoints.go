@@ -0,0 +1,135 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"net/url"
+	"strconv"
+
+	"github.com/frezbo/pulumi-provider-kind/provider/v3/pkg/container"
+	"github.com/frezbo/pulumi-provider-kind/provider/v3/pkg/kubeconfig"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/kind/pkg/cluster"
+)
+
+// liveOutputs are the cluster outputs read back from the running cluster rather than the inputs
+var liveOutputs = []string{
+	"nodeDetails",
+	"apiServerEndpoint",
+	"apiServerPort",
+	"internalApiServerEndpoint",
+	"caCertificate",
+	"networkName",
+	"networkSubnets",
+}
+
+// addLiveOutputs refreshes the outputs read back from the running cluster attached to the network
+func (k *kindProvider) addLiveOutputs(outputs map[string]interface{}, provider *cluster.Provider, clusterName, network string) error {
+	details, err := k.clusterNodeDetails(provider, clusterName)
+	if err != nil {
+		return err
+	}
+	if err = k.setNodeAddresses(details, network); err != nil {
+		return err
+	}
+	if outputs["nodeDetails"], err = toOutputValue(details); err != nil {
+		return err
+	}
+
+	state, err := k.liveClusterState(details)
+	if err != nil {
+		return err
+	}
+	outputs["state"] = state
+	// stopped nodes can't be asked for the kubeconfig and are detached from the network,
+	// so the last known values are kept until the cluster is started again
+	if state == clusterStateStopped {
+		return nil
+	}
+
+	// without kubeadm init there is no API server to talk to
+	if !k.opts.StopBeforeSettingK8s {
+		if err = addAPIServerEndpoints(outputs, provider, clusterName); err != nil {
+			return err
+		}
+	}
+
+	if len(details) == 0 {
+		return nil
+	}
+	return k.addNetwork(outputs, details[0].Name, network)
+}
+
+// addAPIServerEndpoints sets the API server endpoints and certificate authority from the kubeconfigs of the cluster
+func addAPIServerEndpoints(outputs map[string]interface{}, provider *cluster.Provider, clusterName string) error {
+	external, err := provider.KubeConfig(clusterName, false)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get kubeconfig of KIND cluster %s", clusterName)
+	}
+	server, ca, err := kubeconfig.CurrentCluster(external)
+	if err != nil {
+		return err
+	}
+	// the server holds the host port actually allocated when networking.apiServerPort is 0
+	endpoint, err := url.Parse(server)
+	if err != nil {
+		return errors.Wrapf(err, "unexpected API server endpoint %s", server)
+	}
+	port, err := strconv.Atoi(endpoint.Port())
+	if err != nil {
+		return errors.Wrapf(err, "unexpected API server port in %s", server)
+	}
+
+	internal, err := provider.KubeConfig(clusterName, true)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get internal kubeconfig of KIND cluster %s", clusterName)
+	}
+	internalServer, _, err := kubeconfig.CurrentCluster(internal)
+	if err != nil {
+		return err
+	}
+
+	outputs["apiServerEndpoint"] = server
+	outputs["apiServerPort"] = port
+	outputs["internalApiServerEndpoint"] = internalServer
+	outputs["caCertificate"] = ca
+	return nil
+}
+
+// addNetwork sets the name and subnets of the network of the cluster, if the node container joined it
+func (k *kindProvider) addNetwork(outputs map[string]interface{}, node, network string) error {
+	runtime := container.NewRuntime(k.opts.Provider)
+
+	networks, err := runtime.Networks(node)
+	if err != nil {
+		return err
+	}
+	// stopped containers are not attached to any network
+	attached := false
+	for _, name := range networks {
+		attached = attached || name == network
+	}
+	if !attached {
+		return nil
+	}
+	subnets, err := runtime.NetworkSubnets(network)
+	if err != nil {
+		return err
+	}
+
+	outputs["networkName"] = network
+	outputs["networkSubnets"] = subnets
+	return nil
+}
@@ -0,0 +1,331 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	rpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+	"sigs.k8s.io/kind/pkg/apis/config/v1alpha4"
+)
+
+// apiLifecycle are the minor versions of Kubernetes 1.x a feature gate or an API version
+// was added in, went GA in and was removed in, zero when it did not happen
+type apiLifecycle struct {
+	since   int
+	ga      int
+	removed int
+}
+
+// featureGates are the feature gates of the Kubernetes versions with a node image for the embedded KIND release.
+// Gates added before 1.14 have no since version.
+// ref: https://kubernetes.io/docs/reference/command-line-tools-reference/feature-gates/
+var featureGates = map[string]apiLifecycle{
+	"APIPriorityAndFairness":         {since: 17},
+	"AnyVolumeDataSource":            {since: 18},
+	"AttachVolumeLimit":              {ga: 17},
+	"BlockVolume":                    {ga: 18},
+	"BoundServiceAccountTokenVolume": {},
+	"CPUManager":                     {},
+	"CSIBlockVolume":                 {ga: 18},
+	"CSIDriverRegistry":              {ga: 18},
+	"CSIInlineVolume":                {since: 15},
+	"CSIMigration":                   {since: 14},
+	"CSIMigrationAWS":                {since: 14},
+	"CSIMigrationAzureDisk":          {since: 15},
+	"CSIMigrationAzureFile":          {since: 15},
+	"CSIMigrationGCE":                {since: 14},
+	"CSIMigrationOpenStack":          {since: 14},
+	"CSIMigrationvSphere":            {since: 19},
+	"CSINodeInfo":                    {ga: 17},
+	"CSIServiceAccountToken":         {since: 20},
+	"CSIStorageCapacity":             {since: 19},
+	"ConfigurableFSGroupPolicy":      {since: 18},
+	"CronJobControllerV2":            {since: 20},
+	"CustomResourceDefaulting":       {since: 15, ga: 17},
+	"DefaultPodTopologySpread":       {since: 19},
+	"DevicePlugins":                  {},
+	"DownwardAPIHugePages":           {since: 20},
+	"DryRun":                         {ga: 19},
+	"DynamicKubeletConfig":           {},
+	"EndpointSlice":                  {since: 16, ga: 21},
+	"EndpointSliceProxying":          {since: 18},
+	"EphemeralContainers":            {since: 16},
+	"EvenPodsSpread":                 {since: 16, ga: 19, removed: 21},
+	"ExecProbeTimeout":               {since: 20, ga: 20},
+	"ExpandCSIVolumes":               {},
+	"ExpandInUsePersistentVolumes":   {},
+	"ExpandPersistentVolumes":        {},
+	"GenericEphemeralVolume":         {since: 19},
+	"GracefulNodeShutdown":           {since: 20},
+	"HPAScaleToZero":                 {since: 16},
+	"HugePageStorageMediumSize":      {since: 18},
+	"IPv6DualStack":                  {since: 16},
+	"ImmutableEphemeralVolumes":      {since: 18, ga: 21},
+	"IndexedJob":                     {since: 21},
+	"KubeletCredentialProviders":     {since: 20},
+	"KubeletPodResources":            {},
+	"LocalStorageCapacityIsolation":  {},
+	"LogarithmicScaleDown":           {since: 21},
+	"MemoryManager":                  {since: 21},
+	"MixedProtocolLBService":         {since: 20},
+	"NetworkPolicyEndPort":           {since: 21},
+	"NodeDisruptionExclusion":        {since: 16, ga: 21},
+	"NodeLease":                      {ga: 17},
+	"NonPreemptingPriority":          {since: 15},
+	"PodAffinityNamespaceSelector":   {since: 21},
+	"PodDisruptionBudget":            {ga: 21},
+	"PodOverhead":                    {since: 16},
+	"PodShareProcessNamespace":       {ga: 17},
+	"ProbeTerminationGracePeriod":    {since: 21},
+	"RemoveSelfLink":                 {since: 16},
+	"RootCAConfigMap":                {ga: 21},
+	"RotateKubeletServerCertificate": {},
+	"RuntimeClass":                   {ga: 20},
+	"SCTPSupport":                    {ga: 20},
+	"ServerSideApply":                {since: 14},
+	"ServiceAccountIssuerDiscovery":  {since: 18, ga: 21},
+	"ServiceAppProtocol":             {since: 18, ga: 20},
+	"ServiceInternalTrafficPolicy":   {since: 21},
+	"ServiceLBNodePortControl":       {since: 20},
+	"ServiceLoadBalancerClass":       {since: 21},
+	"ServiceNodeExclusion":           {ga: 21},
+	"ServiceTopology":                {since: 17},
+	"SetHostnameAsFQDN":              {since: 19},
+	"SizeMemoryBackedVolumes":        {since: 20},
+	"StartupProbe":                   {since: 16, ga: 20},
+	"StorageVersionAPI":              {since: 20},
+	"StorageVersionHash":             {since: 14},
+	"SupportNodePidsLimit":           {ga: 20},
+	"SupportPodPidsLimit":            {ga: 20},
+	"SuspendJob":                     {since: 21},
+	"Sysctls":                        {ga: 21},
+	"TTLAfterFinished":               {},
+	"TaintBasedEvictions":            {ga: 18},
+	"TokenRequest":                   {ga: 20},
+	"TokenRequestProjection":         {ga: 20},
+	"TopologyManager":                {since: 16},
+	"VolumePVCDataSource":            {since: 15, ga: 18},
+	"VolumeSnapshotDataSource":       {ga: 20},
+	"VolumeSubpathEnvExpansion":      {ga: 17},
+	"WarningHeaders":                 {since: 19},
+	"WatchBookmark":                  {since: 15, ga: 17},
+}
+
+// apiVersions are the group versions of the built-in APIs added or removed within the supported Kubernetes versions
+// ref: https://kubernetes.io/docs/reference/using-api/deprecation-guide/
+var apiVersions = map[string]apiLifecycle{
+	"admissionregistration.k8s.io/v1":       {since: 16},
+	"apiextensions.k8s.io/v1":               {since: 16},
+	"apps/v1beta1":                          {removed: 16},
+	"apps/v1beta2":                          {removed: 16},
+	"auditregistration.k8s.io/v1alpha1":     {removed: 19},
+	"batch/v2alpha1":                        {removed: 21},
+	"certificates.k8s.io/v1":                {since: 19},
+	"discovery.k8s.io/v1":                   {since: 21},
+	"discovery.k8s.io/v1alpha1":             {since: 16, removed: 21},
+	"discovery.k8s.io/v1beta1":              {since: 17},
+	"events.k8s.io/v1":                      {since: 19},
+	"flowcontrol.apiserver.k8s.io/v1alpha1": {since: 17},
+	"flowcontrol.apiserver.k8s.io/v1beta1":  {since: 20},
+	"internal.apiserver.k8s.io/v1alpha1":    {since: 20},
+	"node.k8s.io/v1":                        {since: 20},
+	"node.k8s.io/v1alpha1":                  {since: 14},
+	"node.k8s.io/v1beta1":                   {since: 14},
+	"policy/v1":                             {since: 21},
+	"settings.k8s.io/v1alpha1":              {removed: 20},
+}
+
+// builtinAPIVersions are the group versions served by all supported Kubernetes versions
+var builtinAPIVersions = map[string]bool{
+	"v1":                                   true,
+	"admissionregistration.k8s.io/v1beta1": true,
+	"apiextensions.k8s.io/v1beta1":         true,
+	"apiregistration.k8s.io/v1":            true,
+	"apiregistration.k8s.io/v1beta1":       true,
+	"apps/v1":                              true,
+	"authentication.k8s.io/v1":             true,
+	"authentication.k8s.io/v1beta1":        true,
+	"authorization.k8s.io/v1":              true,
+	"authorization.k8s.io/v1beta1":         true,
+	"autoscaling/v1":                       true,
+	"autoscaling/v2beta1":                  true,
+	"autoscaling/v2beta2":                  true,
+	"batch/v1":                             true,
+	"batch/v1beta1":                        true,
+	"certificates.k8s.io/v1beta1":          true,
+	"coordination.k8s.io/v1":               true,
+	"coordination.k8s.io/v1beta1":          true,
+	"events.k8s.io/v1beta1":                true,
+	"extensions/v1beta1":                   true,
+	"networking.k8s.io/v1":                 true,
+	"networking.k8s.io/v1beta1":            true,
+	"policy/v1beta1":                       true,
+	"rbac.authorization.k8s.io/v1":         true,
+	"rbac.authorization.k8s.io/v1alpha1":   true,
+	"rbac.authorization.k8s.io/v1beta1":    true,
+	"scheduling.k8s.io/v1":                 true,
+	"scheduling.k8s.io/v1alpha1":           true,
+	"scheduling.k8s.io/v1beta1":            true,
+	"storage.k8s.io/v1":                    true,
+	"storage.k8s.io/v1alpha1":              true,
+	"storage.k8s.io/v1beta1":               true,
+}
+
+// runtimeConfigKeyRE matches the keys of --runtime-config: api/all, api/ga, api/beta, api/alpha,
+// group/version, group/version/resource and the core version as v1 or api/v1
+var runtimeConfigKeyRE = regexp.MustCompile(`^(api/(all|ga|beta|alpha)|(api/)?v1|[a-z0-9.-]+/v\d+((alpha|beta)\d+)?(/[a-z0-9]+)?)$`)
+
+// kubernetesMinorVersions returns the distinct Kubernetes 1.x minor versions of the node images
+// of the known nodes, nodes with images without a version in their tag are left out
+func (k *kindProvider) kubernetesMinorVersions(inputs map[string]interface{}) []int {
+	nodes := desiredNodes(&v1alpha4.Cluster{})
+	if rawNodes, present := inputs["nodes"]; present {
+		nodes = nil
+		list, _ := rawNodes.([]interface{})
+		for _, n := range list {
+			node, ok := n.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			image, ok := node["image"].(string)
+			if _, set := node["image"]; set && !ok {
+				// unknown during previews
+				continue
+			}
+			nodes = append(nodes, v1alpha4.Node{Image: image})
+		}
+	}
+
+	found := map[int]bool{}
+	var versions []int
+	for _, node := range nodes {
+		version, ok := imageVersion(k.nodeImage(node))
+		if !ok || version[0] != 1 || found[version[1]] {
+			continue
+		}
+		found[version[1]] = true
+		versions = append(versions, version[1])
+	}
+	sort.Ints(versions)
+	return versions
+}
+
+// checkLifecycle returns why a feature gate or API version can't be used with the Kubernetes minor version
+func checkLifecycle(lifecycle apiLifecycle, minor int) string {
+	switch {
+	case lifecycle.since > minor:
+		return fmt.Sprintf("added in Kubernetes 1.%d, the nodes run Kubernetes 1.%d", lifecycle.since, minor)
+	case lifecycle.removed != 0 && lifecycle.removed <= minor:
+		return fmt.Sprintf("removed in Kubernetes 1.%d, the nodes run Kubernetes 1.%d", lifecycle.removed, minor)
+	}
+	return ""
+}
+
+// checkFeatureGates validates the known feature gates and runtime config against the Kubernetes versions of
+// the nodes. Gates and APIs that don't exist in a version are failures, as kubeadm fails on them. GA gates
+// and keys the provider does not know about are warnings.
+func (k *kindProvider) checkFeatureGates(ctx context.Context, urn resource.URN, news resource.PropertyMap, inputs map[string]interface{}) []*rpc.CheckFailure {
+	var failures []*rpc.CheckFailure
+	var warnings []string
+	versions := k.kubernetesMinorVersions(inputs)
+
+	if gates := news["featureGates"]; gates.IsObject() {
+		for _, gate := range gates.ObjectValue().StableKeys() {
+			property := fmt.Sprintf("featureGates[%q]", gate)
+			value := gates.ObjectValue()[gate]
+			lifecycle, known := featureGates[string(gate)]
+			if !known {
+				warnings = append(warnings, fmt.Sprintf("%s: unknown feature gate %s", property, gate))
+				continue
+			}
+			if reason := checkVersions(lifecycle, versions); reason != "" {
+				failures = append(failures, &rpc.CheckFailure{Property: property, Reason: fmt.Sprintf("feature gate %s was %s", gate, reason)})
+				continue
+			}
+			for _, minor := range versions {
+				if lifecycle.ga != 0 && lifecycle.ga <= minor {
+					state := "can be removed"
+					if value.IsBool() && !value.BoolValue() {
+						state = "may not be disabled anymore"
+					}
+					warnings = append(warnings, fmt.Sprintf("%s: feature gate %s is GA since Kubernetes 1.%d and %s", property, gate, lifecycle.ga, state))
+					break
+				}
+			}
+		}
+	}
+
+	if runtimeConfig := news["runtimeConfig"]; runtimeConfig.IsObject() {
+		for _, key := range runtimeConfig.ObjectValue().StableKeys() {
+			property := fmt.Sprintf("runtimeConfig[%q]", key)
+			if !runtimeConfigKeyRE.MatchString(string(key)) {
+				failures = append(failures, &rpc.CheckFailure{
+					Property: property,
+					Reason:   fmt.Sprintf("%q is not api/all, api/ga, api/beta, api/alpha, a group/version or a group/version/resource", key),
+				})
+				continue
+			}
+			if value := runtimeConfig.ObjectValue()[key]; value.IsString() && value.StringValue() != "" {
+				if _, err := strconv.ParseBool(value.StringValue()); err != nil {
+					failures = append(failures, &rpc.CheckFailure{Property: property, Reason: fmt.Sprintf("%q is neither true nor false", value.StringValue())})
+					continue
+				}
+			}
+			if strings.HasPrefix(string(key), "api/") {
+				continue
+			}
+			groupVersion := string(key)
+			if parts := strings.Split(groupVersion, "/"); len(parts) == 3 {
+				// group/version/resource
+				groupVersion = parts[0] + "/" + parts[1]
+			}
+			lifecycle, known := apiVersions[groupVersion]
+			if !known {
+				if !builtinAPIVersions[groupVersion] {
+					warnings = append(warnings, fmt.Sprintf("%s: unknown API version %s", property, groupVersion))
+				}
+				continue
+			}
+			if reason := checkVersions(lifecycle, versions); reason != "" {
+				failures = append(failures, &rpc.CheckFailure{Property: property, Reason: fmt.Sprintf("API version %s was %s", groupVersion, reason)})
+			}
+		}
+	}
+
+	if k.host != nil {
+		for _, warning := range warnings {
+			_ = k.host.Log(ctx, diag.Warning, urn, warning)
+		}
+	}
+	return failures
+}
+
+// checkVersions returns why the lifecycle does not fit one of the Kubernetes minor versions
+func checkVersions(lifecycle apiLifecycle, versions []int) string {
+	for _, minor := range versions {
+		if reason := checkLifecycle(lifecycle, minor); reason != "" {
+			return reason
+		}
+	}
+	return ""
+}
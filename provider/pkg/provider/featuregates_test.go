@@ -0,0 +1,64 @@
+package provider
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+func TestKubernetesMinorVersions(t *testing.T) {
+	k := &kindProvider{}
+	inputs := map[string]interface{}{
+		"nodes": []interface{}{
+			map[string]interface{}{"role": "control-plane", "image": nodeImages["1.20"]},
+			map[string]interface{}{"role": "worker", "image": nodeImages["1.19"]},
+			map[string]interface{}{"role": "worker", "image": "example.com/custom:latest"},
+			map[string]interface{}{"role": "worker", "image": nodeImages["1.20"]},
+		},
+	}
+	if versions := k.kubernetesMinorVersions(inputs); !reflect.DeepEqual(versions, []int{19, 20}) {
+		t.Errorf("expected [19 20], got %v", versions)
+	}
+	// KIND's default image runs Kubernetes 1.21
+	if versions := k.kubernetesMinorVersions(map[string]interface{}{}); !reflect.DeepEqual(versions, []int{21}) {
+		t.Errorf("expected [21], got %v", versions)
+	}
+}
+
+func TestCheckFeatureGates(t *testing.T) {
+	k := &kindProvider{}
+	inputs := map[string]interface{}{
+		"nodes": []interface{}{map[string]interface{}{"role": "control-plane", "image": nodeImages["1.19"]}},
+		"featureGates": map[string]interface{}{
+			"EvenPodsSpread":       true,
+			"GracefulNodeShutdown": true,
+			"IPv6DualStack":        true,
+			"NotAGate":             true,
+		},
+		"runtimeConfig": map[string]interface{}{
+			"api/alpha":                     "false",
+			"batch/v2alpha1":                "true",
+			"discovery.k8s.io/v1/endpoints": "true",
+			"flowcontrol.apiserver.k8s.io":  "true",
+			"node.k8s.io/v1beta1":           "yes",
+			"example.com/v1":                "true",
+		},
+	}
+	expected := map[string]bool{
+		`featureGates["GracefulNodeShutdown"]`:           true,
+		`runtimeConfig["discovery.k8s.io/v1/endpoints"]`: true,
+		`runtimeConfig["flowcontrol.apiserver.k8s.io"]`:  true,
+		`runtimeConfig["node.k8s.io/v1beta1"]`:           true,
+	}
+	failures := k.checkFeatureGates(context.Background(), "", resource.NewPropertyMapFromMap(inputs), inputs)
+	if len(failures) != len(expected) {
+		t.Fatalf("expected %d failures, got %v", len(expected), failures)
+	}
+	for _, failure := range failures {
+		if !expected[failure.Property] {
+			t.Errorf("unexpected failure %v", failure)
+		}
+	}
+}
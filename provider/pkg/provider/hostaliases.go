@@ -0,0 +1,191 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	pulumilog "github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+	rpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+	"sigs.k8s.io/kind/pkg/cluster"
+	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
+	"sigs.k8s.io/kind/pkg/exec"
+)
+
+const (
+	hostAliasesBegin = "# BEGIN pulumi-kind host aliases"
+	hostAliasesEnd   = "# END pulumi-kind host aliases"
+
+	hostsFile = "/etc/hosts"
+)
+
+// hostAlias are host names resolving to an IP address in the nodes and in the cluster
+type hostAlias struct {
+	IP        string   `json:"ip"`
+	Hostnames []string `json:"hostnames"`
+}
+
+// hostsEntries renders the aliases the way /etc/hosts and the CoreDNS hosts plugin take them
+func hostsEntries(aliases []hostAlias, indent string) string {
+	var entries strings.Builder
+	for _, alias := range aliases {
+		entries.WriteString(fmt.Sprintf("%s%s %s\n", indent, alias.IP, strings.Join(alias.Hostnames, " ")))
+	}
+	return entries.String()
+}
+
+// removeHostAliasesBlock removes the lines between the host aliases markers, the markers included
+func removeHostAliasesBlock(content string) string {
+	var kept []string
+	inBlock := false
+	for _, line := range strings.SplitAfter(content, "\n") {
+		switch strings.TrimSpace(line) {
+		case hostAliasesBegin:
+			inBlock = true
+			continue
+		case hostAliasesEnd:
+			inBlock = false
+			continue
+		}
+		if !inBlock {
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(kept, "")
+}
+
+// setHostsAliases replaces the host aliases at the end of the hosts file
+func setHostsAliases(hosts string, aliases []hostAlias) string {
+	hosts = removeHostAliasesBlock(hosts)
+	if len(aliases) == 0 {
+		return hosts
+	}
+	if hosts != "" && !strings.HasSuffix(hosts, "\n") {
+		hosts += "\n"
+	}
+	return hosts + hostAliasesBegin + "\n" + hostsEntries(aliases, "") + hostAliasesEnd + "\n"
+}
+
+// setCorefileAliases replaces the hosts plugin with the host aliases at the start of the
+// server block of the root zone, falling through to the plugins KIND configures
+func setCorefileAliases(corefile string, aliases []hostAlias) (string, error) {
+	corefile = removeHostAliasesBlock(corefile)
+	if len(aliases) == 0 {
+		return corefile, nil
+	}
+	const serverBlock = ".:53 {\n"
+	start := strings.Index(corefile, serverBlock)
+	if start < 0 {
+		return "", errors.New("no .:53 server block in the CoreDNS config")
+	}
+	start += len(serverBlock)
+	block := "    " + hostAliasesBegin + "\n" +
+		"    hosts {\n" +
+		hostsEntries(aliases, "        ") +
+		"        fallthrough\n" +
+		"    }\n" +
+		"    " + hostAliasesEnd + "\n"
+	return corefile[:start] + block + corefile[start:], nil
+}
+
+// applyHostAliases writes the host aliases to /etc/hosts of every node and to the CoreDNS config.
+// Docker writes /etc/hosts again when a node container starts, so they are applied again after starting the nodes.
+func applyHostAliases(provider *cluster.Provider, clusterName string, aliases []hostAlias) error {
+	internalNodes, err := selectNodes(provider, clusterName, nil)
+	if err != nil {
+		return err
+	}
+	for _, node := range internalNodes {
+		lines, err := exec.OutputLines(node.Command("cat", hostsFile))
+		if err != nil {
+			return errors.Wrapf(err, "failed to read %s of %s", hostsFile, node.String())
+		}
+		hosts := strings.Join(lines, "\n") + "\n"
+		if updated := setHostsAliases(hosts, aliases); updated != hosts {
+			pulumilog.V(3).Infof("writing host aliases to node %s", node.String())
+			if err = nodeutils.WriteFile(node, hostsFile, updated); err != nil {
+				return errors.Wrapf(err, "failed to write %s of %s", hostsFile, node.String())
+			}
+		}
+	}
+
+	controlPlane, err := nodeutils.BootstrapControlPlaneNode(internalNodes)
+	if err != nil {
+		return err
+	}
+	lines, err := exec.OutputLines(controlPlane.Command("kubectl", "--kubeconfig=/etc/kubernetes/admin.conf",
+		"get", "configmap", "coredns", "--namespace=kube-system", "--output=jsonpath={.data.Corefile}"))
+	if err != nil {
+		return errors.Wrap(err, "failed to read CoreDNS config")
+	}
+	corefile := strings.Join(lines, "\n") + "\n"
+	updated, err := setCorefileAliases(corefile, aliases)
+	if err != nil || updated == corefile {
+		return err
+	}
+	patch, err := json.Marshal(map[string]interface{}{"data": map[string]string{"Corefile": updated}})
+	if err != nil {
+		return err
+	}
+	// the reload plugin of the CoreDNS config KIND deploys picks up the change
+	return kubectl(controlPlane, "patch", "configmap", "coredns", "--namespace=kube-system", "--type=merge", "--patch="+string(patch))
+}
+
+// checkHostAliases validates the known IP addresses and host names of the host aliases
+func checkHostAliases(news resource.PropertyMap) []*rpc.CheckFailure {
+	aliases := news["hostAliases"]
+	if !aliases.IsArray() {
+		return nil
+	}
+	var failures []*rpc.CheckFailure
+	for i, alias := range aliases.ArrayValue() {
+		if !alias.IsObject() {
+			continue
+		}
+		if ip := alias.ObjectValue()["ip"]; ip.IsString() && net.ParseIP(ip.StringValue()) == nil {
+			failures = append(failures, &rpc.CheckFailure{
+				Property: fmt.Sprintf("hostAliases[%d].ip", i),
+				Reason:   fmt.Sprintf("%q is not an IP address", ip.StringValue()),
+			})
+		}
+		hostnames := alias.ObjectValue()["hostnames"]
+		if !hostnames.IsArray() {
+			continue
+		}
+		if len(hostnames.ArrayValue()) == 0 {
+			failures = append(failures, &rpc.CheckFailure{
+				Property: fmt.Sprintf("hostAliases[%d].hostnames", i),
+				Reason:   "at least one host name is required",
+			})
+		}
+		for j, hostname := range hostnames.ArrayValue() {
+			if !hostname.IsString() {
+				continue
+			}
+			if name := hostname.StringValue(); strings.HasPrefix(name, "*") || !dnsNameRE.MatchString(name) {
+				failures = append(failures, &rpc.CheckFailure{
+					Property: fmt.Sprintf("hostAliases[%d].hostnames[%d]", i, j),
+					Reason:   fmt.Sprintf("%q is not a DNS name", name),
+				})
+			}
+		}
+	}
+	return failures
+}
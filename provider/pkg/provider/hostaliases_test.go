@@ -0,0 +1,89 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+func TestSetHostsAliases(t *testing.T) {
+	const hosts = "127.0.0.1\tlocalhost\n172.18.0.2\tdev-control-plane\n"
+	aliases := []hostAlias{{IP: "172.18.0.5", Hostnames: []string{"registry.local", "git.local"}}}
+
+	set := setHostsAliases(hosts, aliases)
+	expected := hosts + `# BEGIN pulumi-kind host aliases
+172.18.0.5 registry.local git.local
+# END pulumi-kind host aliases
+`
+	if set != expected {
+		t.Fatalf("expected\n%s\ngot\n%s", expected, set)
+	}
+	if again := setHostsAliases(set, aliases); again != set {
+		t.Errorf("expected the aliases to be set once, got\n%s", again)
+	}
+	if removed := setHostsAliases(set, nil); removed != hosts {
+		t.Errorf("expected %q, got %q", hosts, removed)
+	}
+}
+
+func TestSetCorefileAliases(t *testing.T) {
+	const corefile = `.:53 {
+    errors
+    kubernetes cluster.local in-addr.arpa ip6.arpa
+    forward . /etc/resolv.conf
+    reload
+}
+`
+	aliases := []hostAlias{{IP: "172.18.0.5", Hostnames: []string{"registry.local"}}}
+	set, err := setCorefileAliases(corefile, aliases)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `.:53 {
+    # BEGIN pulumi-kind host aliases
+    hosts {
+        172.18.0.5 registry.local
+        fallthrough
+    }
+    # END pulumi-kind host aliases
+    errors
+    kubernetes cluster.local in-addr.arpa ip6.arpa
+    forward . /etc/resolv.conf
+    reload
+}
+`
+	if set != expected {
+		t.Fatalf("expected\n%s\ngot\n%s", expected, set)
+	}
+	if removed, _ := setCorefileAliases(set, nil); removed != corefile {
+		t.Errorf("expected %q, got %q", corefile, removed)
+	}
+	if _, err = setCorefileAliases("example.com:53 {\n}\n", aliases); err == nil {
+		t.Error("expected an error without a root zone server block")
+	}
+}
+
+func TestCheckHostAliases(t *testing.T) {
+	news := resource.NewPropertyMapFromMap(map[string]interface{}{
+		"hostAliases": []interface{}{
+			map[string]interface{}{"ip": "172.18.0.5", "hostnames": []interface{}{"registry.local"}},
+			map[string]interface{}{"ip": "registry", "hostnames": []interface{}{}},
+			map[string]interface{}{"ip": "::1", "hostnames": []interface{}{"ok.local", "*.local", "Not Valid"}},
+		},
+	})
+	expected := []string{
+		"hostAliases[1].ip",
+		"hostAliases[1].hostnames",
+		"hostAliases[2].hostnames[1]",
+		"hostAliases[2].hostnames[2]",
+	}
+	failures := checkHostAliases(news)
+	if len(failures) != len(expected) {
+		t.Fatalf("expected %d failures, got %v", len(expected), failures)
+	}
+	for i, failure := range failures {
+		if failure.Property != expected[i] {
+			t.Errorf("expected failure on %s, got %s", expected[i], failure.Property)
+		}
+	}
+}
@@ -0,0 +1,574 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/frezbo/pulumi-provider-kind/provider/v3/pkg/container"
+	pbempty "github.com/golang/protobuf/ptypes/empty"
+	structpb "github.com/golang/protobuf/ptypes/struct"
+	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	pulumilog "github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+	rpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+	"sigs.k8s.io/kind/pkg/cluster"
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
+)
+
+const loadedImageType = tokens.Type("kind:image:LoadedImage")
+
+// loadedImage are the inputs and outputs of a LoadedImage
+type loadedImage struct {
+	ClusterName string `json:"clusterName"`
+	// Image is a local image reference
+	Image string `json:"image,omitempty"`
+	// Archive is the path of a local image archive
+	Archive string `json:"archive,omitempty"`
+	// Nodes are the names of the nodes to load the image into, all nodes when empty
+	Nodes []string `json:"nodes,omitempty"`
+	// Digest is the local image ID, or the IDs of the images in the archive, set by Check
+	Digest string `json:"digest,omitempty"`
+	// LoadedNodes are the nodes the image was loaded into
+	LoadedNodes []string `json:"loadedNodes,omitempty"`
+	// References are the references the image is known by in the nodes,
+	// kept so archives can be removed after the archive file is gone
+	References []string `json:"references,omitempty"`
+}
+
+func propMapToLoadedImage(props resource.PropertyMap) (*loadedImage, error) {
+	image := &loadedImage{}
+	data, err := json.Marshal(props.Mappable())
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(data, image); err != nil {
+		return nil, err
+	}
+	return image, nil
+}
+
+func (i *loadedImage) marshal(label string) (*structpb.Struct, error) {
+	value, err := toOutputValue(i)
+	if err != nil {
+		return nil, err
+	}
+	return plugin.MarshalProperties(
+		resource.NewPropertyMapFromMap(value.(map[string]interface{})),
+		plugin.MarshalOptions{Label: label, KeepUnknowns: true, SkipNulls: true},
+	)
+}
+
+// source returns a description of what is loaded
+func (i *loadedImage) source() string {
+	if i.Image != "" {
+		return i.Image
+	}
+	return i.Archive
+}
+
+// imageRefs returns the references the image is known by in the nodes
+func (k *kindProvider) imageRefs(image *loadedImage) ([]string, error) {
+	if image.Image != "" {
+		return []string{image.Image}, nil
+	}
+	images, err := container.ArchiveImages(image.Archive)
+	if err != nil {
+		return nil, err
+	}
+	var refs []string
+	for _, archiveImage := range images {
+		if len(archiveImage.Tags) == 0 {
+			refs = append(refs, archiveImage.ID)
+		}
+		refs = append(refs, archiveImage.Tags...)
+	}
+	return refs, nil
+}
+
+// imageIDRE matches image IDs without the digest algorithm, like podman prints them
+var imageIDRE = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// normalizeImageID returns the image ID prefixed with its digest algorithm like crictl prints it in the nodes
+func normalizeImageID(id string) string {
+	id = strings.TrimSpace(id)
+	if imageIDRE.MatchString(id) {
+		return "sha256:" + id
+	}
+	return id
+}
+
+// imageDigest returns the local image ID, or the sorted IDs of the images in the archive
+func (k *kindProvider) imageDigest(image *loadedImage) (string, error) {
+	if image.Image != "" {
+		id, err := container.NewRuntime(k.opts.Provider).ImageID(image.Image)
+		if err != nil {
+			return "", err
+		}
+		return normalizeImageID(id), nil
+	}
+	images, err := container.ArchiveImages(image.Archive)
+	if err != nil {
+		return "", err
+	}
+	ids := make([]string, 0, len(images))
+	for _, archiveImage := range images {
+		ids = append(ids, archiveImage.ID)
+	}
+	sort.Strings(ids)
+	return strings.Join(ids, ","), nil
+}
+
+// selectNodes returns the nodes of the cluster with the names, all but the load balancer when no names are given
+func selectNodes(provider *cluster.Provider, clusterName string, names []string) ([]nodes.Node, error) {
+	internalNodes, err := provider.ListInternalNodes(clusterName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list nodes of KIND cluster %s", clusterName)
+	}
+	if len(names) == 0 {
+		return internalNodes, nil
+	}
+	selected := make([]nodes.Node, 0, len(names))
+	for _, name := range names {
+		found := false
+		for _, node := range internalNodes {
+			if node.String() == name {
+				selected = append(selected, node)
+				found = true
+			}
+		}
+		if !found {
+			return nil, errors.Errorf("unknown node %s of KIND cluster %s", name, clusterName)
+		}
+	}
+	return selected, nil
+}
+
+// loadImage imports the image into the containerd of the nodes that don't have it yet
+// ref: https://github.com/kubernetes-sigs/kind/blob/v0.11.1/pkg/cmd/kind/load/docker-image/docker-image.go
+func (k *kindProvider) loadImage(image *loadedImage, targets []nodes.Node) error {
+	var pending []nodes.Node
+	for _, node := range targets {
+		// only single images can be compared to what is already in the node, archives are always loaded
+		if image.Image != "" {
+			if id, err := nodeutils.ImageID(node, image.Image); err == nil && normalizeImageID(id) == normalizeImageID(image.Digest) {
+				pulumilog.V(3).Infof("image %s already present on node %s", image.Image, node.String())
+				continue
+			}
+		}
+		pending = append(pending, node)
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	archive := image.Archive
+	if image.Image != "" {
+		dir, err := ioutil.TempDir("", "pulumi-kind-image")
+		if err != nil {
+			return errors.Wrap(err, "failed to create temporary directory")
+		}
+		defer os.RemoveAll(dir)
+		archive = filepath.Join(dir, "image.tar")
+		if err = container.NewRuntime(k.opts.Provider).Save(archive, image.Image); err != nil {
+			return errors.Wrapf(err, "failed to save image %s", image.Image)
+		}
+	}
+
+	for _, node := range pending {
+		f, err := os.Open(archive)
+		if err != nil {
+			return errors.Wrapf(err, "failed to open image archive %s", archive)
+		}
+		err = nodeutils.LoadImageArchive(node, f)
+		f.Close()
+		if err != nil {
+			return errors.Wrapf(err, "failed to load %s into node %s", image.source(), node.String())
+		}
+	}
+	return nil
+}
+
+// unloadImage removes the image from the nodes that still have it
+func (k *kindProvider) unloadImage(image *loadedImage, targets []nodes.Node) error {
+	for _, node := range targets {
+		for _, ref := range image.References {
+			if _, err := nodeutils.ImageID(node, ref); err != nil {
+				continue
+			}
+			if err := node.Command("crictl", "rmi", ref).Run(); err != nil {
+				return errors.Wrapf(err, "failed to remove %s from node %s", ref, node.String())
+			}
+		}
+	}
+	return nil
+}
+
+// nodeNames returns the names of the nodes, sorted
+func nodeNames(targets []nodes.Node) []string {
+	names := make([]string, 0, len(targets))
+	for _, node := range targets {
+		names = append(names, node.String())
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (k *kindProvider) checkLoadedImage(req *rpc.CheckRequest) (*rpc.CheckResponse, error) {
+	urn := resource.URN(req.GetUrn())
+	label := fmt.Sprintf("%s.Check(%s)", k.name, urn)
+
+	news, err := plugin.UnmarshalProperties(req.GetNews(), plugin.MarshalOptions{
+		Label:        fmt.Sprintf("%s.news", label),
+		KeepUnknowns: true,
+		SkipNulls:    true,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "check failed because malformed resource inputs")
+	}
+
+	var failures []*rpc.CheckFailure
+	image, archive := news["image"], news["archive"]
+	switch {
+	case image.ContainsUnknowns() || archive.ContainsUnknowns():
+	case image.IsString() == archive.IsString():
+		failures = append(failures, &rpc.CheckFailure{
+			Property: "image",
+			Reason:   "exactly one of image and archive is required",
+		})
+	case !k.unknownConfig:
+		// the digest is what tells rebuilt images apart, so it's resolved
+		// from the local image every time the inputs are checked
+		args, err := propMapToLoadedImage(news)
+		if err != nil {
+			return nil, err
+		}
+		digest, err := k.imageDigest(args)
+		if err != nil {
+			failures = append(failures, &rpc.CheckFailure{
+				Property: map[bool]string{true: "image", false: "archive"}[args.Image != ""],
+				Reason:   err.Error(),
+			})
+			break
+		}
+		news["digest"] = resource.NewStringProperty(digest)
+	}
+
+	inputs, err := plugin.MarshalProperties(news, plugin.MarshalOptions{
+		Label:        fmt.Sprintf("%s.news", label),
+		KeepUnknowns: true,
+		SkipNulls:    true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &rpc.CheckResponse{Inputs: inputs, Failures: failures}, nil
+}
+
+func (k *kindProvider) diffLoadedImage(req *rpc.DiffRequest) (*rpc.DiffResponse, error) {
+	label := fmt.Sprintf("%s.Diff(%s)", k.name, req.GetUrn())
+
+	olds, err := plugin.UnmarshalProperties(req.GetOlds(), plugin.MarshalOptions{
+		Label:        fmt.Sprintf("%s.olds", label),
+		KeepUnknowns: true,
+		SkipNulls:    true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	news, err := plugin.UnmarshalProperties(req.GetNews(), plugin.MarshalOptions{
+		Label:        fmt.Sprintf("%s.news", label),
+		KeepUnknowns: true,
+		SkipNulls:    true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var replaces, changes []string
+	for _, key := range []resource.PropertyKey{"clusterName", "image", "archive", "digest"} {
+		if !olds[key].DeepEquals(news[key]) {
+			replaces = append(replaces, string(key))
+		}
+	}
+	if !olds["nodes"].DeepEquals(news["nodes"]) {
+		changes = append(changes, "nodes")
+	}
+
+	switch {
+	case len(replaces) > 0:
+		// the old image has to be removed first as the new one might share its references
+		return &rpc.DiffResponse{
+			Changes:             rpc.DiffResponse_DIFF_SOME,
+			Replaces:            replaces,
+			Diffs:               append(replaces, changes...),
+			DeleteBeforeReplace: true,
+		}, nil
+	case len(changes) > 0:
+		return &rpc.DiffResponse{
+			Changes: rpc.DiffResponse_DIFF_SOME,
+			Diffs:   changes,
+		}, nil
+	default:
+		return &rpc.DiffResponse{Changes: rpc.DiffResponse_DIFF_NONE}, nil
+	}
+}
+
+func (k *kindProvider) createLoadedImage(req *rpc.CreateRequest) (*rpc.CreateResponse, error) {
+	urn := resource.URN(req.GetUrn())
+	label := fmt.Sprintf("%s.Create(%s)", k.name, urn)
+
+	news, err := plugin.UnmarshalProperties(req.GetProperties(), plugin.MarshalOptions{
+		Label:        fmt.Sprintf("%s.news", label),
+		KeepUnknowns: true,
+		SkipNulls:    true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetPreview() {
+		news["loadedNodes"] = resource.MakeComputed(resource.NewStringProperty(""))
+		news["references"] = resource.MakeComputed(resource.NewStringProperty(""))
+		properties, err := plugin.MarshalProperties(news, plugin.MarshalOptions{KeepUnknowns: true, SkipNulls: true})
+		if err != nil {
+			return nil, err
+		}
+		return &rpc.CreateResponse{Properties: properties}, nil
+	}
+
+	image, err := propMapToLoadedImage(news)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Create():")
+	}
+	targets, err := selectNodes(k.newClusterProvider(urn), image.ClusterName, image.Nodes)
+	if err != nil {
+		return nil, err
+	}
+	if image.References, err = k.imageRefs(image); err != nil {
+		return nil, err
+	}
+	if err = k.loadImage(image, targets); err != nil {
+		return nil, err
+	}
+	image.LoadedNodes = nodeNames(targets)
+
+	properties, err := image.marshal(fmt.Sprintf("%s.outputs", label))
+	if err != nil {
+		return nil, err
+	}
+	return &rpc.CreateResponse{
+		Id:         fmt.Sprintf("%s/%s", image.ClusterName, image.source()),
+		Properties: properties,
+	}, nil
+}
+
+func (k *kindProvider) readLoadedImage(req *rpc.ReadRequest) (*rpc.ReadResponse, error) {
+	urn := resource.URN(req.GetUrn())
+	label := fmt.Sprintf("%s.Read(%s)", k.name, urn)
+
+	properties, err := plugin.UnmarshalProperties(req.GetProperties(), plugin.MarshalOptions{
+		Label:        fmt.Sprintf("%s.properties", label),
+		KeepUnknowns: true,
+		SkipNulls:    true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	image, err := propMapToLoadedImage(properties)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Read():")
+	}
+
+	provider := k.newClusterProvider(urn)
+	exists, err := clusterExists(provider, image.ClusterName)
+	if err != nil {
+		return nil, err
+	}
+	// the image went away with the cluster
+	if !exists {
+		return &rpc.ReadResponse{}, nil
+	}
+
+	// a node missing the image makes the digest drift, so it's loaded again on the next update
+	if image.Image != "" {
+		internalNodes, err := selectNodes(provider, image.ClusterName, nil)
+		if err != nil {
+			return nil, err
+		}
+		targets := internalNodes
+		if len(image.LoadedNodes) > 0 {
+			targets = nil
+			for _, node := range internalNodes {
+				for _, name := range image.LoadedNodes {
+					if node.String() == name {
+						targets = append(targets, node)
+					}
+				}
+			}
+			// a node the image was loaded into is gone
+			if len(targets) != len(image.LoadedNodes) {
+				image.Digest = ""
+			}
+		}
+		for _, node := range targets {
+			if id, err := nodeutils.ImageID(node, image.Image); err != nil || normalizeImageID(id) != normalizeImageID(image.Digest) {
+				image.Digest = ""
+				break
+			}
+		}
+	}
+
+	outputs, err := image.marshal(fmt.Sprintf("%s.outputs", label))
+	if err != nil {
+		return nil, err
+	}
+	return &rpc.ReadResponse{Id: req.GetId(), Properties: outputs}, nil
+}
+
+func (k *kindProvider) updateLoadedImage(req *rpc.UpdateRequest) (*rpc.UpdateResponse, error) {
+	urn := resource.URN(req.GetUrn())
+	label := fmt.Sprintf("%s.Update(%s)", k.name, urn)
+
+	olds, err := plugin.UnmarshalProperties(req.GetOlds(), plugin.MarshalOptions{
+		Label:        fmt.Sprintf("%s.olds", label),
+		KeepUnknowns: true,
+		SkipNulls:    true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	news, err := plugin.UnmarshalProperties(req.GetNews(), plugin.MarshalOptions{
+		Label:        fmt.Sprintf("%s.news", label),
+		KeepUnknowns: true,
+		SkipNulls:    true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	oldImage, err := propMapToLoadedImage(olds)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Update():")
+	}
+	image, err := propMapToLoadedImage(news)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Update():")
+	}
+
+	if req.GetPreview() {
+		news["loadedNodes"] = resource.MakeComputed(resource.NewStringProperty(""))
+		news["references"] = resource.MakeComputed(resource.NewStringProperty(""))
+		properties, err := plugin.MarshalProperties(news, plugin.MarshalOptions{KeepUnknowns: true, SkipNulls: true})
+		if err != nil {
+			return nil, err
+		}
+		return &rpc.UpdateResponse{Properties: properties}, nil
+	}
+
+	// Diff only allows the nodes to change in place
+	provider := k.newClusterProvider(urn)
+	targets, err := selectNodes(provider, image.ClusterName, image.Nodes)
+	if err != nil {
+		return nil, err
+	}
+	if image.References, err = k.imageRefs(image); err != nil {
+		return nil, err
+	}
+	if err = k.loadImage(image, targets); err != nil {
+		return nil, err
+	}
+	image.LoadedNodes = nodeNames(targets)
+
+	loaded := map[string]bool{}
+	for _, name := range image.LoadedNodes {
+		loaded[name] = true
+	}
+	var removed []string
+	for _, name := range oldImage.LoadedNodes {
+		if !loaded[name] {
+			removed = append(removed, name)
+		}
+	}
+	if len(removed) > 0 {
+		previous, err := selectNodes(provider, image.ClusterName, removed)
+		if err != nil {
+			return nil, err
+		}
+		if err = k.unloadImage(oldImage, previous); err != nil {
+			return nil, err
+		}
+	}
+
+	properties, err := image.marshal(fmt.Sprintf("%s.outputs", label))
+	if err != nil {
+		return nil, err
+	}
+	return &rpc.UpdateResponse{Properties: properties}, nil
+}
+
+func (k *kindProvider) deleteLoadedImage(req *rpc.DeleteRequest) (*pbempty.Empty, error) {
+	urn := resource.URN(req.GetUrn())
+	label := fmt.Sprintf("%s.Delete(%s)", k.name, urn)
+
+	properties, err := plugin.UnmarshalProperties(req.GetProperties(), plugin.MarshalOptions{
+		Label:        fmt.Sprintf("%s.properties", label),
+		KeepUnknowns: true,
+		SkipNulls:    true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	image, err := propMapToLoadedImage(properties)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Delete():")
+	}
+
+	provider := k.newClusterProvider(urn)
+	exists, err := clusterExists(provider, image.ClusterName)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return &pbempty.Empty{}, nil
+	}
+
+	// nodes removed from the cluster since don't need cleaning up
+	internalNodes, err := provider.ListInternalNodes(image.ClusterName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list nodes of KIND cluster %s", image.ClusterName)
+	}
+	var targets []nodes.Node
+	for _, node := range internalNodes {
+		for _, name := range image.LoadedNodes {
+			if node.String() == name {
+				targets = append(targets, node)
+			}
+		}
+	}
+	if err = k.unloadImage(image, targets); err != nil {
+		return nil, err
+	}
+	return &pbempty.Empty{}, nil
+}
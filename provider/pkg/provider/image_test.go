@@ -0,0 +1,18 @@
+package provider
+
+import "testing"
+
+func TestNormalizeImageID(t *testing.T) {
+	const hex = "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	for id, expected := range map[string]string{
+		// docker
+		"sha256:" + hex: "sha256:" + hex,
+		// podman
+		hex + "\n": "sha256:" + hex,
+		"":         "",
+	} {
+		if actual := normalizeImageID(id); actual != expected {
+			t.Errorf("expected %q for %q, got %q", expected, id, actual)
+		}
+	}
+}
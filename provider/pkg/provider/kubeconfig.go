@@ -0,0 +1,163 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/frezbo/pulumi-provider-kind/provider/v3/pkg/kubeconfig"
+	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	rpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+	"sigs.k8s.io/kind/pkg/cluster"
+)
+
+const (
+	// kubeconfigExportNone only stores the kubeconfig in the resource outputs
+	kubeconfigExportNone = "none"
+	// kubeconfigExportFile writes the kubeconfig to a dedicated file
+	kubeconfigExportFile = "file"
+	// kubeconfigExportMerge merges the kubeconfig into a file without switching its current context
+	kubeconfigExportMerge = "merge"
+)
+
+// kubeconfigExport returns the kubeconfig export policy of the cluster.
+// An empty policy keeps KIND's behaviour of merging the kubeconfig into the
+// default kubeconfig file and switching the current context to the new cluster.
+func (o *clusterOptions) kubeconfigExport() string {
+	// KIND always uses its own names, so custom names are merged by the provider instead
+	if o.KubeconfigExport == "" && o.KubeconfigContext != "" {
+		return kubeconfigExportMerge
+	}
+	return o.KubeconfigExport
+}
+
+// kubeconfigContext returns the name of the context, cluster and user entries in the kubeconfig
+func (o *clusterOptions) kubeconfigContext(clusterName string) string {
+	if o.KubeconfigContext != "" {
+		return o.KubeconfigContext
+	}
+	// same as KIND
+	// ref: https://github.com/kubernetes-sigs/kind/blob/v0.11.1/pkg/cluster/internal/kubeconfig/internal/kubeconfig/helpers.go#L23
+	return fmt.Sprintf("kind-%s", clusterName)
+}
+
+// kubeconfigPath returns the file the kubeconfig of the cluster is exported to
+func (k *kindProvider) kubeconfigPath(options *clusterOptions) string {
+	switch options.kubeconfigExport() {
+	case kubeconfigExportNone:
+		return ""
+	case kubeconfigExportFile:
+		return options.KubeconfigPath
+	default:
+		if options.KubeconfigPath != "" {
+			return options.KubeconfigPath
+		}
+		if k.opts.KubeconfigFile != "" {
+			return k.opts.KubeconfigFile
+		}
+		return kubeconfig.DefaultPath()
+	}
+}
+
+// kindKubeconfigPath returns the kubeconfig file KIND itself writes to when creating and deleting the cluster
+// along with a func to clean up any temporary files. KIND always writes the kubeconfig, so unless the
+// export policy is KIND's own it gets a throw away file instead.
+func (k *kindProvider) kindKubeconfigPath(options *clusterOptions) (string, func(), error) {
+	if options.kubeconfigExport() == "" {
+		return k.opts.KubeconfigFile, func() {}, nil
+	}
+	dir, err := ioutil.TempDir("", "pulumi-kind-kubeconfig")
+	if err != nil {
+		return "", nil, errors.Wrap(err, "failed to create temporary kubeconfig directory")
+	}
+	return filepath.Join(dir, "config"), func() {
+		_ = os.RemoveAll(dir)
+	}, nil
+}
+
+// clusterKubeconfig returns the kubeconfig of the cluster with entries named after the configured context
+func (k *kindProvider) clusterKubeconfig(provider *cluster.Provider, clusterName string, options *clusterOptions) (string, error) {
+	content, err := provider.KubeConfig(clusterName, false)
+	if err != nil {
+		return "", err
+	}
+	if options.KubeconfigContext == "" {
+		return content, nil
+	}
+	return kubeconfig.Rename(content, options.KubeconfigContext)
+}
+
+// exportKubeconfig writes the kubeconfig according to the export policy of the cluster
+func (k *kindProvider) exportKubeconfig(provider *cluster.Provider, clusterName string, options *clusterOptions, content string) error {
+	switch options.kubeconfigExport() {
+	case kubeconfigExportNone:
+		return nil
+	case kubeconfigExportFile:
+		return kubeconfig.WriteFile(k.kubeconfigPath(options), content)
+	case kubeconfigExportMerge:
+		return kubeconfig.Merge(k.kubeconfigPath(options), content)
+	default:
+		return provider.ExportKubeConfig(clusterName, k.opts.KubeconfigFile)
+	}
+}
+
+// removeKubeconfig removes exactly the entries exportKubeconfig added for the cluster
+func (k *kindProvider) removeKubeconfig(clusterName string, options *clusterOptions) error {
+	switch options.kubeconfigExport() {
+	case kubeconfigExportNone:
+		return nil
+	case kubeconfigExportFile:
+		return kubeconfig.Remove(k.kubeconfigPath(options), options.kubeconfigContext(clusterName), true)
+	default:
+		return kubeconfig.Remove(k.kubeconfigPath(options), options.kubeconfigContext(clusterName), false)
+	}
+}
+
+// kubeconfigExportChanged checks if the kubeconfig needs to be exported again
+func kubeconfigExportChanged(olds, news *clusterOptions) bool {
+	return olds.KubeconfigExport != news.KubeconfigExport ||
+		olds.KubeconfigPath != news.KubeconfigPath ||
+		olds.KubeconfigContext != news.KubeconfigContext
+}
+
+// checkKubeconfigExport validates the kubeconfig export inputs that are known
+func checkKubeconfigExport(news resource.PropertyMap) []*rpc.CheckFailure {
+	var failures []*rpc.CheckFailure
+
+	export := news["kubeconfigExport"]
+	if !export.IsString() {
+		return nil
+	}
+	switch export.StringValue() {
+	case kubeconfigExportNone, kubeconfigExportMerge:
+	case kubeconfigExportFile:
+		if path := news["kubeconfigPath"]; !path.ContainsUnknowns() && (!path.IsString() || path.StringValue() == "") {
+			failures = append(failures, &rpc.CheckFailure{
+				Property: "kubeconfigPath",
+				Reason:   "kubeconfigPath is required when kubeconfigExport is file",
+			})
+		}
+	default:
+		failures = append(failures, &rpc.CheckFailure{
+			Property: "kubeconfigExport",
+			Reason:   fmt.Sprintf("Valid kubeconfigExport values are %s/%s/%s, got %q", kubeconfigExportNone, kubeconfigExportFile, kubeconfigExportMerge, export.StringValue()),
+		})
+	}
+	return failures
+}
@@ -0,0 +1,303 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	rpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+	apiresource "k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/kind/pkg/apis/config/v1alpha4"
+	"sigs.k8s.io/yaml"
+)
+
+// controlPlaneTaint is the taint kubeadm puts on control plane nodes, which KIND removes from single node clusters
+const controlPlaneTaint = "node-role.kubernetes.io/master"
+
+// evictionSignals are the eviction signals of the kubelet on Linux
+// ref: https://kubernetes.io/docs/concepts/scheduling-eviction/node-pressure-eviction/#eviction-signals
+var evictionSignals = map[string]bool{
+	"memory.available":   true,
+	"nodefs.available":   true,
+	"nodefs.inodesFree":  true,
+	"imagefs.available":  true,
+	"imagefs.inodesFree": true,
+	"pid.available":      true,
+}
+
+// managedKubeletArgs are the kubelet flags set by KIND or by the typed kubelet settings
+var managedKubeletArgs = map[string]string{
+	"node-ip":                    "",
+	"provider-id":                "",
+	"fail-swap-on":               "",
+	"node-labels":                "nodeLabels",
+	"max-pods":                   "maxPods",
+	"eviction-hard":              "evictionHard",
+	"eviction-soft":              "evictionSoft",
+	"eviction-soft-grace-period": "evictionSoftGracePeriod",
+}
+
+// nodeOptions are the node inputs handled by the provider instead of KIND
+type nodeOptions struct {
+	// Kubelet are the kubelet settings of the node
+	Kubelet *kubeletConfig `json:"kubelet,omitempty"`
+	// Taints are added to the taints kubeadm gives the node for its role
+	Taints []taint `json:"taints,omitempty"`
+}
+
+// kubeletConfig are the kubelet settings of a node set when it registers
+type kubeletConfig struct {
+	// MaxPods is the maximum number of pods on the node
+	MaxPods *int `json:"maxPods,omitempty"`
+	// EvictionHard are the hard eviction thresholds by signal
+	EvictionHard map[string]string `json:"evictionHard,omitempty"`
+	// EvictionSoft are the soft eviction thresholds by signal
+	EvictionSoft map[string]string `json:"evictionSoft,omitempty"`
+	// EvictionSoftGracePeriod are the grace periods of the soft eviction thresholds by signal
+	EvictionSoftGracePeriod map[string]string `json:"evictionSoftGracePeriod,omitempty"`
+	// NodeLabels are the labels the node registers with
+	NodeLabels map[string]string `json:"nodeLabels,omitempty"`
+	// ExtraArgs are kubelet flags without the leading dashes
+	ExtraArgs map[string]string `json:"extraArgs,omitempty"`
+}
+
+// taint is a taint the node registers with
+type taint struct {
+	Key    string `json:"key"`
+	Value  string `json:"value,omitempty"`
+	Effect string `json:"effect"`
+}
+
+// joinThresholds renders thresholds by signal the way the kubelet flags take them
+func joinThresholds(thresholds map[string]string, separator string) string {
+	var entries []string
+	for signal, threshold := range thresholds {
+		entries = append(entries, signal+separator+threshold)
+	}
+	sort.Strings(entries)
+	return strings.Join(entries, ",")
+}
+
+// args returns the kubelet flags of the settings, the extra args override the typed settings
+func (k *kubeletConfig) args() map[string]string {
+	args := map[string]string{}
+	if k.MaxPods != nil {
+		args["max-pods"] = strconv.Itoa(*k.MaxPods)
+	}
+	if len(k.EvictionHard) > 0 {
+		args["eviction-hard"] = joinThresholds(k.EvictionHard, "<")
+	}
+	if len(k.EvictionSoft) > 0 {
+		args["eviction-soft"] = joinThresholds(k.EvictionSoft, "<")
+	}
+	if len(k.EvictionSoftGracePeriod) > 0 {
+		args["eviction-soft-grace-period"] = joinThresholds(k.EvictionSoftGracePeriod, "=")
+	}
+	for flag, value := range k.ExtraArgs {
+		args[flag] = value
+	}
+	return args
+}
+
+// kubeadmPatches returns the merge patches of the node registration of the node for its role. The first
+// control plane node is set up with the InitConfiguration and the other nodes with the JoinConfiguration.
+func (n *nodeOptions) kubeadmPatches(role v1alpha4.NodeRole) ([]string, error) {
+	registration := map[string]interface{}{}
+	if n.Kubelet != nil {
+		if args := n.Kubelet.args(); len(args) > 0 {
+			registration["kubeletExtraArgs"] = args
+		}
+	}
+	if len(n.Taints) > 0 {
+		// the merge patch replaces the taints kubeadm defaults to
+		var taints []taint
+		if role == v1alpha4.ControlPlaneRole {
+			taints = append(taints, taint{Key: controlPlaneTaint, Effect: "NoSchedule"})
+		}
+		registration["taints"] = append(taints, n.Taints...)
+	}
+	if len(registration) == 0 {
+		return nil, nil
+	}
+
+	kinds := []string{"JoinConfiguration"}
+	if role == v1alpha4.ControlPlaneRole {
+		kinds = []string{"InitConfiguration", "JoinConfiguration"}
+	}
+	var patches []string
+	for _, kind := range kinds {
+		data, err := yaml.Marshal(map[string]interface{}{
+			"kind":             kind,
+			"nodeRegistration": registration,
+		})
+		if err != nil {
+			return nil, err
+		}
+		patches = append(patches, string(data))
+	}
+	return patches, nil
+}
+
+// propMapToNodeOptions returns the provider handled inputs of the nodes, in the order of the nodes
+func propMapToNodeOptions(inputs map[string]interface{}) ([]nodeOptions, error) {
+	nodes, ok := inputs["nodes"]
+	if !ok {
+		return nil, nil
+	}
+	data, err := json.Marshal(nodes)
+	if err != nil {
+		return nil, err
+	}
+	var options []nodeOptions
+	if err = json.Unmarshal(data, &options); err != nil {
+		return nil, err
+	}
+	return options, nil
+}
+
+// applyNodeOptions renders the kubelet settings and taints of the nodes into kubeadm patches of the nodes
+func applyNodeOptions(config *v1alpha4.Cluster, options []nodeOptions) error {
+	for i := range options {
+		if i >= len(config.Nodes) {
+			break
+		}
+		node := &config.Nodes[i]
+		if kubelet := options[i].Kubelet; kubelet != nil && len(kubelet.NodeLabels) > 0 {
+			// KIND passes the labels of the node to the kubelet when it registers
+			if node.Labels == nil {
+				node.Labels = map[string]string{}
+			}
+			for key, value := range kubelet.NodeLabels {
+				node.Labels[key] = value
+			}
+		}
+		patches, err := options[i].kubeadmPatches(node.Role)
+		if err != nil {
+			return err
+		}
+		node.KubeadmConfigPatches = append(node.KubeadmConfigPatches, patches...)
+	}
+	return nil
+}
+
+// checkNodeOptions validates the known kubelet settings and taints of the nodes
+func checkNodeOptions(news resource.PropertyMap) []*rpc.CheckFailure {
+	nodes := news["nodes"]
+	if !nodes.IsArray() {
+		return nil
+	}
+	var failures []*rpc.CheckFailure
+	for i, node := range nodes.ArrayValue() {
+		if !node.IsObject() {
+			continue
+		}
+		fail := func(property, reason string, args ...interface{}) {
+			failures = append(failures, &rpc.CheckFailure{
+				Property: fmt.Sprintf("nodes[%d].%s", i, property),
+				Reason:   fmt.Sprintf(reason, args...),
+			})
+		}
+		if kubelet := node.ObjectValue()["kubelet"]; kubelet.IsObject() {
+			checkKubelet(kubelet.ObjectValue(), node.ObjectValue()["labels"], fail)
+		}
+		if taints := node.ObjectValue()["taints"]; taints.IsArray() {
+			for j, t := range taints.ArrayValue() {
+				if !t.IsObject() {
+					continue
+				}
+				key, effect := t.ObjectValue()["key"], t.ObjectValue()["effect"]
+				if key.IsString() && key.StringValue() == controlPlaneTaint {
+					fail(fmt.Sprintf("taints[%d].key", j), "%s is set by kubeadm on control plane nodes", controlPlaneTaint)
+				}
+				if effect.IsString() {
+					switch effect.StringValue() {
+					case "NoSchedule", "PreferNoSchedule", "NoExecute":
+					default:
+						fail(fmt.Sprintf("taints[%d].effect", j), "effect is one of NoSchedule, PreferNoSchedule and NoExecute")
+					}
+				}
+			}
+		}
+	}
+	return failures
+}
+
+func checkKubelet(kubelet resource.PropertyMap, labels resource.PropertyValue, fail func(string, string, ...interface{})) {
+	if maxPods := kubelet["maxPods"]; maxPods.IsNumber() && maxPods.NumberValue() < 1 {
+		fail("kubelet.maxPods", "maxPods has to be at least 1")
+	}
+	for _, key := range []resource.PropertyKey{"evictionHard", "evictionSoft", "evictionSoftGracePeriod"} {
+		thresholds := kubelet[key]
+		if !thresholds.IsObject() {
+			continue
+		}
+		for _, signal := range thresholds.ObjectValue().StableKeys() {
+			property := fmt.Sprintf("kubelet.%s[%q]", key, signal)
+			if !evictionSignals[string(signal)] {
+				fail(property, "unknown eviction signal %s", signal)
+				continue
+			}
+			value := thresholds.ObjectValue()[signal]
+			if !value.IsString() {
+				continue
+			}
+			if key == "evictionSoftGracePeriod" {
+				if _, err := time.ParseDuration(value.StringValue()); err != nil {
+					fail(property, "%q is not a duration like 1m30s", value.StringValue())
+				}
+				continue
+			}
+			if !validThreshold(value.StringValue()) {
+				fail(property, "%q is neither a quantity like 100Mi nor a percentage like 10%%", value.StringValue())
+			}
+		}
+	}
+	if nodeLabels := kubelet["nodeLabels"]; nodeLabels.IsObject() && labels.IsObject() {
+		for _, key := range nodeLabels.ObjectValue().StableKeys() {
+			if _, set := labels.ObjectValue()[key]; set {
+				fail(fmt.Sprintf("kubelet.nodeLabels[%q]", key), "%s is also set in the labels of the node", key)
+			}
+		}
+	}
+	if extraArgs := kubelet["extraArgs"]; extraArgs.IsObject() {
+		for _, flag := range extraArgs.ObjectValue().StableKeys() {
+			property := fmt.Sprintf("kubelet.extraArgs[%q]", flag)
+			switch input, managed := managedKubeletArgs[string(flag)]; {
+			case strings.HasPrefix(string(flag), "-"):
+				fail(property, "flags are set without the leading dashes")
+			case managed && input == "":
+				fail(property, "%s is set by KIND", flag)
+			case managed:
+				fail(property, "%s is set with the kubelet.%s input", flag, input)
+			}
+		}
+	}
+}
+
+// validThreshold returns if the eviction threshold is a quantity or a percentage
+func validThreshold(threshold string) bool {
+	if strings.HasSuffix(threshold, "%") {
+		percentage, err := strconv.ParseFloat(strings.TrimSuffix(threshold, "%"), 64)
+		return err == nil && percentage >= 0 && percentage <= 100
+	}
+	_, err := apiresource.ParseQuantity(threshold)
+	return err == nil
+}
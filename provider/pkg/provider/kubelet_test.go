@@ -0,0 +1,103 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"sigs.k8s.io/kind/pkg/apis/config/v1alpha4"
+)
+
+func TestApplyNodeOptions(t *testing.T) {
+	inputs := map[string]interface{}{
+		"nodes": []interface{}{
+			map[string]interface{}{
+				"role":   "control-plane",
+				"taints": []interface{}{map[string]interface{}{"key": "dedicated", "value": "infra", "effect": "NoSchedule"}},
+			},
+			map[string]interface{}{
+				"role": "worker",
+				"kubelet": map[string]interface{}{
+					"maxPods":      50,
+					"evictionHard": map[string]interface{}{"nodefs.available": "10%", "memory.available": "100Mi"},
+					"nodeLabels":   map[string]interface{}{"tier": "backend"},
+				},
+			},
+			map[string]interface{}{"role": "worker"},
+		},
+	}
+	config, err := propMapToKindClusterConfig(inputs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	controlPlane := config.Nodes[0].KubeadmConfigPatches
+	expectedTaints := `nodeRegistration:
+  taints:
+  - effect: NoSchedule
+    key: node-role.kubernetes.io/master
+  - effect: NoSchedule
+    key: dedicated
+    value: infra
+`
+	if len(controlPlane) != 2 || controlPlane[0] != "kind: InitConfiguration\n"+expectedTaints || controlPlane[1] != "kind: JoinConfiguration\n"+expectedTaints {
+		t.Errorf("unexpected control plane patches %q", controlPlane)
+	}
+
+	expectedWorker := `kind: JoinConfiguration
+nodeRegistration:
+  kubeletExtraArgs:
+    eviction-hard: memory.available<100Mi,nodefs.available<10%
+    max-pods: "50"
+`
+	if worker := config.Nodes[1].KubeadmConfigPatches; len(worker) != 1 || worker[0] != expectedWorker {
+		t.Errorf("expected\n%s\ngot %q", expectedWorker, worker)
+	}
+	if config.Nodes[1].Labels["tier"] != "backend" {
+		t.Errorf("expected the node labels in the labels of the node, got %v", config.Nodes[1].Labels)
+	}
+	if len(config.Nodes[2].KubeadmConfigPatches) != 0 || config.Nodes[2].Role != v1alpha4.WorkerRole {
+		t.Errorf("expected no patches for the last worker, got %v", config.Nodes[2])
+	}
+}
+
+func TestCheckNodeOptions(t *testing.T) {
+	news := resource.NewPropertyMapFromMap(map[string]interface{}{
+		"nodes": []interface{}{
+			map[string]interface{}{
+				"role":   "worker",
+				"labels": map[string]interface{}{"tier": "frontend"},
+				"kubelet": map[string]interface{}{
+					"maxPods":                 0,
+					"evictionHard":            map[string]interface{}{"memory.available": "lots", "disk.available": "10%"},
+					"evictionSoftGracePeriod": map[string]interface{}{"memory.available": "1m30s", "nodefs.available": "soon"},
+					"nodeLabels":              map[string]interface{}{"tier": "backend"},
+					"extraArgs":               map[string]interface{}{"node-ip": "10.0.0.1", "max-pods": "10", "v": "4"},
+				},
+				"taints": []interface{}{
+					map[string]interface{}{"key": "dedicated", "effect": "Never"},
+					map[string]interface{}{"key": "node-role.kubernetes.io/master", "effect": "NoSchedule"},
+				},
+			},
+		},
+	})
+	expected := []string{
+		"nodes[0].kubelet.maxPods",
+		`nodes[0].kubelet.evictionHard["disk.available"]`,
+		`nodes[0].kubelet.evictionHard["memory.available"]`,
+		`nodes[0].kubelet.evictionSoftGracePeriod["nodefs.available"]`,
+		`nodes[0].kubelet.nodeLabels["tier"]`,
+		`nodes[0].kubelet.extraArgs["max-pods"]`,
+		`nodes[0].kubelet.extraArgs["node-ip"]`,
+		"nodes[0].taints[0].effect",
+		"nodes[0].taints[1].key",
+	}
+	failures := checkNodeOptions(news)
+	if len(failures) != len(expected) {
+		t.Fatalf("expected %d failures, got %v", len(expected), failures)
+	}
+	for i, failure := range failures {
+		if failure.Property != expected[i] {
+			t.Errorf("expected failure on %s, got %s", expected[i], failure.Property)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	rpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+	"sigs.k8s.io/kind/pkg/apis/config/v1alpha4"
+)
+
+// criRegistryPlugin is the table of the CRI registry settings in the containerd config of the node images
+// ref: https://github.com/containerd/containerd/blob/release/1.5/docs/cri/registry.md
+const criRegistryPlugin = `plugins."io.containerd.grpc.v1.cri".registry`
+
+// registryMirror is a registry whose pulls go through mirror endpoints
+type registryMirror struct {
+	// Endpoints are the mirror URLs tried in order before the registry itself
+	Endpoints []string `json:"endpoints,omitempty"`
+	// InsecureSkipVerify skips the TLS verification of the endpoints
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+	// CAFile is the path of the CA certificate of the endpoints in the nodes
+	CAFile string `json:"caFile,omitempty"`
+	// Auth are the credentials of the endpoints
+	Auth *registryAuth `json:"auth,omitempty"`
+}
+
+// registryAuth are the credentials containerd sends to a registry
+type registryAuth struct {
+	Username      string `json:"username,omitempty"`
+	Password      string `json:"password,omitempty"`
+	IdentityToken string `json:"identityToken,omitempty"`
+}
+
+// registryMirrorsPatch renders the registry mirrors as a containerd config patch,
+// the TLS and auth settings apply to the hosts of the mirror endpoints
+func registryMirrorsPatch(mirrors map[string]registryMirror) string {
+	if len(mirrors) == 0 {
+		return ""
+	}
+	registries := make([]string, 0, len(mirrors))
+	for registry := range mirrors {
+		registries = append(registries, registry)
+	}
+	sort.Strings(registries)
+
+	var patch strings.Builder
+	// containerd configures TLS and auth per endpoint host, Check makes sure registries sharing one agree on them
+	configured := map[string]bool{}
+	for _, registry := range registries {
+		mirror := mirrors[registry]
+		endpoints := make([]string, len(mirror.Endpoints))
+		for i, endpoint := range mirror.Endpoints {
+			endpoints[i] = strconv.Quote(endpoint)
+		}
+		fmt.Fprintf(&patch, "[%s.mirrors.%s]\n  endpoint = [%s]\n", criRegistryPlugin, strconv.Quote(registry), strings.Join(endpoints, ", "))
+
+		for _, endpoint := range mirror.Endpoints {
+			u, err := url.Parse(endpoint)
+			if err != nil || configured[u.Host] {
+				continue
+			}
+			configured[u.Host] = true
+			if mirror.InsecureSkipVerify || mirror.CAFile != "" {
+				fmt.Fprintf(&patch, "[%s.configs.%s.tls]\n", criRegistryPlugin, strconv.Quote(u.Host))
+				if mirror.InsecureSkipVerify {
+					patch.WriteString("  insecure_skip_verify = true\n")
+				}
+				if mirror.CAFile != "" {
+					fmt.Fprintf(&patch, "  ca_file = %s\n", strconv.Quote(mirror.CAFile))
+				}
+			}
+			if auth := mirror.Auth; auth != nil {
+				fmt.Fprintf(&patch, "[%s.configs.%s.auth]\n", criRegistryPlugin, strconv.Quote(u.Host))
+				if auth.Username != "" {
+					fmt.Fprintf(&patch, "  username = %s\n  password = %s\n", strconv.Quote(auth.Username), strconv.Quote(auth.Password))
+				}
+				if auth.IdentityToken != "" {
+					fmt.Fprintf(&patch, "  identitytoken = %s\n", strconv.Quote(auth.IdentityToken))
+				}
+			}
+		}
+	}
+	return patch.String()
+}
+
+// applyRegistryMirrors adds the containerd config patch of the registry mirrors after the patches of the user
+func applyRegistryMirrors(config *v1alpha4.Cluster, mirrors map[string]registryMirror) {
+	if patch := registryMirrorsPatch(mirrors); patch != "" {
+		config.ContainerdConfigPatches = append(config.ContainerdConfigPatches, patch)
+	}
+}
+
+// registryMirrorsChanged checks if the registry mirrors of the inputs differ
+func registryMirrorsChanged(olds, news *clusterOptions) bool {
+	if len(olds.RegistryMirrors) == 0 && len(news.RegistryMirrors) == 0 {
+		return false
+	}
+	return !reflect.DeepEqual(olds.RegistryMirrors, news.RegistryMirrors)
+}
+
+// checkRegistryMirrors validates the known registry mirrors
+func checkRegistryMirrors(news resource.PropertyMap) []*rpc.CheckFailure {
+	mirrors := news["registryMirrors"]
+	if !mirrors.IsObject() {
+		return nil
+	}
+	var failures []*rpc.CheckFailure
+	fail := func(property, reason string, args ...interface{}) {
+		failures = append(failures, &rpc.CheckFailure{Property: property, Reason: fmt.Sprintf(reason, args...)})
+	}
+	// containerd configures TLS and auth per host, so registries sharing an endpoint host share these settings
+	type hostSettings struct {
+		registry resource.PropertyKey
+		settings resource.PropertyValue
+	}
+	hosts := map[string]hostSettings{}
+
+	for _, registry := range mirrors.ObjectValue().StableKeys() {
+		property := fmt.Sprintf("registryMirrors[%q]", registry)
+		if registry != "*" && (registry == "" || strings.ContainsAny(string(registry), "/ ") || strings.Contains(string(registry), "://")) {
+			fail(property, "%q is not a registry host like docker.io or registry.local:5000", registry)
+		}
+		mirror := mirrors.ObjectValue()[registry]
+		if !mirror.IsObject() {
+			continue
+		}
+		settings := mirror.ObjectValue()
+
+		hostConfig := resource.NewObjectProperty(resource.PropertyMap{
+			"insecureSkipVerify": settings["insecureSkipVerify"],
+			"caFile":             settings["caFile"],
+			"auth":               settings["auth"],
+		})
+		switch endpoints := settings["endpoints"]; {
+		case endpoints.IsArray() && len(endpoints.ArrayValue()) > 0:
+			for i, endpoint := range endpoints.ArrayValue() {
+				if !endpoint.IsString() {
+					continue
+				}
+				u, err := url.Parse(endpoint.StringValue())
+				if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+					fail(fmt.Sprintf("%s.endpoints[%d]", property, i), "%q is not an http or https URL", endpoint.StringValue())
+					continue
+				}
+				if hostConfig.ContainsUnknowns() {
+					continue
+				}
+				if other, ok := hosts[u.Host]; ok && !other.settings.DeepEquals(hostConfig) {
+					fail(fmt.Sprintf("%s.endpoints[%d]", property, i),
+						"host %s is also an endpoint of registry %q with different insecureSkipVerify, caFile or auth settings", u.Host, other.registry)
+				} else if !ok {
+					hosts[u.Host] = hostSettings{registry: registry, settings: hostConfig}
+				}
+			}
+		case endpoints.IsComputed(), endpoints.IsOutput():
+		default:
+			fail(property+".endpoints", "at least one endpoint is required")
+		}
+
+		if caFile := settings["caFile"]; caFile.IsString() && !path.IsAbs(caFile.StringValue()) {
+			fail(property+".caFile", "%q is not an absolute path in the nodes", caFile.StringValue())
+		}
+
+		if auth := settings["auth"]; auth.IsObject() {
+			credentials := auth.ObjectValue()
+			username, password, token := credentials["username"], credentials["password"], credentials["identityToken"]
+			switch {
+			case credentials.ContainsUnknowns():
+			case username.IsString() && !password.IsString():
+				fail(property+".auth.password", "password is required with username")
+			case !username.IsString() && password.IsString():
+				fail(property+".auth.username", "username is required with password")
+			case !username.IsString() && !token.IsString():
+				fail(property+".auth", "either username and password or identityToken is required")
+			}
+		}
+	}
+	return failures
+}
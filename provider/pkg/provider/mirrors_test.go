@@ -0,0 +1,94 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+func TestRegistryMirrorsPatch(t *testing.T) {
+	patch := registryMirrorsPatch(map[string]registryMirror{
+		"quay.io": {Endpoints: []string{"http://mirror.local:5000"}},
+		"docker.io": {
+			Endpoints:          []string{"https://mirror.example.com", "https://mirror.example.com/v2"},
+			InsecureSkipVerify: true,
+			CAFile:             "/etc/ssl/mirror.pem",
+			Auth:               &registryAuth{Username: "pulumi", Password: "secret"},
+		},
+	})
+	expected := `[plugins."io.containerd.grpc.v1.cri".registry.mirrors."docker.io"]
+  endpoint = ["https://mirror.example.com", "https://mirror.example.com/v2"]
+[plugins."io.containerd.grpc.v1.cri".registry.configs."mirror.example.com".tls]
+  insecure_skip_verify = true
+  ca_file = "/etc/ssl/mirror.pem"
+[plugins."io.containerd.grpc.v1.cri".registry.configs."mirror.example.com".auth]
+  username = "pulumi"
+  password = "secret"
+[plugins."io.containerd.grpc.v1.cri".registry.mirrors."quay.io"]
+  endpoint = ["http://mirror.local:5000"]
+`
+	if patch != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, patch)
+	}
+	if patch := registryMirrorsPatch(nil); patch != "" {
+		t.Errorf("expected no patch, got %s", patch)
+	}
+}
+
+func TestCheckRegistryMirrors(t *testing.T) {
+	news := resource.NewPropertyMapFromMap(map[string]interface{}{
+		"registryMirrors": map[string]interface{}{
+			"docker.io": map[string]interface{}{
+				"endpoints": []interface{}{"https://mirror.example.com"},
+				"auth":      map[string]interface{}{"identityToken": "token"},
+			},
+			"https://quay.io": map[string]interface{}{
+				"endpoints": []interface{}{"mirror.example.com"},
+				"caFile":    "mirror.pem",
+				"auth":      map[string]interface{}{"username": "pulumi"},
+			},
+			"gcr.io": map[string]interface{}{},
+		},
+	})
+	expected := []string{
+		`registryMirrors["gcr.io"].endpoints`,
+		`registryMirrors["https://quay.io"]`,
+		`registryMirrors["https://quay.io"].endpoints[0]`,
+		`registryMirrors["https://quay.io"].caFile`,
+		`registryMirrors["https://quay.io"].auth.password`,
+	}
+	failures := checkRegistryMirrors(news)
+	if len(failures) != len(expected) {
+		t.Fatalf("expected %d failures, got %v", len(expected), failures)
+	}
+	for i, failure := range failures {
+		if failure.Property != expected[i] {
+			t.Errorf("expected failure on %s, got %s", expected[i], failure.Property)
+		}
+	}
+}
+
+func TestCheckRegistryMirrorsSharedHost(t *testing.T) {
+	news := resource.NewPropertyMapFromMap(map[string]interface{}{
+		"registryMirrors": map[string]interface{}{
+			"docker.io": map[string]interface{}{
+				"endpoints":          []interface{}{"https://mirror.example.com/docker"},
+				"insecureSkipVerify": true,
+			},
+			"gcr.io": map[string]interface{}{
+				"endpoints":          []interface{}{"https://mirror.example.com/gcr"},
+				"insecureSkipVerify": true,
+			},
+			"quay.io": map[string]interface{}{
+				"endpoints": []interface{}{"https://other.example.com", "https://mirror.example.com/quay"},
+			},
+		},
+	})
+	failures := checkRegistryMirrors(news)
+	if len(failures) != 1 {
+		t.Fatalf("expected 1 failure, got %v", failures)
+	}
+	if expected := `registryMirrors["quay.io"].endpoints[1]`; failures[0].Property != expected {
+		t.Errorf("expected failure on %s, got %s", expected, failures[0].Property)
+	}
+}
@@ -0,0 +1,251 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/frezbo/pulumi-provider-kind/provider/v3/pkg/container"
+	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	pulumilog "github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+	rpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+	"sigs.k8s.io/kind/pkg/apis/config/v1alpha4"
+	"sigs.k8s.io/kind/pkg/cluster"
+)
+
+// networkNameRE matches the network names docker and podman accept
+var networkNameRE = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// networkSettings is the container network the nodes of the cluster are attached to
+type networkSettings struct {
+	Name       string `json:"name"`
+	Subnet     string `json:"subnet,omitempty"`
+	IPv6Subnet string `json:"ipv6Subnet,omitempty"`
+	MTU        int    `json:"mtu,omitempty"`
+}
+
+// env returns the environment variables KIND reads the network of the nodes from
+// ref: https://github.com/kubernetes-sigs/kind/blob/v0.11.1/pkg/cluster/internal/providers/docker/provider.go#L74
+func (n *networkSettings) env() map[string]string {
+	return map[string]string{
+		"KIND_EXPERIMENTAL_DOCKER_NETWORK": n.Name,
+		"KIND_EXPERIMENTAL_PODMAN_NETWORK": n.Name,
+	}
+}
+
+// clusterEnv returns the environment variables of the settings KIND reads from the process environment
+func clusterEnv(options *clusterOptions) map[string]string {
+	env := map[string]string{}
+	if options.Proxy != nil {
+		for key, value := range options.Proxy.env() {
+			env[key] = value
+		}
+	}
+	if options.Network != nil {
+		for key, value := range options.Network.env() {
+			env[key] = value
+		}
+	}
+	return env
+}
+
+// envMutex guards the settings KIND reads from the process environment. Creates hold it until KIND read them and
+// reads of them by the provider hold it, so parallel creates never see the variables set for another cluster.
+var envMutex sync.Mutex
+
+// envPollInterval is how often withEnv checks if KIND read the environment variables
+const envPollInterval = 500 * time.Millisecond
+
+// withEnv runs fn with the environment variables instead of the ones of the provider, empty values unset them.
+// The variables are restored and the lock released as soon as read reports that KIND read them, so parallel
+// creates only wait for each other until their nodes are created and not for the whole cluster to come up.
+// The lock is taken even without variables, so fn does not see the ones set for a parallel create.
+func withEnv(env map[string]string, read func() bool, fn func() error) error {
+	envMutex.Lock()
+	previous := map[string]*string{}
+	for key, value := range env {
+		if old, set := os.LookupEnv(key); set {
+			previous[key] = &old
+		} else {
+			previous[key] = nil
+		}
+		if value == "" {
+			os.Unsetenv(key)
+		} else {
+			os.Setenv(key, value)
+		}
+	}
+	var once sync.Once
+	release := func() {
+		once.Do(func() {
+			for key, value := range previous {
+				if value != nil {
+					os.Setenv(key, *value)
+				} else {
+					os.Unsetenv(key)
+				}
+			}
+			envMutex.Unlock()
+		})
+	}
+	defer release()
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		ticker := time.NewTicker(envPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if read() {
+					release()
+					return
+				}
+			}
+		}
+	}()
+	return fn()
+}
+
+// nodesCreated reports if KIND created any node container of the cluster. KIND reads the network and proxy
+// settings from the environment before it creates the first node container.
+// ref: https://github.com/kubernetes-sigs/kind/blob/v0.11.1/pkg/cluster/internal/providers/docker/provider.go#L65
+func nodesCreated(provider *cluster.Provider, clusterName string) func() bool {
+	return func() bool {
+		nodes, err := provider.ListNodes(clusterName)
+		return err == nil && len(nodes) > 0
+	}
+}
+
+// getenv reads an environment variable KIND reads settings from without seeing the ones set by withEnv
+func getenv(key string) string {
+	envMutex.Lock()
+	defer envMutex.Unlock()
+	return os.Getenv(key)
+}
+
+// clusterNetworkName returns the name of the network KIND attaches the nodes of the cluster to
+// ref: https://github.com/kubernetes-sigs/kind/blob/v0.11.1/pkg/cluster/internal/providers/docker/provider.go#L74
+func (k *kindProvider) clusterNetworkName(network *networkSettings) string {
+	if network != nil {
+		return network.Name
+	}
+	variable := "KIND_EXPERIMENTAL_DOCKER_NETWORK"
+	if k.opts.Provider == kindPodmanProvider {
+		variable = "KIND_EXPERIMENTAL_PODMAN_NETWORK"
+	}
+	if name := getenv(variable); name != "" {
+		return name
+	}
+	return "kind"
+}
+
+// ensureNetwork creates the network when it is missing, existing networks are used as they are.
+// KIND only creates networks with an IPv6 subnet of its own, so IPv6 clusters need one set.
+func (k *kindProvider) ensureNetwork(network *networkSettings, config *v1alpha4.Cluster) error {
+	if network == nil {
+		return nil
+	}
+	runtime := container.NewRuntime(k.opts.Provider)
+	if runtime.NetworkExists(network.Name) {
+		return nil
+	}
+	if config.Networking.IPFamily != v1alpha4.IPv4Family && config.Networking.IPFamily != "" && network.IPv6Subnet == "" {
+		return errors.Errorf("network %s needs an ipv6Subnet for the %s cluster", network.Name, config.Networking.IPFamily)
+	}
+	pulumilog.V(3).Infof("creating network %s", network.Name)
+	if err := runtime.CreateNetwork(&container.NetworkSpec{
+		Name:       network.Name,
+		Subnet:     network.Subnet,
+		IPv6Subnet: network.IPv6Subnet,
+		MTU:        network.MTU,
+	}); err != nil {
+		return errors.Wrapf(err, "failed to create network %s", network.Name)
+	}
+	return nil
+}
+
+// removeUnusedNetwork removes the network if the provider created it and no container is attached to it anymore
+func (k *kindProvider) removeUnusedNetwork(network *networkSettings) error {
+	if network == nil {
+		return nil
+	}
+	runtime := container.NewRuntime(k.opts.Provider)
+	if !runtime.NetworkExists(network.Name) {
+		return nil
+	}
+	managed, err := runtime.NetworkManaged(network.Name)
+	if err != nil || !managed {
+		return err
+	}
+	containers, err := runtime.NetworkContainers(network.Name)
+	if err != nil {
+		return err
+	}
+	if len(containers) > 0 {
+		pulumilog.V(3).Infof("keeping network %s used by %v", network.Name, containers)
+		return nil
+	}
+	pulumilog.V(3).Infof("removing network %s", network.Name)
+	if err = runtime.RemoveNetwork(network.Name); err != nil {
+		return errors.Wrapf(err, "failed to remove network %s", network.Name)
+	}
+	return nil
+}
+
+// checkNetwork validates the known network settings
+func checkNetwork(news resource.PropertyMap) []*rpc.CheckFailure {
+	network := news["network"]
+	if !network.IsObject() {
+		return nil
+	}
+	settings := network.ObjectValue()
+	var failures []*rpc.CheckFailure
+	fail := func(property, reason string, args ...interface{}) {
+		failures = append(failures, &rpc.CheckFailure{Property: "network." + property, Reason: fmt.Sprintf(reason, args...)})
+	}
+
+	if name := settings["name"]; name.IsString() && !networkNameRE.MatchString(name.StringValue()) {
+		fail("name", "%q is not a valid network name", name.StringValue())
+	}
+	for _, key := range []resource.PropertyKey{"subnet", "ipv6Subnet"} {
+		ipv6 := key == "ipv6Subnet"
+		subnet := settings[key]
+		if !subnet.IsString() {
+			continue
+		}
+		ip, _, err := net.ParseCIDR(subnet.StringValue())
+		if err != nil || (ip.To4() == nil) != ipv6 {
+			family := "IPv4"
+			if ipv6 {
+				family = "IPv6"
+			}
+			fail(string(key), "%q is not an %s CIDR", subnet.StringValue(), family)
+		}
+	}
+	if mtu := settings["mtu"]; mtu.IsNumber() && (mtu.NumberValue() < 68 || mtu.NumberValue() > 65535) {
+		fail("mtu", "mtu is between 68 and 65535")
+	}
+	return failures
+}
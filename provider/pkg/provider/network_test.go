@@ -0,0 +1,75 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+func TestClusterEnv(t *testing.T) {
+	options := &clusterOptions{
+		Proxy:   &proxySettings{HTTPProxy: "http://proxy:3128"},
+		Network: &networkSettings{Name: "isolated"},
+	}
+	env := clusterEnv(options)
+	expected := map[string]string{
+		"HTTP_PROXY":                       "http://proxy:3128",
+		"http_proxy":                       "http://proxy:3128",
+		"HTTPS_PROXY":                      "",
+		"https_proxy":                      "",
+		"NO_PROXY":                         "",
+		"no_proxy":                         "",
+		"KIND_EXPERIMENTAL_DOCKER_NETWORK": "isolated",
+		"KIND_EXPERIMENTAL_PODMAN_NETWORK": "isolated",
+	}
+	if !reflect.DeepEqual(env, expected) {
+		t.Errorf("expected %v, got %v", expected, env)
+	}
+	if env = clusterEnv(&clusterOptions{}); len(env) != 0 {
+		t.Errorf("expected no environment variables, got %v", env)
+	}
+}
+
+func TestWithEnvReleasesLock(t *testing.T) {
+	read := make(chan struct{})
+	err := withEnv(nil, func() bool {
+		select {
+		case <-read:
+			return true
+		default:
+			return false
+		}
+	}, func() error {
+		close(read)
+		// blocks until withEnv released the lock while fn is still running
+		getenv("HOME")
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the lock is released once fn returns
+	getenv("HOME")
+}
+
+func TestCheckNetwork(t *testing.T) {
+	news := resource.NewPropertyMapFromMap(map[string]interface{}{
+		"network": map[string]interface{}{
+			"name":       "-isolated",
+			"subnet":     "fd00:10::/64",
+			"ipv6Subnet": "fd00:20::/64",
+			"mtu":        20,
+		},
+	})
+	expected := []string{"network.name", "network.subnet", "network.mtu"}
+	failures := checkNetwork(news)
+	if len(failures) != len(expected) {
+		t.Fatalf("expected %d failures, got %v", len(expected), failures)
+	}
+	for i, failure := range failures {
+		if failure.Property != expected[i] {
+			t.Errorf("expected failure on %s, got %s", expected[i], failure.Property)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	rpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+	"sigs.k8s.io/kind/pkg/apis/config/defaults"
+	"sigs.k8s.io/kind/pkg/apis/config/v1alpha4"
+)
+
+const getNodeImageToken = tokens.ModuleMember("kind:index:getNodeImage")
+
+// nodeImages are the node images published for the embedded KIND release, by minor version.
+// Images built for other KIND releases are not guaranteed to work.
+// ref: https://github.com/kubernetes-sigs/kind/releases/tag/v0.11.1
+var nodeImages = map[string]string{
+	"1.21": "kindest/node:v1.21.1@sha256:69860bda5563ac81e3c0057d654b5253219618a22ec3a346306239bba8cfa1a6",
+	"1.20": "kindest/node:v1.20.7@sha256:cbeaf907fc78ac97ce7b625e4bf0de16e3ea725daf6b04f930bd14c67c671ff9",
+	"1.19": "kindest/node:v1.19.11@sha256:07db187ae84b4b7de440a73886f008cf903fcf5764ba8106a9fd5243d6f32729",
+	"1.18": "kindest/node:v1.18.19@sha256:7af1492e19b3192a79f606e43c35fb741e520d195f96399284515f077b3b622c",
+	"1.17": "kindest/node:v1.17.17@sha256:66f1d0d91a88b8a001811e2f1054af60eef3b669a9a74f9b6db871f2f1eeed00",
+	"1.16": "kindest/node:v1.16.15@sha256:83067ed51bf2a3395b24687094e283a7c7c865ccc12a8b1d7aa673ba0c5e8861",
+	"1.15": "kindest/node:v1.15.12@sha256:b920920e1eda689d9936dfcf7332701e80be12566999152626b2c9d730397a95",
+	"1.14": "kindest/node:v1.14.10@sha256:f8a66ef82822ab4f7569e91a5bccaf27bceee135c1457c512e54de8c6f7219f8",
+}
+
+// kubernetesVersionRE matches 1.21, v1.21, 1.21.1 and v1.21.1
+var kubernetesVersionRE = regexp.MustCompile(`^v?(\d+\.\d+)(\.\d+)?$`)
+
+// resolveNodeImage returns the pinned node image for the Kubernetes version,
+// either a minor version or the exact patch version of the pinned image
+func resolveNodeImage(version string) (string, error) {
+	match := kubernetesVersionRE.FindStringSubmatch(version)
+	if match == nil {
+		return "", errors.Errorf("invalid Kubernetes version %q, expected a version like 1.21 or v1.21.1", version)
+	}
+	image, ok := nodeImages[match[1]]
+	if !ok {
+		return "", errors.Errorf("no node image for Kubernetes %s, supported versions are %s", version, strings.Join(supportedKubernetesVersions(), ", "))
+	}
+	if match[2] != "" && !strings.Contains(image, fmt.Sprintf(":v%s%s@", match[1], match[2])) {
+		return "", errors.Errorf("no node image for Kubernetes %s, the node image for %s is %s", version, match[1], image)
+	}
+	return image, nil
+}
+
+// supportedKubernetesVersions returns the minor versions with a pinned node image, newest first
+func supportedKubernetesVersions() []string {
+	versions := make([]string, 0, len(nodeImages))
+	for version := range nodeImages {
+		versions = append(versions, version)
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return imageVersionLess(nodeImages[versions[j]], nodeImages[versions[i]])
+	})
+	return versions
+}
+
+func imageVersionLess(a, b string) bool {
+	va, _ := imageVersion(a)
+	vb, _ := imageVersion(b)
+	for i := range va {
+		if va[i] != vb[i] {
+			return va[i] < vb[i]
+		}
+	}
+	return false
+}
+
+// resolveKubernetesVersions sets the image of the nodes from the kubernetesVersion of the node or the cluster.
+// Nodes with an explicit image keep it, unless they set both which is a failure.
+func resolveKubernetesVersions(inputs map[string]interface{}) []*rpc.CheckFailure {
+	var failures []*rpc.CheckFailure
+
+	clusterVersion, _ := inputs["kubernetesVersion"].(string)
+	nodes, ok := inputs["nodes"].([]interface{})
+	if _, set := inputs["nodes"]; !set && clusterVersion != "" {
+		// same default as KIND, a single control plane
+		nodes, ok = []interface{}{map[string]interface{}{"role": string(v1alpha4.ControlPlaneRole)}}, true
+		inputs["nodes"] = nodes
+	}
+	if !ok {
+		return nil
+	}
+
+	for i, n := range nodes {
+		node, ok := n.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		version, _ := node["kubernetesVersion"].(string)
+		if _, set := node["image"]; set {
+			if version != "" {
+				failures = append(failures, &rpc.CheckFailure{
+					Property: fmt.Sprintf("nodes[%d].kubernetesVersion", i),
+					Reason:   "only one of image and kubernetesVersion can be set",
+				})
+			}
+			continue
+		}
+		if version == "" {
+			version = clusterVersion
+		}
+		if version == "" {
+			continue
+		}
+		image, err := resolveNodeImage(version)
+		if err != nil {
+			property := "kubernetesVersion"
+			if node["kubernetesVersion"] != nil {
+				property = fmt.Sprintf("nodes[%d].kubernetesVersion", i)
+			}
+			failures = append(failures, &rpc.CheckFailure{Property: property, Reason: err.Error()})
+			continue
+		}
+		node["image"] = image
+	}
+	return failures
+}
+
+// warnUnpinnedImages warns about node images that are tagged without a digest, which
+// KIND does not recommend as the images are rebuilt for every KIND release
+// ref: https://github.com/kubernetes-sigs/kind/releases/tag/v0.11.1
+func (k *kindProvider) warnUnpinnedImages(ctx context.Context, urn resource.URN, inputs map[string]interface{}) {
+	images := map[string]bool{}
+	if k.opts.NodeImage != "" {
+		images[k.opts.NodeImage] = true
+	}
+	nodes, _ := inputs["nodes"].([]interface{})
+	for _, n := range nodes {
+		if node, ok := n.(map[string]interface{}); ok {
+			if image, ok := node["image"].(string); ok && image != "" {
+				images[image] = true
+			}
+		}
+	}
+	for image := range images {
+		if strings.Contains(image, "@") || k.host == nil {
+			continue
+		}
+		_ = k.host.Log(ctx, diag.Warning, urn, fmt.Sprintf(
+			"node image %s has no digest, use kubernetesVersion or a full %s@sha256:... reference to get an image built for this provider's KIND release",
+			image, strings.Split(image, "@")[0]))
+	}
+}
+
+// getNodeImage returns the pinned node image for a Kubernetes version, KIND's default image without one
+func (k *kindProvider) getNodeImage(req *rpc.InvokeRequest) (*rpc.InvokeResponse, error) {
+	args, err := plugin.UnmarshalProperties(req.GetArgs(), plugin.MarshalOptions{
+		Label:        fmt.Sprintf("%s.getNodeImage.args", k.name),
+		KeepUnknowns: true,
+		SkipNulls:    true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	image := defaults.Image
+	if version := args["kubernetesVersion"]; version.IsString() && version.StringValue() != "" {
+		resolved, err := resolveNodeImage(version.StringValue())
+		if err != nil {
+			return &rpc.InvokeResponse{Failures: []*rpc.CheckFailure{{Property: "kubernetesVersion", Reason: err.Error()}}}, nil
+		}
+		image = resolved
+	}
+	version, _ := imageVersion(image)
+
+	result, err := plugin.MarshalProperties(resource.NewPropertyMapFromMap(map[string]interface{}{
+		"image":             image,
+		"kubernetesVersion": fmt.Sprintf("v%d.%d.%d", version[0], version[1], version[2]),
+	}), plugin.MarshalOptions{Label: fmt.Sprintf("%s.getNodeImage.result", k.name)})
+	if err != nil {
+		return nil, err
+	}
+	return &rpc.InvokeResponse{Return: result}, nil
+}
@@ -0,0 +1,67 @@
+package provider
+
+import (
+	"testing"
+)
+
+func TestResolveNodeImage(t *testing.T) {
+	tests := []struct {
+		version  string
+		expected string
+		fails    bool
+	}{
+		{"1.21", nodeImages["1.21"], false},
+		{"v1.20", nodeImages["1.20"], false},
+		{"v1.19.11", nodeImages["1.19"], false},
+		{"1.19.1", "", true},
+		{"1.13", "", true},
+		{"latest", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			actual, err := resolveNodeImage(tt.version)
+			if tt.fails != (err != nil) {
+				t.Fatalf("expected failure %v, got %v", tt.fails, err)
+			}
+			if actual != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestResolveKubernetesVersions(t *testing.T) {
+	inputs := map[string]interface{}{
+		"kubernetesVersion": "1.21",
+		"nodes": []interface{}{
+			map[string]interface{}{"role": "control-plane"},
+			map[string]interface{}{"role": "worker", "kubernetesVersion": "1.20"},
+			map[string]interface{}{"role": "worker", "image": "kindest/node:v1.19.11"},
+		},
+	}
+	if failures := resolveKubernetesVersions(inputs); len(failures) != 0 {
+		t.Fatalf("unexpected failures %v", failures)
+	}
+	expected := []string{nodeImages["1.21"], nodeImages["1.20"], "kindest/node:v1.19.11"}
+	for i, n := range inputs["nodes"].([]interface{}) {
+		if image := n.(map[string]interface{})["image"]; image != expected[i] {
+			t.Errorf("node %d: expected %s, got %v", i, expected[i], image)
+		}
+	}
+
+	defaulted := map[string]interface{}{"kubernetesVersion": "1.20"}
+	resolveKubernetesVersions(defaulted)
+	if nodes, ok := defaulted["nodes"].([]interface{}); !ok || len(nodes) != 1 {
+		t.Fatalf("expected a single default node, got %v", defaulted["nodes"])
+	}
+
+	conflicting := map[string]interface{}{
+		"nodes": []interface{}{
+			map[string]interface{}{"image": "kindest/node:v1.21.1", "kubernetesVersion": "1.21"},
+		},
+	}
+	failures := resolveKubernetesVersions(conflicting)
+	if len(failures) != 1 || failures[0].Property != "nodes[0].kubernetesVersion" {
+		t.Errorf("expected a nodes[0].kubernetesVersion failure, got %v", failures)
+	}
+}
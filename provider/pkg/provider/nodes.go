@@ -0,0 +1,142 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+
+	"github.com/frezbo/pulumi-provider-kind/provider/v3/pkg/container"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/kind/pkg/cluster"
+)
+
+// nodeDetail is the live information of a node container exposed as the `nodeDetails` output
+type nodeDetail struct {
+	Name         string            `json:"name"`
+	Role         string            `json:"role"`
+	Image        string            `json:"image"`
+	IPv4Address  string            `json:"ipv4Address,omitempty"`
+	IPv6Address  string            `json:"ipv6Address,omitempty"`
+	PortMappings []nodePortMapping `json:"portMappings,omitempty"`
+	// HostPorts are the host addresses of the published ports keyed by <containerPort>/<protocol>,
+	// so ports allocated for a hostPort of 0 can be looked up by the container port
+	HostPorts map[string]string `json:"hostPorts,omitempty"`
+}
+
+// nodePortMapping is a container port of a node published on the host
+type nodePortMapping struct {
+	ContainerPort int    `json:"containerPort"`
+	Protocol      string `json:"protocol"`
+	ListenAddress string `json:"listenAddress"`
+	HostPort      int    `json:"hostPort"`
+}
+
+// clusterNodeDetails inspects the node containers of the cluster, sorted by name
+func (k *kindProvider) clusterNodeDetails(provider *cluster.Provider, clusterName string) ([]nodeDetail, error) {
+	nodes, err := provider.ListNodes(clusterName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list nodes of KIND cluster %s", clusterName)
+	}
+
+	runtime := container.NewRuntime(k.opts.Provider)
+
+	details := make([]nodeDetail, 0, len(nodes))
+	for _, node := range nodes {
+		name := node.String()
+		role, err := node.Role()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get role of node %s", name)
+		}
+		image, err := nodeContainerImage(runtime, name)
+		if err != nil {
+			return nil, err
+		}
+		bindings, err := runtime.PortBindings(name)
+		if err != nil {
+			return nil, err
+		}
+
+		detail := nodeDetail{
+			Name:  name,
+			Role:  role,
+			Image: image,
+		}
+		detail.setPortMappings(bindings)
+		details = append(details, detail)
+	}
+
+	sort.Slice(details, func(i, j int) bool {
+		return details[i].Name < details[j].Name
+	})
+	return details, nil
+}
+
+// setNodeAddresses sets the addresses of the nodes on the network of the cluster, instead of the first network
+// they are attached to, as the nodes of peered clusters are attached to the peering network too.
+// Stopped nodes have no addresses.
+func (k *kindProvider) setNodeAddresses(details []nodeDetail, network string) error {
+	runtime := container.NewRuntime(k.opts.Provider)
+	for i := range details {
+		ipv4, ipv6, err := runtime.NetworkAddresses(details[i].Name, network)
+		if err != nil {
+			return err
+		}
+		details[i].IPv4Address, details[i].IPv6Address = ipv4, ipv6
+	}
+	return nil
+}
+
+// setPortMappings sets the port mappings of the node from the port bindings of the node container
+func (d *nodeDetail) setPortMappings(bindings []container.PortBinding) {
+	for _, binding := range bindings {
+		d.PortMappings = append(d.PortMappings, nodePortMapping{
+			ContainerPort: binding.ContainerPort,
+			Protocol:      binding.Protocol,
+			ListenAddress: binding.HostIP,
+			HostPort:      binding.HostPort,
+		})
+
+		// ports published on all IPv4 and IPv6 addresses are bound twice, the IPv4 address sorts first
+		key := fmt.Sprintf("%d/%s", binding.ContainerPort, binding.Protocol)
+		if _, set := d.HostPorts[key]; set {
+			continue
+		}
+		hostIP := binding.HostIP
+		if hostIP == "" {
+			hostIP = "0.0.0.0"
+		}
+		if d.HostPorts == nil {
+			d.HostPorts = map[string]string{}
+		}
+		d.HostPorts[key] = net.JoinHostPort(hostIP, strconv.Itoa(binding.HostPort))
+	}
+}
+
+// toOutputValue converts v to the plain values used in resource property maps
+func toOutputValue(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	if err = json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
@@ -0,0 +1,29 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/frezbo/pulumi-provider-kind/provider/v3/pkg/container"
+)
+
+func TestSetPortMappings(t *testing.T) {
+	detail := &nodeDetail{Name: "dev-control-plane"}
+	detail.setPortMappings([]container.PortBinding{
+		{ContainerPort: 53, Protocol: "udp", HostIP: "", HostPort: 40053},
+		{ContainerPort: 80, Protocol: "tcp", HostIP: "0.0.0.0", HostPort: 32768},
+		{ContainerPort: 80, Protocol: "tcp", HostIP: "::", HostPort: 32768},
+		{ContainerPort: 6443, Protocol: "tcp", HostIP: "127.0.0.1", HostPort: 38211},
+	})
+	expected := map[string]string{
+		"53/udp":   "0.0.0.0:40053",
+		"80/tcp":   "0.0.0.0:32768",
+		"6443/tcp": "127.0.0.1:38211",
+	}
+	if !reflect.DeepEqual(detail.HostPorts, expected) {
+		t.Errorf("expected %v, got %v", expected, detail.HostPorts)
+	}
+	if len(detail.PortMappings) != 4 {
+		t.Errorf("expected every binding in the port mappings, got %v", detail.PortMappings)
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/frezbo/pulumi-provider-kind/provider/v3/pkg/container"
+	"github.com/pkg/errors"
+	pulumilog "github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+	rpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+	"sigs.k8s.io/kind/pkg/apis/config/v1alpha4"
+)
+
+// loadBalancerImage is the image KIND uses for the load balancer in front of multiple control planes
+// ref: https://github.com/kubernetes-sigs/kind/blob/v0.11.1/pkg/cluster/internal/loadbalancer/const.go#L20
+const loadBalancerImage = "kindest/haproxy:v20200708-548e36db"
+
+// requiredImage is an image KIND needs and the property it comes from
+type requiredImage struct {
+	image    string
+	property string
+}
+
+// requiredImages returns the images KIND needs for the config, in node order
+// ref: https://github.com/kubernetes-sigs/kind/blob/v0.11.1/pkg/cluster/internal/providers/common/images.go#L28
+func (k *kindProvider) requiredImages(config *v1alpha4.Cluster) []requiredImage {
+	var images []requiredImage
+	controlPlanes := 0
+	for i, node := range desiredNodes(config) {
+		// the single default node is not part of the inputs
+		property := "nodes"
+		if len(config.Nodes) > 0 {
+			property = fmt.Sprintf("nodes[%d].image", i)
+		}
+		images = append(images, requiredImage{image: k.nodeImage(node), property: property})
+		if node.Role == v1alpha4.ControlPlaneRole {
+			controlPlanes++
+		}
+	}
+	if controlPlanes > 1 {
+		images = append(images, requiredImage{image: loadBalancerImage, property: "nodes"})
+	}
+	return images
+}
+
+// missingImages returns the required images of the config that are not available locally.
+// KIND only pulls images that are missing, so there's nothing to pull when all of them are there.
+func (k *kindProvider) missingImages(config *v1alpha4.Cluster) []requiredImage {
+	runtime := container.NewRuntime(k.opts.Provider)
+	checked := map[string]bool{}
+	var missing []requiredImage
+	for _, required := range k.requiredImages(config) {
+		if checked[required.image] {
+			continue
+		}
+		checked[required.image] = true
+		if _, err := runtime.ImageID(required.image); err != nil {
+			missing = append(missing, required)
+		}
+	}
+	return missing
+}
+
+// offlineImageMissing checks if the image would have to be pulled in offline mode
+func (k *kindProvider) offlineImageMissing(image string) bool {
+	if !k.opts.Offline {
+		return false
+	}
+	_, err := container.NewRuntime(k.opts.Provider).ImageID(image)
+	return err != nil
+}
+
+// checkOfflineImages fails the check for every required image that would have to be pulled in offline mode
+func (k *kindProvider) checkOfflineImages(config *v1alpha4.Cluster) []*rpc.CheckFailure {
+	if !k.opts.Offline || k.unknownConfig {
+		return nil
+	}
+	var failures []*rpc.CheckFailure
+	for _, required := range k.missingImages(config) {
+		reason := fmt.Sprintf("image %s is not available locally and the provider is offline", required.image)
+		if required.image == k.opts.NodeImage {
+			reason += ", it is set by the nodeImage provider config"
+		}
+		failures = append(failures, &rpc.CheckFailure{
+			Property: required.property,
+			Reason:   reason,
+		})
+	}
+	return failures
+}
+
+// verifyOfflineImages makes sure nothing would be pulled in offline mode
+func (k *kindProvider) verifyOfflineImages(config *v1alpha4.Cluster) error {
+	if !k.opts.Offline {
+		return nil
+	}
+	missing := k.missingImages(config)
+	if len(missing) == 0 {
+		return nil
+	}
+	images := make([]string, 0, len(missing))
+	for _, required := range missing {
+		images = append(images, required.image)
+	}
+	return errors.Errorf("images %s are not available locally and the provider is offline", strings.Join(images, ", "))
+}
+
+// pullMissingImages pulls the node images that are not available locally, like KIND does before creating the nodes
+func (k *kindProvider) pullMissingImages(config *v1alpha4.Cluster) error {
+	runtime := container.NewRuntime(k.opts.Provider)
+	for _, required := range k.missingImages(config) {
+		pulumilog.V(3).Infof("pulling image %s", required.image)
+		if err := runtime.Pull(required.image); err != nil {
+			return errors.Wrapf(err, "failed to pull image %s", required.image)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/apis/config/v1alpha4"
+)
+
+func TestRequiredImages(t *testing.T) {
+	k := &kindProvider{}
+	config := &v1alpha4.Cluster{Nodes: []v1alpha4.Node{
+		{Role: v1alpha4.ControlPlaneRole, Image: "kindest/node:v1.21.1"},
+		{Role: v1alpha4.ControlPlaneRole},
+	}}
+	expected := []requiredImage{
+		{image: "kindest/node:v1.21.1", property: "nodes[0].image"},
+		{image: k.nodeImage(v1alpha4.Node{}), property: "nodes[1].image"},
+		{image: loadBalancerImage, property: "nodes"},
+	}
+	if images := k.requiredImages(config); !reflect.DeepEqual(images, expected) {
+		t.Errorf("expected %v, got %v", expected, images)
+	}
+
+	// the provider node image applies to every node
+	k.opts.NodeImage = "kindest/node:v1.20.7"
+	expected = []requiredImage{{image: "kindest/node:v1.20.7", property: "nodes"}}
+	if images := k.requiredImages(&v1alpha4.Cluster{}); !reflect.DeepEqual(images, expected) {
+		t.Errorf("expected %v, got %v", expected, images)
+	}
+}
@@ -0,0 +1,819 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/frezbo/pulumi-provider-kind/provider/v3/pkg/container"
+	pbempty "github.com/golang/protobuf/ptypes/empty"
+	structpb "github.com/golang/protobuf/ptypes/struct"
+	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	pulumilog "github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+	rpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+	"sigs.k8s.io/kind/pkg/apis/config/v1alpha4"
+	"sigs.k8s.io/kind/pkg/cluster"
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
+	"sigs.k8s.io/kind/pkg/exec"
+	"sigs.k8s.io/yaml"
+)
+
+const clusterPeeringType = tokens.Type("kind:network:ClusterPeering")
+
+// clusterPeering are the inputs and outputs of a ClusterPeering
+type clusterPeering struct {
+	// ClusterNames are the clusters whose pod and service subnets are routed between each other
+	ClusterNames []string `json:"clusterNames"`
+	// Network is the docker/podman network the nodes of the clusters are attached to
+	Network string `json:"network"`
+	// Subnet is the IPv4 subnet of the network when it is created
+	Subnet string `json:"subnet,omitempty"`
+	// Clusters are the peered clusters as of the last update or refresh
+	Clusters []peeredCluster `json:"clusters,omitempty"`
+}
+
+// peeredCluster is a cluster attached to the peering network
+type peeredCluster struct {
+	Name          string       `json:"name"`
+	PodSubnet     string       `json:"podSubnet"`
+	ServiceSubnet string       `json:"serviceSubnet"`
+	Nodes         []peeredNode `json:"nodes"`
+}
+
+// peeredNode is a node attached to the peering network with the pod CIDRs assigned to it
+type peeredNode struct {
+	Name        string   `json:"name"`
+	IPv4Address string   `json:"ipv4Address,omitempty"`
+	IPv6Address string   `json:"ipv6Address,omitempty"`
+	PodCIDRs    []string `json:"podCidrs,omitempty"`
+}
+
+// peeringRoute is a static route of a node to a subnet of another cluster
+type peeringRoute struct {
+	cidr string
+	via  string
+}
+
+func propMapToClusterPeering(props resource.PropertyMap) (*clusterPeering, error) {
+	peering := &clusterPeering{}
+	data, err := json.Marshal(props.Mappable())
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(data, peering); err != nil {
+		return nil, err
+	}
+	return peering, nil
+}
+
+func (p *clusterPeering) marshal(label string) (*structpb.Struct, error) {
+	value, err := toOutputValue(p)
+	if err != nil {
+		return nil, err
+	}
+	return plugin.MarshalProperties(
+		resource.NewPropertyMapFromMap(value.(map[string]interface{})),
+		plugin.MarshalOptions{Label: label, KeepUnknowns: true, SkipNulls: true},
+	)
+}
+
+// peeredClusterNames returns the names of the peered clusters
+func (p *clusterPeering) peeredClusterNames() []string {
+	var names []string
+	for _, c := range p.Clusters {
+		names = append(names, c.Name)
+	}
+	return names
+}
+
+// splitCIDRs splits the comma separated subnets of dual stack clusters
+func splitCIDRs(subnets string) []string {
+	var cidrs []string
+	for _, cidr := range strings.Split(subnets, ",") {
+		if cidr = strings.TrimSpace(cidr); cidr != "" {
+			cidrs = append(cidrs, cidr)
+		}
+	}
+	return cidrs
+}
+
+// checkPeeringOverlaps makes sure the pod and service subnets of the clusters and the subnets of the network
+// are distinct, as the nodes could not tell where to route the overlapping addresses
+func checkPeeringOverlaps(clusters []peeredCluster, network string, networkSubnets []string) error {
+	type subnet struct {
+		cidr  *net.IPNet
+		owner string
+	}
+	var subnets []subnet
+	add := func(cidr, owner string) error {
+		_, parsed, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return errors.Wrapf(err, "invalid %s", owner)
+		}
+		for _, other := range subnets {
+			if parsed.Contains(other.cidr.IP) || other.cidr.Contains(parsed.IP) {
+				return errors.Errorf("%s overlaps with %s", owner, other.owner)
+			}
+		}
+		subnets = append(subnets, subnet{cidr: parsed, owner: owner})
+		return nil
+	}
+
+	for _, cidr := range networkSubnets {
+		if err := add(cidr, fmt.Sprintf("subnet %s of network %s", cidr, network)); err != nil {
+			return err
+		}
+	}
+	for _, c := range clusters {
+		for _, cidr := range splitCIDRs(c.PodSubnet) {
+			if err := add(cidr, fmt.Sprintf("podSubnet %s of cluster %s", cidr, c.Name)); err != nil {
+				return err
+			}
+		}
+		for _, cidr := range splitCIDRs(c.ServiceSubnet) {
+			if err := add(cidr, fmt.Sprintf("serviceSubnet %s of cluster %s", cidr, c.Name)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// routeVia returns the address of the node on the peering network of the same IP family as the subnet
+func routeVia(node peeredNode, cidr string) string {
+	ip, _, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return ""
+	}
+	if ip.To4() != nil {
+		return node.IPv4Address
+	}
+	return node.IPv6Address
+}
+
+// clusterRoutes returns the routes to the subnets of the cluster: the pod CIDRs of every node via the node, and
+// the service subnet via the first node, where kube-proxy forwards the traffic like on any other node
+func clusterRoutes(c peeredCluster) []peeringRoute {
+	var routes []peeringRoute
+	for _, node := range c.Nodes {
+		for _, cidr := range node.PodCIDRs {
+			if via := routeVia(node, cidr); via != "" {
+				routes = append(routes, peeringRoute{cidr: cidr, via: via})
+			}
+		}
+	}
+	for _, cidr := range splitCIDRs(c.ServiceSubnet) {
+		for _, node := range c.Nodes {
+			if via := routeVia(node, cidr); via != "" {
+				routes = append(routes, peeringRoute{cidr: cidr, via: via})
+				break
+			}
+		}
+	}
+	return routes
+}
+
+// peeringRoutes returns the routes of every node to the subnets of the other clusters, by node name
+func peeringRoutes(clusters []peeredCluster) map[string][]peeringRoute {
+	routes := map[string][]peeringRoute{}
+	for i, c := range clusters {
+		var remote []peeringRoute
+		for j, other := range clusters {
+			if i != j {
+				remote = append(remote, clusterRoutes(other)...)
+			}
+		}
+		for _, node := range c.Nodes {
+			routes[node.Name] = remote
+		}
+	}
+	return routes
+}
+
+// routeCommand returns the ip route command for the route
+func routeCommand(node nodes.Node, action string, route peeringRoute) exec.Cmd {
+	args := []string{"route", action, route.cidr, "via", route.via}
+	if ip, _, err := net.ParseCIDR(route.cidr); err == nil && ip.To4() == nil {
+		args = append([]string{"-6"}, args...)
+	}
+	return node.Command("ip", args...)
+}
+
+// normalizeRoute returns the route the way ip route show prints it, host routes are printed without a prefix length
+func normalizeRoute(route peeringRoute) peeringRoute {
+	cidr := route.cidr
+	if !strings.Contains(cidr, "/") {
+		if ip := net.ParseIP(cidr); ip != nil && ip.To4() != nil {
+			cidr += "/32"
+		} else {
+			cidr += "/128"
+		}
+	}
+	if _, parsed, err := net.ParseCIDR(cidr); err == nil {
+		route.cidr = parsed.String()
+	}
+	if ip := net.ParseIP(route.via); ip != nil {
+		route.via = ip.String()
+	}
+	return route
+}
+
+// parseRoutes returns the gateway routes printed by ip route show
+func parseRoutes(lines []string) map[peeringRoute]bool {
+	routes := map[peeringRoute]bool{}
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) < 3 || fields[0] == "default" || fields[1] != "via" {
+			continue
+		}
+		routes[normalizeRoute(peeringRoute{cidr: fields[0], via: fields[2]})] = true
+	}
+	return routes
+}
+
+// hasRoutes checks if the routes are installed on the node, stopped nodes have none
+func hasRoutes(node nodes.Node, routes []peeringRoute) bool {
+	families := map[string]bool{}
+	for _, route := range routes {
+		if ip, _, err := net.ParseCIDR(route.cidr); err == nil && ip.To4() == nil {
+			families["-6"] = true
+		} else {
+			families["-4"] = true
+		}
+	}
+	var lines []string
+	for family := range families {
+		out, err := exec.OutputLines(node.Command("ip", family, "route", "show"))
+		if err != nil {
+			return false
+		}
+		lines = append(lines, out...)
+	}
+	installed := parseRoutes(lines)
+	for _, route := range routes {
+		if !installed[normalizeRoute(route)] {
+			return false
+		}
+	}
+	return true
+}
+
+// routedClusters returns the clusters whose nodes all have their routes to the other clusters installed.
+// The routes are gone once a node container restarts, the next update installs them again.
+func routedClusters(provider *cluster.Provider, clusters []peeredCluster) ([]peeredCluster, error) {
+	routes := peeringRoutes(clusters)
+	var routed []peeredCluster
+	for _, c := range clusters {
+		internalNodes, err := selectNodes(provider, c.Name, nil)
+		if err != nil {
+			return nil, err
+		}
+		complete := true
+		for _, node := range internalNodes {
+			if !hasRoutes(node, routes[node.String()]) {
+				pulumilog.V(3).Infof("node %s is missing routes to the peered clusters", node.String())
+				complete = false
+				break
+			}
+		}
+		if complete {
+			routed = append(routed, c)
+		}
+	}
+	return routed, nil
+}
+
+// inspectPeeredCluster reads the subnets of the cluster and the addresses of its nodes on the network
+func inspectPeeredCluster(provider *cluster.Provider, runtime *container.Runtime, clusterName, network string) (*peeredCluster, error) {
+	exists, err := clusterExists(provider, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.Errorf("KIND cluster %s does not exist", clusterName)
+	}
+	internalNodes, err := selectNodes(provider, clusterName, nil)
+	if err != nil {
+		return nil, err
+	}
+	controlPlane, err := nodeutils.BootstrapControlPlaneNode(internalNodes)
+	if err != nil {
+		return nil, err
+	}
+
+	lines, err := exec.OutputLines(controlPlane.Command("kubectl", "--kubeconfig=/etc/kubernetes/admin.conf",
+		"get", "configmap", "kubeadm-config", "--namespace=kube-system", "--output=jsonpath={.data.ClusterConfiguration}"))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read kubeadm config of KIND cluster %s", clusterName)
+	}
+	var clusterConfiguration struct {
+		Networking struct {
+			PodSubnet     string `json:"podSubnet"`
+			ServiceSubnet string `json:"serviceSubnet"`
+		} `json:"networking"`
+	}
+	if err = yaml.Unmarshal([]byte(strings.Join(lines, "\n")), &clusterConfiguration); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode kubeadm config of KIND cluster %s", clusterName)
+	}
+
+	lines, err = exec.OutputLines(controlPlane.Command("kubectl", "--kubeconfig=/etc/kubernetes/admin.conf",
+		"get", "nodes", "--output=json"))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list nodes of KIND cluster %s", clusterName)
+	}
+	var nodeList struct {
+		Items []struct {
+			Metadata struct {
+				Name string `json:"name"`
+			} `json:"metadata"`
+			Spec struct {
+				PodCIDR  string   `json:"podCIDR"`
+				PodCIDRs []string `json:"podCIDRs"`
+			} `json:"spec"`
+		} `json:"items"`
+	}
+	if err = json.Unmarshal([]byte(strings.Join(lines, "\n")), &nodeList); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode nodes of KIND cluster %s", clusterName)
+	}
+	podCIDRs := map[string][]string{}
+	for _, item := range nodeList.Items {
+		// podCIDRs is only set from Kubernetes 1.16 on
+		cidrs := item.Spec.PodCIDRs
+		if len(cidrs) == 0 && item.Spec.PodCIDR != "" {
+			cidrs = []string{item.Spec.PodCIDR}
+		}
+		podCIDRs[item.Metadata.Name] = cidrs
+	}
+
+	peered := &peeredCluster{
+		Name:          clusterName,
+		PodSubnet:     clusterConfiguration.Networking.PodSubnet,
+		ServiceSubnet: clusterConfiguration.Networking.ServiceSubnet,
+	}
+	for _, node := range internalNodes {
+		ipv4, ipv6, err := runtime.NetworkAddresses(node.String(), network)
+		if err != nil {
+			return nil, err
+		}
+		peered.Nodes = append(peered.Nodes, peeredNode{
+			Name:        node.String(),
+			IPv4Address: ipv4,
+			IPv6Address: ipv6,
+			PodCIDRs:    podCIDRs[node.String()],
+		})
+	}
+	sort.Slice(peered.Nodes, func(i, j int) bool {
+		return peered.Nodes[i].Name < peered.Nodes[j].Name
+	})
+	return peered, nil
+}
+
+// connected checks if all the nodes of the cluster have an address on the network
+func (c *peeredCluster) connected() bool {
+	for _, node := range c.Nodes {
+		if node.IPv4Address == "" && node.IPv6Address == "" {
+			return false
+		}
+	}
+	return len(c.Nodes) > 0
+}
+
+// sameNodes checks if the cluster still has the nodes it was peered with. Workers added or removed in place
+// since need to be attached to the network and routed to, or have their routes removed from the other clusters.
+func (c *peeredCluster) sameNodes(peered peeredCluster) bool {
+	if len(c.Nodes) != len(peered.Nodes) {
+		return false
+	}
+	for i := range c.Nodes {
+		if c.Nodes[i].Name != peered.Nodes[i].Name {
+			return false
+		}
+	}
+	return true
+}
+
+// connectPeeredCluster attaches the nodes of the cluster that are not attached yet to the network
+func connectPeeredCluster(runtime *container.Runtime, c *peeredCluster, network string) error {
+	for i, node := range c.Nodes {
+		if node.IPv4Address != "" || node.IPv6Address != "" {
+			continue
+		}
+		pulumilog.V(3).Infof("attaching node %s to network %s", node.Name, network)
+		if err := runtime.ConnectNetwork(network, node.Name); err != nil {
+			return errors.Wrapf(err, "failed to attach node %s to network %s", node.Name, network)
+		}
+		ipv4, ipv6, err := runtime.NetworkAddresses(node.Name, network)
+		if err != nil {
+			return err
+		}
+		c.Nodes[i].IPv4Address, c.Nodes[i].IPv6Address = ipv4, ipv6
+	}
+	return nil
+}
+
+// reconcilePeering removes the routes of the old clusters that are not wanted anymore, detaches the nodes of the
+// clusters that are not peered anymore and installs the routes of the new clusters. Clusters that are gone are skipped.
+func reconcilePeering(provider *cluster.Provider, runtime *container.Runtime, network string, olds, news []peeredCluster) error {
+	nodesByName := map[string]nodes.Node{}
+	wanted := map[string]bool{}
+	for _, c := range news {
+		wanted[c.Name] = true
+	}
+	for _, c := range append(append([]peeredCluster{}, olds...), news...) {
+		exists, err := clusterExists(provider, c.Name)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			continue
+		}
+		internalNodes, err := selectNodes(provider, c.Name, nil)
+		if err != nil {
+			return err
+		}
+		for _, node := range internalNodes {
+			nodesByName[node.String()] = node
+		}
+	}
+
+	oldRoutes, newRoutes := peeringRoutes(olds), peeringRoutes(news)
+	for name, routes := range oldRoutes {
+		node, ok := nodesByName[name]
+		if !ok {
+			continue
+		}
+		keep := map[peeringRoute]bool{}
+		for _, route := range newRoutes[name] {
+			keep[route] = true
+		}
+		for _, route := range routes {
+			if !keep[route] {
+				// the route is gone already when the node was restarted
+				_ = routeCommand(node, "del", route).Run()
+			}
+		}
+	}
+
+	for _, c := range olds {
+		if wanted[c.Name] {
+			continue
+		}
+		for _, n := range c.Nodes {
+			if _, ok := nodesByName[n.Name]; !ok {
+				continue
+			}
+			pulumilog.V(3).Infof("detaching node %s from network %s", n.Name, network)
+			if err := runtime.DisconnectNetwork(network, n.Name); err != nil {
+				return errors.Wrapf(err, "failed to detach node %s from network %s", n.Name, network)
+			}
+		}
+	}
+
+	for name, routes := range newRoutes {
+		node, ok := nodesByName[name]
+		if !ok {
+			return errors.Errorf("unknown node %s", name)
+		}
+		for _, route := range routes {
+			if lines, err := exec.CombinedOutputLines(routeCommand(node, "replace", route)); err != nil {
+				return errors.Wrapf(err, "failed to add route to %s via %s on %s: %s", route.cidr, route.via, name, strings.Join(lines, "\n"))
+			}
+		}
+	}
+	return nil
+}
+
+// peerClusters attaches the clusters to the network and routes their subnets between each other,
+// after making sure the subnets don't overlap
+func (k *kindProvider) peerClusters(urn resource.URN, peering *clusterPeering, olds []peeredCluster) error {
+	provider := k.newClusterProvider(urn)
+	runtime := container.NewRuntime(k.opts.Provider)
+
+	var clusters []peeredCluster
+	for _, clusterName := range peering.ClusterNames {
+		c, err := inspectPeeredCluster(provider, runtime, clusterName, peering.Network)
+		if err != nil {
+			return err
+		}
+		clusters = append(clusters, *c)
+	}
+	networkSubnets, err := runtime.NetworkSubnets(peering.Network)
+	if err != nil {
+		return err
+	}
+	if err = checkPeeringOverlaps(clusters, peering.Network, networkSubnets); err != nil {
+		return err
+	}
+
+	for i := range clusters {
+		if err = connectPeeredCluster(runtime, &clusters[i], peering.Network); err != nil {
+			return err
+		}
+	}
+	if err = reconcilePeering(provider, runtime, peering.Network, olds, clusters); err != nil {
+		return err
+	}
+	peering.Clusters = clusters
+	return nil
+}
+
+func (k *kindProvider) checkClusterPeering(req *rpc.CheckRequest) (*rpc.CheckResponse, error) {
+	urn := resource.URN(req.GetUrn())
+	label := fmt.Sprintf("%s.Check(%s)", k.name, urn)
+
+	news, err := plugin.UnmarshalProperties(req.GetNews(), plugin.MarshalOptions{
+		Label:        fmt.Sprintf("%s.news", label),
+		KeepUnknowns: true,
+		SkipNulls:    true,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "check failed because malformed resource inputs")
+	}
+	if _, ok := news["network"]; !ok {
+		news["network"] = resource.NewStringProperty("kind-peering")
+	}
+
+	var failures []*rpc.CheckFailure
+	if names := news["clusterNames"]; names.IsArray() && !names.ContainsUnknowns() {
+		seen := map[string]bool{}
+		for i, name := range names.ArrayValue() {
+			if !name.IsString() {
+				continue
+			}
+			if seen[name.StringValue()] {
+				failures = append(failures, &rpc.CheckFailure{
+					Property: fmt.Sprintf("clusterNames[%d]", i),
+					Reason:   fmt.Sprintf("cluster %s is listed more than once", name.StringValue()),
+				})
+			}
+			seen[name.StringValue()] = true
+		}
+		if len(seen) < 2 {
+			failures = append(failures, &rpc.CheckFailure{
+				Property: "clusterNames",
+				Reason:   "at least two clusters are needed for a peering",
+			})
+		}
+	}
+	if network := news["network"]; network.IsString() && !networkNameRE.MatchString(network.StringValue()) {
+		failures = append(failures, &rpc.CheckFailure{
+			Property: "network",
+			Reason:   fmt.Sprintf("%q is not a valid network name", network.StringValue()),
+		})
+	}
+	if subnet := news["subnet"]; subnet.IsString() {
+		if ip, _, err := net.ParseCIDR(subnet.StringValue()); err != nil || ip.To4() == nil {
+			failures = append(failures, &rpc.CheckFailure{
+				Property: "subnet",
+				Reason:   fmt.Sprintf("%q is not an IPv4 CIDR", subnet.StringValue()),
+			})
+		}
+	}
+
+	inputs, err := plugin.MarshalProperties(news, plugin.MarshalOptions{
+		Label:        fmt.Sprintf("%s.news", label),
+		KeepUnknowns: true,
+		SkipNulls:    true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &rpc.CheckResponse{Inputs: inputs, Failures: failures}, nil
+}
+
+func (k *kindProvider) diffClusterPeering(req *rpc.DiffRequest) (*rpc.DiffResponse, error) {
+	label := fmt.Sprintf("%s.Diff(%s)", k.name, req.GetUrn())
+
+	olds, err := plugin.UnmarshalProperties(req.GetOlds(), plugin.MarshalOptions{
+		Label:        fmt.Sprintf("%s.olds", label),
+		KeepUnknowns: true,
+		SkipNulls:    true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	news, err := plugin.UnmarshalProperties(req.GetNews(), plugin.MarshalOptions{
+		Label:        fmt.Sprintf("%s.news", label),
+		KeepUnknowns: true,
+		SkipNulls:    true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var replaces []string
+	for _, key := range []resource.PropertyKey{"network", "subnet"} {
+		if !olds[key].DeepEquals(news[key]) {
+			replaces = append(replaces, string(key))
+		}
+	}
+	if len(replaces) > 0 {
+		return &rpc.DiffResponse{
+			Changes:             rpc.DiffResponse_DIFF_SOME,
+			Replaces:            replaces,
+			DeleteBeforeReplace: true,
+		}, nil
+	}
+
+	// clusters recreated or scaled since they were peered show up as drift after a refresh
+	oldPeering, err := propMapToClusterPeering(olds)
+	if err != nil {
+		return nil, err
+	}
+	peered := resource.NewPropertyValue(oldPeering.peeredClusterNames())
+	if !olds["clusterNames"].DeepEquals(news["clusterNames"]) || !peered.DeepEquals(news["clusterNames"]) {
+		return &rpc.DiffResponse{
+			Changes: rpc.DiffResponse_DIFF_SOME,
+			Diffs:   []string{"clusterNames"},
+		}, nil
+	}
+	return &rpc.DiffResponse{Changes: rpc.DiffResponse_DIFF_NONE}, nil
+}
+
+func (k *kindProvider) createClusterPeering(req *rpc.CreateRequest) (*rpc.CreateResponse, error) {
+	urn := resource.URN(req.GetUrn())
+	label := fmt.Sprintf("%s.Create(%s)", k.name, urn)
+
+	news, err := plugin.UnmarshalProperties(req.GetProperties(), plugin.MarshalOptions{
+		Label:        fmt.Sprintf("%s.news", label),
+		KeepUnknowns: true,
+		SkipNulls:    true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetPreview() {
+		news["clusters"] = resource.MakeComputed(resource.NewStringProperty(""))
+		properties, err := plugin.MarshalProperties(news, plugin.MarshalOptions{KeepUnknowns: true, SkipNulls: true})
+		if err != nil {
+			return nil, err
+		}
+		return &rpc.CreateResponse{Properties: properties}, nil
+	}
+
+	peering, err := propMapToClusterPeering(news)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Create():")
+	}
+	if err = k.ensureNetwork(&networkSettings{Name: peering.Network, Subnet: peering.Subnet}, &v1alpha4.Cluster{}); err != nil {
+		return nil, err
+	}
+	if err = k.peerClusters(urn, peering, nil); err != nil {
+		return nil, err
+	}
+
+	properties, err := peering.marshal(fmt.Sprintf("%s.outputs", label))
+	if err != nil {
+		return nil, err
+	}
+	return &rpc.CreateResponse{
+		Id:         peering.Network,
+		Properties: properties,
+	}, nil
+}
+
+func (k *kindProvider) readClusterPeering(req *rpc.ReadRequest) (*rpc.ReadResponse, error) {
+	urn := resource.URN(req.GetUrn())
+	label := fmt.Sprintf("%s.Read(%s)", k.name, urn)
+
+	properties, err := plugin.UnmarshalProperties(req.GetProperties(), plugin.MarshalOptions{
+		Label:        fmt.Sprintf("%s.properties", label),
+		KeepUnknowns: true,
+		SkipNulls:    true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	peering, err := propMapToClusterPeering(properties)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Read():")
+	}
+
+	runtime := container.NewRuntime(k.opts.Provider)
+	if !runtime.NetworkExists(peering.Network) {
+		return &rpc.ReadResponse{}, nil
+	}
+
+	// only the clusters with all their nodes still attached and no nodes added or removed are peered
+	provider := k.newClusterProvider(urn)
+	peered := peering.Clusters
+	peering.Clusters = nil
+	for _, c := range peered {
+		refreshed, err := inspectPeeredCluster(provider, runtime, c.Name, peering.Network)
+		if err != nil || !refreshed.connected() || !refreshed.sameNodes(c) {
+			continue
+		}
+		peering.Clusters = append(peering.Clusters, *refreshed)
+	}
+	if peering.Clusters, err = routedClusters(provider, peering.Clusters); err != nil {
+		return nil, err
+	}
+
+	outputs, err := peering.marshal(fmt.Sprintf("%s.outputs", label))
+	if err != nil {
+		return nil, err
+	}
+	return &rpc.ReadResponse{Id: req.GetId(), Properties: outputs}, nil
+}
+
+func (k *kindProvider) updateClusterPeering(req *rpc.UpdateRequest) (*rpc.UpdateResponse, error) {
+	urn := resource.URN(req.GetUrn())
+	label := fmt.Sprintf("%s.Update(%s)", k.name, urn)
+
+	olds, err := plugin.UnmarshalProperties(req.GetOlds(), plugin.MarshalOptions{
+		Label:        fmt.Sprintf("%s.olds", label),
+		KeepUnknowns: true,
+		SkipNulls:    true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	news, err := plugin.UnmarshalProperties(req.GetNews(), plugin.MarshalOptions{
+		Label:        fmt.Sprintf("%s.news", label),
+		KeepUnknowns: true,
+		SkipNulls:    true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetPreview() {
+		news["clusters"] = resource.MakeComputed(resource.NewStringProperty(""))
+		properties, err := plugin.MarshalProperties(news, plugin.MarshalOptions{KeepUnknowns: true, SkipNulls: true})
+		if err != nil {
+			return nil, err
+		}
+		return &rpc.UpdateResponse{Properties: properties}, nil
+	}
+
+	oldPeering, err := propMapToClusterPeering(olds)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Update():")
+	}
+	peering, err := propMapToClusterPeering(news)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Update():")
+	}
+
+	// Diff only allows the clusters to change in place
+	if err = k.peerClusters(urn, peering, oldPeering.Clusters); err != nil {
+		return nil, err
+	}
+
+	properties, err := peering.marshal(fmt.Sprintf("%s.outputs", label))
+	if err != nil {
+		return nil, err
+	}
+	return &rpc.UpdateResponse{Properties: properties}, nil
+}
+
+func (k *kindProvider) deleteClusterPeering(req *rpc.DeleteRequest) (*pbempty.Empty, error) {
+	urn := resource.URN(req.GetUrn())
+	label := fmt.Sprintf("%s.Delete(%s)", k.name, urn)
+
+	properties, err := plugin.UnmarshalProperties(req.GetProperties(), plugin.MarshalOptions{
+		Label:        fmt.Sprintf("%s.properties", label),
+		KeepUnknowns: true,
+		SkipNulls:    true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	peering, err := propMapToClusterPeering(properties)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Delete():")
+	}
+
+	runtime := container.NewRuntime(k.opts.Provider)
+	if !runtime.NetworkExists(peering.Network) {
+		return &pbempty.Empty{}, nil
+	}
+	if err = reconcilePeering(k.newClusterProvider(urn), runtime, peering.Network, peering.Clusters, nil); err != nil {
+		return nil, err
+	}
+	if err = k.removeUnusedNetwork(&networkSettings{Name: peering.Network}); err != nil {
+		return nil, err
+	}
+	return &pbempty.Empty{}, nil
+}
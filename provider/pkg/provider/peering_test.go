@@ -0,0 +1,150 @@
+package provider
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	rpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+)
+
+var peeredClusters = []peeredCluster{
+	{
+		Name:          "east",
+		PodSubnet:     "10.244.0.0/16",
+		ServiceSubnet: "10.96.0.0/16",
+		Nodes: []peeredNode{
+			{Name: "east-control-plane", IPv4Address: "172.30.0.2", PodCIDRs: []string{"10.244.0.0/24"}},
+			{Name: "east-worker", IPv4Address: "172.30.0.3", PodCIDRs: []string{"10.244.1.0/24"}},
+		},
+	},
+	{
+		Name:          "west",
+		PodSubnet:     "10.245.0.0/16,fd00:10:245::/56",
+		ServiceSubnet: "10.97.0.0/16,fd00:10:97::/112",
+		Nodes: []peeredNode{
+			{
+				Name:        "west-control-plane",
+				IPv4Address: "172.30.0.4",
+				IPv6Address: "fc00:f853:ccd:e793::4",
+				PodCIDRs:    []string{"10.245.0.0/24", "fd00:10:245::/64"},
+			},
+		},
+	},
+}
+
+func TestPeeringRoutes(t *testing.T) {
+	routes := peeringRoutes(peeredClusters)
+	toWest := []peeringRoute{
+		{cidr: "10.245.0.0/24", via: "172.30.0.4"},
+		{cidr: "fd00:10:245::/64", via: "fc00:f853:ccd:e793::4"},
+		{cidr: "10.97.0.0/16", via: "172.30.0.4"},
+		{cidr: "fd00:10:97::/112", via: "fc00:f853:ccd:e793::4"},
+	}
+	toEast := []peeringRoute{
+		{cidr: "10.244.0.0/24", via: "172.30.0.2"},
+		{cidr: "10.244.1.0/24", via: "172.30.0.3"},
+		{cidr: "10.96.0.0/16", via: "172.30.0.2"},
+	}
+	for node, expected := range map[string][]peeringRoute{
+		"east-control-plane": toWest,
+		"east-worker":        toWest,
+		"west-control-plane": toEast,
+	} {
+		if !reflect.DeepEqual(routes[node], expected) {
+			t.Errorf("expected routes %v on %s, got %v", expected, node, routes[node])
+		}
+	}
+	if len(routes) != 3 {
+		t.Errorf("expected routes on 3 nodes, got %v", routes)
+	}
+}
+
+func TestParseRoutes(t *testing.T) {
+	routes := parseRoutes([]string{
+		"default via 172.18.0.1 dev eth0",
+		"10.244.1.0/24 via 172.18.0.3 dev eth0",
+		"10.245.0.0/24 via 172.30.0.4 dev eth1",
+		"172.18.0.0/16 dev eth0 proto kernel scope link src 172.18.0.2",
+		"fd00:10:245::/64 via fc00:f853:ccd:e793::4 dev eth1 metric 1024 pref medium",
+		"10.99.0.1 via 172.30.0.4 dev eth1",
+	})
+	for _, route := range []peeringRoute{
+		{cidr: "10.245.0.0/24", via: "172.30.0.4"},
+		{cidr: "fd00:10:245:0::/64", via: "fc00:f853:ccd:e793:0::4"},
+		{cidr: "10.99.0.1/32", via: "172.30.0.4"},
+	} {
+		if !routes[normalizeRoute(route)] {
+			t.Errorf("expected route to %s via %s in %v", route.cidr, route.via, routes)
+		}
+	}
+	if routes[normalizeRoute(peeringRoute{cidr: "10.97.0.0/16", via: "172.30.0.4"})] {
+		t.Errorf("unexpected route to 10.97.0.0/16 in %v", routes)
+	}
+	if len(routes) != 4 {
+		t.Errorf("expected 4 gateway routes, got %v", routes)
+	}
+}
+
+func TestSameNodes(t *testing.T) {
+	east := peeredClusters[0]
+	if !east.sameNodes(east) {
+		t.Errorf("expected the same nodes")
+	}
+	scaled := east
+	scaled.Nodes = append(append([]peeredNode{}, east.Nodes...), peeredNode{Name: "east-worker2"})
+	if scaled.sameNodes(east) || east.sameNodes(scaled) {
+		t.Errorf("expected added or removed workers to differ")
+	}
+}
+
+func TestCheckPeeringOverlaps(t *testing.T) {
+	if err := checkPeeringOverlaps(peeredClusters, "kind-peering", []string{"172.30.0.0/16"}); err != nil {
+		t.Errorf("expected no overlaps, got %v", err)
+	}
+
+	err := checkPeeringOverlaps(peeredClusters, "kind-peering", []string{"10.0.0.0/8"})
+	if err == nil || !strings.Contains(err.Error(), "podSubnet 10.244.0.0/16 of cluster east overlaps with subnet 10.0.0.0/8 of network kind-peering") {
+		t.Errorf("expected the pod subnet to overlap with the network, got %v", err)
+	}
+
+	clusters := append([]peeredCluster{}, peeredClusters...)
+	clusters = append(clusters, peeredCluster{Name: "north", PodSubnet: "10.244.128.0/17", ServiceSubnet: "10.98.0.0/16"})
+	err = checkPeeringOverlaps(clusters, "kind-peering", nil)
+	if err == nil || !strings.Contains(err.Error(), "podSubnet 10.244.128.0/17 of cluster north overlaps with podSubnet 10.244.0.0/16 of cluster east") {
+		t.Errorf("expected the pod subnets to overlap, got %v", err)
+	}
+}
+
+func TestCheckClusterPeering(t *testing.T) {
+	k := &kindProvider{}
+	news, err := plugin.MarshalProperties(resource.NewPropertyMapFromMap(map[string]interface{}{
+		"clusterNames": []interface{}{"east", "east"},
+		"subnet":       "fd00::/64",
+	}), plugin.MarshalOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := k.checkClusterPeering(&rpc.CheckRequest{Urn: "urn:pulumi:dev::test::kind:network:ClusterPeering::peering", News: news})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"clusterNames[1]", "clusterNames", "subnet"}
+	if len(resp.Failures) != len(expected) {
+		t.Fatalf("expected %d failures, got %v", len(expected), resp.Failures)
+	}
+	for i, failure := range resp.Failures {
+		if failure.Property != expected[i] {
+			t.Errorf("expected failure on %s, got %s", expected[i], failure.Property)
+		}
+	}
+	inputs, err := plugin.UnmarshalProperties(resp.Inputs, plugin.MarshalOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if network := inputs["network"]; !network.IsString() || network.StringValue() != "kind-peering" {
+		t.Errorf("expected the default network kind-peering, got %v", network)
+	}
+}
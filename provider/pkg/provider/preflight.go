@@ -0,0 +1,246 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+
+	"github.com/frezbo/pulumi-provider-kind/provider/v3/pkg/container"
+	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	pulumilog "github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+	rpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+	"sigs.k8s.io/kind/pkg/apis/config/defaults"
+	"sigs.k8s.io/kind/pkg/apis/config/v1alpha4"
+	"sigs.k8s.io/kind/pkg/cluster"
+)
+
+// clusterExists checks if a KIND cluster with the given name already exists
+func clusterExists(provider *cluster.Provider, name string) (bool, error) {
+	clusters, err := provider.List()
+	if err != nil {
+		return false, errors.Wrap(err, "failed to list KIND clusters")
+	}
+	for _, clusterName := range clusters {
+		if clusterName == name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// nodeImage returns the image KIND uses for the node
+func (k *kindProvider) nodeImage(node v1alpha4.Node) string {
+	// same precedence as kind, the provider level image overrides any per node images
+	// ref: https://github.com/kubernetes-sigs/kind/blob/v0.11.1/pkg/cluster/internal/create/create.go#L226
+	if k.opts.NodeImage != "" {
+		return k.opts.NodeImage
+	}
+	if node.Image != "" {
+		return node.Image
+	}
+	return defaults.Image
+}
+
+// desiredNodes returns the nodes KIND creates for the config, defaulting to a single control-plane node
+func desiredNodes(config *v1alpha4.Cluster) []v1alpha4.Node {
+	if len(config.Nodes) == 0 {
+		return []v1alpha4.Node{{Role: v1alpha4.ControlPlaneRole}}
+	}
+	return config.Nodes
+}
+
+// verifyAdoptable checks if an existing cluster matches the config closely enough to be adopted.
+// KIND does not keep the config a cluster was created with, so only the node roles and images are compared.
+func (k *kindProvider) verifyAdoptable(provider *cluster.Provider, config *v1alpha4.Cluster) error {
+	nodes, err := provider.ListInternalNodes(config.Name)
+	if err != nil {
+		return errors.Wrapf(err, "failed to list nodes of existing KIND cluster %s", config.Name)
+	}
+
+	runtime := container.NewRuntime(k.opts.Provider)
+
+	var actual []string
+	for _, node := range nodes {
+		role, err := node.Role()
+		if err != nil {
+			return errors.Wrapf(err, "failed to get role of node %s", node.String())
+		}
+		image, err := nodeContainerImage(runtime, node.String())
+		if err != nil {
+			return err
+		}
+		actual = append(actual, fmt.Sprintf("%s (%s)", role, image))
+	}
+
+	var desired []string
+	for _, node := range desiredNodes(config) {
+		desired = append(desired, fmt.Sprintf("%s (%s)", node.Role, k.nodeImage(node)))
+	}
+
+	sort.Strings(actual)
+	sort.Strings(desired)
+	if fmt.Sprint(actual) != fmt.Sprint(desired) {
+		return errors.Errorf("existing KIND cluster %s has nodes %v, but the config requires %v", config.Name, actual, desired)
+	}
+	return nil
+}
+
+// checkNameCollision reports a failure when a cluster with the same name already exists,
+// unless it's marked to be adopted and matches the config
+func (k *kindProvider) checkNameCollision(urn resource.URN, news resource.PropertyMap, inputs map[string]interface{}) []*rpc.CheckFailure {
+	name, ok := inputs["name"].(string)
+	if !ok || name == "" {
+		return nil
+	}
+	adopt := news["adoptExisting"]
+	if adopt.ContainsUnknowns() {
+		return nil
+	}
+
+	provider := k.newClusterProvider(urn)
+	exists, err := clusterExists(provider, name)
+	if err != nil {
+		// the container runtime might not be reachable during preview, Create checks again anyway
+		pulumilog.V(3).Infof("skipping cluster name collision check for %s: %v", urn, err)
+		return nil
+	}
+	if !exists {
+		return nil
+	}
+
+	if !adopt.IsBool() || !adopt.BoolValue() {
+		return []*rpc.CheckFailure{{
+			Property: "name",
+			Reason:   fmt.Sprintf("a KIND cluster named %s already exists, possibly from another stack. Choose another name or set adoptExisting to take it over", name),
+		}}
+	}
+
+	config, err := propMapToKindClusterConfig(inputs)
+	if err != nil {
+		// some inputs are not known yet, Create verifies the cluster before adopting it
+		return nil
+	}
+	if err := k.verifyAdoptable(provider, config); err != nil {
+		return []*rpc.CheckFailure{{
+			Property: "adoptExisting",
+			Reason:   fmt.Sprintf("cannot adopt existing KIND cluster: %v", err),
+		}}
+	}
+	return nil
+}
+
+// hostPortInUse tries to bind the host port the way the container runtime does when publishing it,
+// SCTP ports can't be bound from Go and are assumed to be free
+func hostPortInUse(listenAddress string, port int32, protocol v1alpha4.PortMappingProtocol) error {
+	address := net.JoinHostPort(listenAddress, strconv.Itoa(int(port)))
+	switch protocol {
+	case v1alpha4.PortMappingProtocolUDP:
+		conn, err := net.ListenPacket("udp", address)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	case v1alpha4.PortMappingProtocolSCTP:
+		return nil
+	default:
+		listener, err := net.Listen("tcp", address)
+		if err != nil {
+			return err
+		}
+		return listener.Close()
+	}
+}
+
+// apiServerListenAddress is the host address the API server port is published on, with KIND's defaults
+// ref: https://github.com/kubernetes-sigs/kind/blob/v0.11.1/pkg/apis/config/v1alpha4/default.go#L44
+func apiServerListenAddress(config *v1alpha4.Cluster) string {
+	switch {
+	case config.Networking.APIServerAddress != "":
+		return config.Networking.APIServerAddress
+	case config.Networking.IPFamily == v1alpha4.IPv6Family:
+		return "::1"
+	}
+	return "127.0.0.1"
+}
+
+// hostPortKey identifies a host port published by a node container
+func hostPortKey(listenAddress string, port int32, protocol v1alpha4.PortMappingProtocol) string {
+	if listenAddress == "" {
+		// KIND's default listen address of extra port mappings
+		listenAddress = "0.0.0.0"
+	}
+	if protocol == "" {
+		protocol = v1alpha4.PortMappingProtocolTCP
+	}
+	return fmt.Sprintf("%s/%s", net.JoinHostPort(listenAddress, strconv.Itoa(int(port))), protocol)
+}
+
+// checkHostPorts makes sure the fixed host ports of the new config are free, instead of the container runtime
+// failing halfway through creating the nodes. The ports of the old config are published by the cluster itself.
+// The ports are only bound to check them when the container runtime is local, so they are published here.
+func checkHostPorts(olds, news *v1alpha4.Cluster, local bool) []*rpc.CheckFailure {
+	published := map[string]bool{}
+	if olds.Networking.APIServerPort > 0 {
+		published[hostPortKey(apiServerListenAddress(olds), olds.Networking.APIServerPort, "")] = true
+	}
+	for _, node := range olds.Nodes {
+		for _, mapping := range node.ExtraPortMappings {
+			published[hostPortKey(mapping.ListenAddress, mapping.HostPort, mapping.Protocol)] = true
+		}
+	}
+
+	var failures []*rpc.CheckFailure
+	requested := map[string]string{}
+	check := func(property, listenAddress string, port int32, protocol v1alpha4.PortMappingProtocol) {
+		if port <= 0 {
+			// the container runtime picks a free port
+			return
+		}
+		key := hostPortKey(listenAddress, port, protocol)
+		if other, ok := requested[key]; ok {
+			failures = append(failures, &rpc.CheckFailure{
+				Property: property,
+				Reason:   fmt.Sprintf("host port %s is also requested by %s", key, other),
+			})
+			return
+		}
+		requested[key] = property
+		// remote container runtimes publish the ports on another machine
+		if published[key] || !local {
+			return
+		}
+		if listenAddress == "" {
+			listenAddress = "0.0.0.0"
+		}
+		if err := hostPortInUse(listenAddress, port, protocol); err != nil {
+			failures = append(failures, &rpc.CheckFailure{
+				Property: property,
+				Reason:   fmt.Sprintf("host port %s is not available: %v", key, err),
+			})
+		}
+	}
+
+	check("networking.apiServerPort", apiServerListenAddress(news), news.Networking.APIServerPort, "")
+	for i, node := range news.Nodes {
+		for j, mapping := range node.ExtraPortMappings {
+			check(fmt.Sprintf("nodes[%d].extraPortMappings[%d].hostPort", i, j), mapping.ListenAddress, mapping.HostPort, mapping.Protocol)
+		}
+	}
+	return failures
+}
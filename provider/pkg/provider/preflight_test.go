@@ -0,0 +1,50 @@
+package provider
+
+import (
+	"net"
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/apis/config/v1alpha4"
+)
+
+func TestCheckHostPorts(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	busy := int32(listener.Addr().(*net.TCPAddr).Port)
+
+	news := &v1alpha4.Cluster{
+		Networking: v1alpha4.Networking{APIServerPort: busy},
+		Nodes: []v1alpha4.Node{
+			{Role: v1alpha4.ControlPlaneRole, ExtraPortMappings: []v1alpha4.PortMapping{
+				{ContainerPort: 80, HostPort: 0},
+				{ContainerPort: 443, HostPort: busy, ListenAddress: "127.0.0.1"},
+			}},
+			{Role: v1alpha4.WorkerRole, ExtraPortMappings: []v1alpha4.PortMapping{
+				{ContainerPort: 443, HostPort: busy, ListenAddress: "127.0.0.1", Protocol: v1alpha4.PortMappingProtocolUDP},
+			}},
+		},
+	}
+	expected := []string{"networking.apiServerPort", "nodes[0].extraPortMappings[1].hostPort"}
+	failures := checkHostPorts(&v1alpha4.Cluster{}, news, true)
+	if len(failures) != len(expected) {
+		t.Fatalf("expected %d failures, got %v", len(expected), failures)
+	}
+	for i, failure := range failures {
+		if failure.Property != expected[i] {
+			t.Errorf("expected failure on %s, got %s", expected[i], failure.Property)
+		}
+	}
+
+	// the ports of the existing cluster are published by its own nodes
+	if failures = checkHostPorts(news, news, true); len(failures) != 1 {
+		t.Errorf("expected only the port requested twice to fail, got %v", failures)
+	}
+
+	// the ports of a remote container runtime can't be checked here
+	if failures = checkHostPorts(&v1alpha4.Cluster{}, news, false); len(failures) != 1 {
+		t.Errorf("expected only the port requested twice to fail, got %v", failures)
+	}
+}
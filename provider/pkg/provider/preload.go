@@ -0,0 +1,75 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	rpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+	"sigs.k8s.io/kind/pkg/cluster"
+)
+
+// preloadImage returns the image or image archive to preload, entries that are existing files are archives
+func preloadImage(clusterName, entry string) *loadedImage {
+	if info, err := os.Stat(entry); err == nil && !info.IsDir() {
+		return &loadedImage{ClusterName: clusterName, Archive: entry}
+	}
+	return &loadedImage{ClusterName: clusterName, Image: entry}
+}
+
+// preloadImages imports the preload images into every node of the cluster that doesn't have them yet
+func (k *kindProvider) preloadImages(provider *cluster.Provider, clusterName string, options *clusterOptions) error {
+	if len(options.PreloadImages) == 0 {
+		return nil
+	}
+	targets, err := selectNodes(provider, clusterName, nil)
+	if err != nil {
+		return err
+	}
+	for _, entry := range options.PreloadImages {
+		image := preloadImage(clusterName, entry)
+		if image.Digest, err = k.imageDigest(image); err != nil {
+			return errors.Wrapf(err, "failed to preload %s", entry)
+		}
+		if err = k.loadImage(image, targets); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkPreloadImages makes sure the known preload images exist locally
+func (k *kindProvider) checkPreloadImages(news resource.PropertyMap) []*rpc.CheckFailure {
+	entries := news["preloadImages"]
+	if !entries.IsArray() || k.unknownConfig {
+		return nil
+	}
+	var failures []*rpc.CheckFailure
+	for i, entry := range entries.ArrayValue() {
+		if !entry.IsString() {
+			continue
+		}
+		if _, err := k.imageDigest(preloadImage("", entry.StringValue())); err != nil {
+			failures = append(failures, &rpc.CheckFailure{
+				Property: fmt.Sprintf("preloadImages[%d]", i),
+				Reason:   fmt.Sprintf("%s is neither a local image nor an image archive: %v", entry.StringValue(), err),
+			})
+		}
+	}
+	return failures
+}
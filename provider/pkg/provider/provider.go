@@ -20,15 +20,17 @@ import (
 	"fmt"
 	"os"
 	"reflect"
-	"strconv"
 	"time"
 
+	"github.com/frezbo/pulumi-provider-kind/provider/v3/pkg/container"
 	"github.com/frezbo/pulumi-provider-kind/provider/v3/pkg/logging"
 	"github.com/frezbo/pulumi-provider-kind/provider/v3/pkg/metadata"
 	"github.com/pkg/errors"
 	"github.com/pulumi/pulumi/pkg/v3/resource/provider"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
 	rpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
 
 	pbempty "github.com/golang/protobuf/ptypes/empty"
@@ -62,6 +64,10 @@ type kindProvider struct {
 	name     string
 	version  string
 	opts     kindCreateOpts
+	// autonaming controls the names generated for clusters without an explicit name
+	autonaming metadata.AutonameOptions
+	// unknownConfig is set when the provider configuration contains values not yet known during preview
+	unknownConfig bool
 }
 
 // Partial create options from https://pkg.go.dev/sigs.k8s.io/kind@v0.11.1/pkg/cluster
@@ -74,16 +80,18 @@ type kindCreateOpts struct {
 	KubeconfigFile       string
 	StopBeforeSettingK8s bool
 	Provider             string
+	Offline              bool
 }
 
 func makeKindProvider(host *provider.HostClient, name, version string) (rpc.ResourceProviderServer, error) {
 	// Return the new provider
 	return &kindProvider{
-		host:     host,
-		canceler: makeCancellationContext(),
-		name:     name,
-		version:  version,
-		opts:     kindCreateOpts{},
+		host:       host,
+		canceler:   makeCancellationContext(),
+		name:       name,
+		version:    version,
+		opts:       kindCreateOpts{},
+		autonaming: metadata.DefaultAutonameOptions(),
 	}, nil
 }
 
@@ -100,48 +108,25 @@ func (k *kindProvider) CheckConfig(ctx context.Context, req *rpc.CheckRequest) (
 	pulumilog.V(9).Infof("%s executing", label)
 
 	news, err := plugin.UnmarshalProperties(req.GetNews(), plugin.MarshalOptions{
-		Label:          fmt.Sprintf("%s.news", label),
-		RejectUnknowns: true,
-		SkipNulls:      true,
+		Label:        fmt.Sprintf("%s.news", label),
+		KeepUnknowns: true,
+		KeepSecrets:  true,
+		SkipNulls:    true,
 	})
 	if err != nil {
 		return nil, errors.Wrapf(err, "CheckConfig failed because of malformed resource inputs")
 	}
-	truthyValue := func(argName resource.PropertyKey, props resource.PropertyMap) bool {
-		if arg := news[argName]; arg.HasValue() {
-			switch {
-			case arg.IsString() && len(arg.StringValue()) > 0:
-				return true
-			default:
-				return false
-			}
-		}
-		return false
-	}
 
-	var failures []*rpc.CheckFailure
+	decoder := newConfigDecoder(news)
+	opts := decoder.decodeKindCreateOpts()
+	decoder.decodeAutonameOptions()
+	failures := decoder.failures
 
-	configFileValueSet := truthyValue("configFile", news)
-	if configFileValueSet {
-		configFilePath := news["configFile"].StringValue()
-		if _, err := os.Stat(configFilePath); err == os.ErrNotExist {
+	if opts.ConfigFile != "" {
+		if _, err := os.Stat(opts.ConfigFile); os.IsNotExist(err) {
 			failures = append(failures, &rpc.CheckFailure{
 				Property: "configFile",
-				Reason:   fmt.Sprintf("KIND config file does not exist at path: %s", configFilePath),
-			})
-		}
-	}
-
-	providerValueSet := truthyValue("provider", news)
-
-	if providerValueSet {
-		switch news["provider"].StringValue() {
-		case kindDockerProvider:
-		case kindPodmanProvider:
-		default:
-			failures = append(failures, &rpc.CheckFailure{
-				Property: "provider",
-				Reason:   "Valid provider values are docker/podman",
+				Reason:   fmt.Sprintf("KIND config file does not exist at path: %s", opts.ConfigFile),
 			})
 		}
 	}
@@ -173,13 +158,27 @@ func (k *kindProvider) DiffConfig(ctx context.Context, req *rpc.DiffRequest) (*r
 		return nil, errors.Wrapf(err, "DiffConfig failed because of malformed resource inputs")
 	}
 
-	// any provider changes creates a new kind cluster
+	// any provider changes creates a new kind cluster, except for the settings
+	// that don't apply to existing clusters
 	if d := olds.Diff(news); d != nil {
+		var diffs []string
+		for _, key := range d.Keys() {
+			if !d.Changed(key) {
+				continue
+			}
+			if !inPlaceConfigKeys[string(key)] {
+				return &rpc.DiffResponse{
+					Changes: rpc.DiffResponse_DIFF_SOME,
+
+					Replaces:            []string{""},
+					DeleteBeforeReplace: true,
+				}, nil
+			}
+			diffs = append(diffs, string(key))
+		}
 		return &rpc.DiffResponse{
 			Changes: rpc.DiffResponse_DIFF_SOME,
-
-			Replaces:            []string{""},
-			DeleteBeforeReplace: true,
+			Diffs:   diffs,
 		}, nil
 	}
 
@@ -190,41 +189,33 @@ func (k *kindProvider) DiffConfig(ctx context.Context, req *rpc.DiffRequest) (*r
 
 // Configure configures the resource provider with "globals" that control its behavior.
 func (k *kindProvider) Configure(_ context.Context, req *rpc.ConfigureRequest) (*rpc.ConfigureResponse, error) {
-	const trueStr = "true"
-	vars := req.GetVariables()
-
-	// set some defaults so that the KIND clusters can be created with any option
-	if configFile, exists := vars["kind:config:configFile"]; exists {
-		k.opts.ConfigFile = configFile
-	}
-	if kubeconfig, exists := vars["kind:config:kubeconfigFile"]; exists {
-		k.opts.KubeconfigFile = kubeconfig
-	}
-	if nodeImage, exists := vars["kind:config:nodeImage"]; exists {
-		k.opts.NodeImage = nodeImage
-	}
-	if provider, exists := vars["kind:config:provider"]; exists {
-		k.opts.Provider = provider
-	} else {
-		k.opts.Provider = kindDefaultProvider
-	}
-	if retain, exists := vars["kind:config:retainNodesOnFailure"]; exists {
-		k.opts.RetainNodesOnFailure = retain == trueStr
-	} else {
-		k.opts.RetainNodesOnFailure = false
+	props, err := plugin.UnmarshalProperties(req.GetArgs(), plugin.MarshalOptions{
+		Label:        fmt.Sprintf("%s.Configure.args", k.name),
+		KeepUnknowns: true,
+		KeepSecrets:  true,
+		SkipNulls:    true,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "Configure failed because of malformed provider configuration")
 	}
-	if stopBeforeSettingK8s, exists := vars["kind:config:stopBeforeSettingK8s"]; exists {
-		k.opts.StopBeforeSettingK8s = stopBeforeSettingK8s == trueStr
-	} else {
-		k.opts.StopBeforeSettingK8s = false
+	// older engines only send the flattened string variables
+	if req.GetArgs() == nil {
+		props = configVariablesToPropertyMap(req.GetVariables())
 	}
-	if waitForNodeReady, exists := vars["kind:config:waitForNodeReady"]; exists {
-		waitDuration, _ := strconv.Atoi(waitForNodeReady)
-		k.opts.WaitForNodeReady = time.Duration(waitDuration) * time.Second
-	} else {
-		k.opts.WaitForNodeReady = 0 * time.Second
+
+	decoder := newConfigDecoder(props)
+	opts := decoder.decodeKindCreateOpts()
+	autonaming := decoder.decodeAutonameOptions()
+	if err := decoder.err(); err != nil {
+		return nil, err
 	}
 
+	k.opts = opts
+	k.autonaming = autonaming
+	// unknown config values can only happen during preview, operations that
+	// depend on the actual configuration are skipped until it's known
+	k.unknownConfig = decoder.unknown
+
 	return &rpc.ConfigureResponse{
 		SupportsPreview: true,
 	}, nil
@@ -233,6 +224,10 @@ func (k *kindProvider) Configure(_ context.Context, req *rpc.ConfigureRequest) (
 // Invoke dynamically executes a built-in function in the provider.
 func (k *kindProvider) Invoke(_ context.Context, req *rpc.InvokeRequest) (*rpc.InvokeResponse, error) {
 	tok := req.GetTok()
+	switch tokens.ModuleMember(tok) {
+	case getNodeImageToken:
+		return k.getNodeImage(req)
+	}
 	return nil, fmt.Errorf("unknown Invoke token '%s'", tok)
 }
 
@@ -251,6 +246,14 @@ func (k *kindProvider) StreamInvoke(req *rpc.InvokeRequest, server rpc.ResourceP
 // the provider inputs are using for detecting and rendering diffs.
 func (k *kindProvider) Check(ctx context.Context, req *rpc.CheckRequest) (*rpc.CheckResponse, error) {
 	urn := resource.URN(req.GetUrn())
+	switch urn.Type() {
+	case loadedImageType:
+		return k.checkLoadedImage(req)
+	case localRegistryType:
+		return k.checkLocalRegistry(req)
+	case clusterPeeringType:
+		return k.checkClusterPeering(req)
+	}
 	label := fmt.Sprintf("%s.DiffConfig(%s)", k.name, urn)
 	pulumilog.V(9).Infof("%s executing", label)
 
@@ -301,7 +304,59 @@ func (k *kindProvider) Check(ctx context.Context, req *rpc.CheckRequest) (*rpc.C
 		metadata.AdoptOldAutonameIfUnnamed(newInputs, oldInputs)
 
 	} else {
-		metadata.AssignNameIfAutonamable(newInputs, news, urn.Name())
+		metadata.AssignNameIfAutonamable(newInputs, news, urn, k.autonaming)
+	}
+
+	var failures []*rpc.CheckFailure
+
+	// names set by the user are passed as is to kind, so let's make sure
+	// they are valid before kind fails halfway through creating the cluster
+	if name, ok := newInputs["name"].(string); ok && name != "" {
+		if err := metadata.ValidateClusterName(name); err != nil {
+			failures = append(failures, &rpc.CheckFailure{
+				Property: "name",
+				Reason:   err.Error(),
+			})
+		}
+		if warning := metadata.ClusterNameLengthWarning(name); warning != "" && k.host != nil {
+			_ = k.host.Log(ctx, diag.Warning, urn, warning)
+		}
+	}
+
+	failures = append(failures, resolveKubernetesVersions(newInputs)...)
+	k.warnUnpinnedImages(ctx, urn, newInputs)
+	failures = append(failures, k.checkFeatureGates(ctx, urn, news, newInputs)...)
+
+	// only new or changed KIND configs can need images that are not there yet
+	if !news.ContainsUnknowns() {
+		newConfig, err := propMapToKindClusterConfig(newInputs)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to convert new inputs to kind config")
+		}
+		if oldInputs.Name == "" || !reflect.DeepEqual(oldInputs, newConfig) {
+			failures = append(failures, k.checkOfflineImages(newConfig)...)
+		}
+		// an adopted cluster already publishes the ports of its config
+		if adopt, _ := newInputs["adoptExisting"].(bool); !adopt {
+			failures = append(failures, checkHostPorts(oldInputs, newConfig, container.NewRuntime(k.opts.Provider).Local())...)
+		}
+	}
+
+	failures = append(failures, checkKubeconfigExport(news)...)
+	failures = append(failures, checkClusterState(news)...)
+	failures = append(failures, checkUpgradeStrategy(news)...)
+	failures = append(failures, k.checkPreloadImages(news)...)
+	failures = append(failures, checkRegistryMirrors(news)...)
+	failures = append(failures, checkExtraCACertificates(news)...)
+	failures = append(failures, checkProxy(news)...)
+	failures = append(failures, checkAPIServer(news)...)
+	failures = append(failures, checkNodeOptions(news)...)
+	failures = append(failures, checkHostAliases(news)...)
+	failures = append(failures, checkNetwork(news)...)
+
+	// only new clusters can collide with clusters created outside of this stack
+	if oldInputs.Name == "" && !k.unknownConfig {
+		failures = append(failures, k.checkNameCollision(urn, news, newInputs)...)
 	}
 
 	checkedInputs := resource.NewPropertyMapFromMap(newInputs)
@@ -315,12 +370,20 @@ func (k *kindProvider) Check(ctx context.Context, req *rpc.CheckRequest) (*rpc.C
 		return nil, err
 	}
 
-	return &rpc.CheckResponse{Inputs: autonamedInputs, Failures: nil}, nil
+	return &rpc.CheckResponse{Inputs: autonamedInputs, Failures: failures}, nil
 }
 
 // Diff checks what impacts a hypothetical update will have on the resource's properties.
 func (k *kindProvider) Diff(ctx context.Context, req *rpc.DiffRequest) (*rpc.DiffResponse, error) {
 	urn := resource.URN(req.GetUrn())
+	switch urn.Type() {
+	case loadedImageType:
+		return k.diffLoadedImage(req)
+	case localRegistryType:
+		return k.diffLocalRegistry(req)
+	case clusterPeeringType:
+		return k.diffClusterPeering(req)
+	}
 	label := fmt.Sprintf("%s.Diff(%s)", k.name, urn)
 	pulumilog.V(9).Infof("%s executing", label)
 
@@ -352,20 +415,62 @@ func (k *kindProvider) Diff(ctx context.Context, req *rpc.DiffRequest) (*rpc.Dif
 		return nil, errors.Wrapf(err, "failed to convert new inputs to kind config")
 	}
 
-	if !reflect.DeepEqual(oldInputs, newInputs) {
+	oldOptions, err := propMapToClusterOptions(olds.Mappable())
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to convert old inputs to cluster options")
+	}
+	newOptions, err := propMapToClusterOptions(news.Mappable())
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to convert new inputs to cluster options")
+	}
+
+	// workers can be added and removed in place and node images upgraded in place when opted in,
+	// any other change to the KIND config or to the options applied when creating the nodes recreates the cluster
+	configChanged := !reflect.DeepEqual(oldInputs, newInputs)
+	inPlace := canScaleWorkers(oldInputs, newInputs) ||
+		(newOptions.UpgradeStrategy == upgradeStrategyInPlace && k.canUpgradeInPlace(oldInputs, newInputs))
+	replaces := replacingOptions(oldOptions, newOptions)
+	// clusters created before the directory of the certificates was always mounted can't get any in place
+	if len(oldOptions.ExtraCACertificates) == 0 && len(newOptions.ExtraCACertificates) > 0 &&
+		!k.caCertificatesMounted(k.newClusterProvider(urn), oldInputs.Name) {
+		replaces = append(replaces, "extraCACertificates")
+	}
+	if (configChanged && !inPlace) || len(replaces) > 0 {
+		if len(replaces) == 0 {
+			replaces = []string{""}
+		}
 		return &rpc.DiffResponse{
 			Changes:             rpc.DiffResponse_DIFF_SOME,
-			Replaces:            []string{""},
+			Replaces:            replaces,
 			DeleteBeforeReplace: true,
 		}, nil
 	}
 
+	// the state output is the live state, which is running unless stopped on purpose
+	oldOptions.State = oldOptions.clusterState()
+	newOptions.State = newOptions.clusterState()
+
+	// the options handled by the provider can be updated in place
+	if configChanged || !reflect.DeepEqual(oldOptions, newOptions) {
+		return &rpc.DiffResponse{
+			Changes: rpc.DiffResponse_DIFF_SOME,
+		}, nil
+	}
+
 	return &rpc.DiffResponse{}, nil
 }
 
 // Create allocates a new instance of the provided resource and returns its unique ID afterwards.
 func (k *kindProvider) Create(ctx context.Context, req *rpc.CreateRequest) (*rpc.CreateResponse, error) {
 	urn := resource.URN(req.GetUrn())
+	switch urn.Type() {
+	case loadedImageType:
+		return k.createLoadedImage(req)
+	case localRegistryType:
+		return k.createLocalRegistry(req)
+	case clusterPeeringType:
+		return k.createClusterPeering(req)
+	}
 	label := fmt.Sprintf("%s.Create(%s)", k.name, urn)
 	pulumilog.V(9).Infof("%s executing", label)
 
@@ -378,21 +483,18 @@ func (k *kindProvider) Create(ctx context.Context, req *rpc.CreateRequest) (*rpc
 		return nil, err
 	}
 
-	var kindProviderOption cluster.ProviderOption
-
-	switch k.opts.Provider {
-	case kindDockerProvider:
-		kindProviderOption = cluster.ProviderWithDocker()
-	case kindPodmanProvider:
-		kindProviderOption = cluster.ProviderWithPodman()
-	}
-
 	newInputsMap := newInputs.Mappable()
 
 	if req.GetPreview() {
 
 		newInputsMap["kubeconfig"] = resource.Computed{}
 		newInputsMap["name"] = resource.Computed{}
+		for _, output := range liveOutputs {
+			newInputsMap[output] = resource.Computed{}
+		}
+		if _, ok := newInputsMap["state"]; !ok {
+			newInputsMap["state"] = clusterStateRunning
+		}
 
 		outputProperties, err := plugin.MarshalProperties(
 			resource.NewPropertyMapFromMap(newInputsMap),
@@ -404,61 +506,117 @@ func (k *kindProvider) Create(ctx context.Context, req *rpc.CreateRequest) (*rpc
 		return &rpc.CreateResponse{Properties: outputProperties}, nil
 	}
 
-	logger := logging.NewLogger(k.canceler.context, k.host, urn)
-
-	kindProviderConfig := cluster.NewProvider(kindProviderOption, cluster.ProviderWithLogger(logger))
-
-	var kindClusterCreateOptions []cluster.CreateOption
-
-	if k.opts.ConfigFile != "" {
-		kindClusterCreateOptions = append(kindClusterCreateOptions, cluster.CreateWithConfigFile(k.opts.ConfigFile))
-	}
-	if k.opts.KubeconfigFile != "" {
-		kindClusterCreateOptions = append(kindClusterCreateOptions, cluster.CreateWithKubeconfigPath(k.opts.KubeconfigFile))
-	}
-	if k.opts.NodeImage != "" {
-		kindClusterCreateOptions = append(kindClusterCreateOptions, cluster.CreateWithNodeImage(k.opts.NodeImage))
-	}
-	if k.opts.RetainNodesOnFailure {
-		kindClusterCreateOptions = append(kindClusterCreateOptions, cluster.CreateWithRetain(k.opts.RetainNodesOnFailure))
-	}
-	if k.opts.StopBeforeSettingK8s {
-		kindClusterCreateOptions = append(kindClusterCreateOptions, cluster.CreateWithStopBeforeSettingUpKubernetes(k.opts.StopBeforeSettingK8s))
-	}
+	kindProviderConfig := k.newClusterProvider(urn)
 
 	clusterConfig, err := propMapToKindClusterConfig(newInputsMap)
 	if err != nil {
 		return nil, errors.Wrapf(err, "Create():")
 	}
+	options, err := propMapToClusterOptions(newInputsMap)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Create():")
+	}
 
 	clusterName := clusterConfig.Name
 
-	kindClusterCreateOptions = append(kindClusterCreateOptions, cluster.CreateWithV1Alpha4Config(clusterConfig))
-	kindClusterCreateOptions = append(kindClusterCreateOptions, cluster.CreateWithWaitForReady(k.opts.WaitForNodeReady))
+	// Check already looked for a cluster with the same name, but it might have been created since
+	// and kind's create would fail late and then we would delete a cluster we don't own
+	exists, err := clusterExists(kindProviderConfig, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case exists && !options.AdoptExisting:
+		return nil, errors.Errorf("a KIND cluster named %s already exists, set adoptExisting to take it over", clusterName)
+	case exists:
+		if err = k.verifyAdoptable(kindProviderConfig, clusterConfig); err != nil {
+			return nil, errors.Wrapf(err, "cannot adopt existing KIND cluster")
+		}
+		// the adopted cluster might be stopped, but the kubeconfig can only be read from running nodes
+		if err = k.setClusterState(kindProviderConfig, clusterName, clusterStateRunning); err != nil {
+			return nil, err
+		}
+	default:
+		kindKubeconfigPath, cleanup, err := k.kindKubeconfigPath(options)
+		if err != nil {
+			return nil, err
+		}
+		defer cleanup()
+		// KIND pulls missing images without asking
+		if err = k.verifyOfflineImages(clusterConfig); err != nil {
+			return nil, err
+		}
+		// the certificates are mounted into the nodes, so they are trusted before kubeadm runs
+		dataDir := defaultClusterDataDir(clusterName)
+		if err = writeCACertificates(dataDir, options.ExtraCACertificates); err != nil {
+			return nil, err
+		}
+		if err = writeAPIServerFiles(dataDir, options.APIServer); err != nil {
+			return nil, err
+		}
+		applyClusterDataMounts(clusterConfig, options, dataDir)
+		if err = k.ensureNetwork(options.Network, clusterConfig); err != nil {
+			return nil, err
+		}
+		// KIND pulls missing images while the environment lock is held, which would hold up parallel creates
+		if err = k.pullMissingImages(clusterConfig); err != nil {
+			return nil, err
+		}
+		if err = withEnv(clusterEnv(options), nodesCreated(kindProviderConfig, clusterName), func() error {
+			return k.createCluster(kindProviderConfig, clusterConfig, kindKubeconfigPath)
+		}); err != nil {
+			return nil, err
+		}
+		if options.Proxy != nil {
+			if err = k.applyProxy(kindProviderConfig, clusterName, clusterConfig, k.clusterNetworkName(options.Network), options.Proxy); err != nil {
+				return nil, err
+			}
+		}
+		if len(options.ExtraCACertificates) > 0 {
+			if err = installCACertificates(kindProviderConfig, clusterName, false); err != nil {
+				return nil, err
+			}
+		}
+	}
 
-	if err = kindProviderConfig.Create(clusterName, kindClusterCreateOptions...); err != nil {
-		// delete any kind cluster that failed to create like the kind cli, unless explicitly set not to
-		if !k.opts.RetainNodesOnFailure {
-			// a best effort to delete the cluster that failed to create
-			// without checking for errors. It's up to the user to cleanup
-			// kind clusters that may have been orphaned due to some serious
-			// config issues/weird edge cases
-			// nolint:errcheck
-			kindProviderConfig.Delete(clusterName, k.opts.KubeconfigFile)
+	if len(options.HostAliases) > 0 {
+		if err = applyHostAliases(kindProviderConfig, clusterName, options.HostAliases); err != nil {
+			return nil, err
 		}
+	}
+
+	// the images are there before anything depending on the cluster gets to run workloads on it
+	if err = k.preloadImages(kindProviderConfig, clusterName, options); err != nil {
 		return nil, err
 	}
 
 	kubeconfig := ""
 	if !k.opts.StopBeforeSettingK8s {
-		kubeconfig, err = kindProviderConfig.KubeConfig(clusterName, false)
+		kubeconfig, err = k.clusterKubeconfig(kindProviderConfig, clusterName, options)
 		if err != nil {
 			return nil, err
 		}
+		// KIND already exported the kubeconfig of the clusters it created when using its own policy
+		if exists || options.kubeconfigExport() != "" {
+			if err = k.exportKubeconfig(kindProviderConfig, clusterName, options, kubeconfig); err != nil {
+				return nil, errors.Wrapf(err, "failed to export kubeconfig")
+			}
+		}
 	}
 	newInputsMap["kubeconfig"] = kubeconfig
 	newInputsMap["name"] = clusterName
 
+	if err = k.addLiveOutputs(newInputsMap, kindProviderConfig, clusterName, k.clusterNetworkName(options.Network)); err != nil {
+		return nil, err
+	}
+
+	if options.clusterState() == clusterStateStopped {
+		if err = k.setClusterState(kindProviderConfig, clusterName, clusterStateStopped); err != nil {
+			return nil, err
+		}
+		newInputsMap["state"] = clusterStateStopped
+	}
+
 	outputProperties, err := plugin.MarshalProperties(
 		resource.NewPropertyMapFromMap(newInputsMap),
 		plugin.MarshalOptions{
@@ -479,19 +637,18 @@ func (k *kindProvider) Create(ctx context.Context, req *rpc.CreateRequest) (*rpc
 // Read the current live state associated with a resource.
 func (k *kindProvider) Read(ctx context.Context, req *rpc.ReadRequest) (*rpc.ReadResponse, error) {
 	urn := resource.URN(req.GetUrn())
+	switch urn.Type() {
+	case loadedImageType:
+		return k.readLoadedImage(req)
+	case localRegistryType:
+		return k.readLocalRegistry(req)
+	case clusterPeeringType:
+		return k.readClusterPeering(req)
+	}
 	label := fmt.Sprintf("%s.Read(%s)", k.name, urn)
 	pulumilog.V(9).Infof("%s executing", label)
 
-	var kindProviderOption cluster.ProviderOption
-
-	switch k.opts.Provider {
-	case kindDockerProvider:
-		kindProviderOption = cluster.ProviderWithDocker()
-	case kindPodmanProvider:
-		kindProviderOption = cluster.ProviderWithPodman()
-	}
-
-	kindProviderConfig := cluster.NewProvider(kindProviderOption)
+	kindProviderConfig := k.newClusterProvider(urn)
 
 	clusters, err := kindProviderConfig.List()
 	if err != nil {
@@ -504,9 +661,39 @@ func (k *kindProvider) Read(ctx context.Context, req *rpc.ReadRequest) (*rpc.Rea
 	// modified by hand, else we delete the cluster
 	for _, clusterName := range clusters {
 		if clusterName == req.Id {
+			properties, err := plugin.UnmarshalProperties(req.GetProperties(), plugin.MarshalOptions{
+				Label:        fmt.Sprintf("%s.properties", label),
+				KeepUnknowns: true,
+				SkipNulls:    true,
+			})
+			if err != nil {
+				return nil, err
+			}
+			outputs := properties.Mappable()
+			options, err := propMapToClusterOptions(outputs)
+			if err != nil {
+				return nil, errors.Wrapf(err, "Read():")
+			}
+
+			// the node containers might have been restarted with new addresses or host ports
+			if err = k.addLiveOutputs(outputs, kindProviderConfig, clusterName, k.clusterNetworkName(options.Network)); err != nil {
+				return nil, err
+			}
+
+			outputProperties, err := plugin.MarshalProperties(
+				resource.NewPropertyMapFromMap(outputs),
+				plugin.MarshalOptions{
+					Label:        fmt.Sprintf("%s.outputs", label),
+					KeepUnknowns: true,
+					SkipNulls:    true,
+				},
+			)
+			if err != nil {
+				return nil, err
+			}
 			return &rpc.ReadResponse{
 				Id:         clusterName,
-				Properties: req.Properties,
+				Properties: outputProperties,
 			}, nil
 		}
 	}
@@ -516,34 +703,249 @@ func (k *kindProvider) Read(ctx context.Context, req *rpc.ReadRequest) (*rpc.Rea
 // Update updates an existing resource with new values.
 func (k *kindProvider) Update(ctx context.Context, req *rpc.UpdateRequest) (*rpc.UpdateResponse, error) {
 	urn := resource.URN(req.GetUrn())
+	switch urn.Type() {
+	case loadedImageType:
+		return k.updateLoadedImage(req)
+	case localRegistryType:
+		return k.updateLocalRegistry(req)
+	case clusterPeeringType:
+		return k.updateClusterPeering(req)
+	}
 	label := fmt.Sprintf("%s.Update(%s)", k.name, urn)
 	pulumilog.V(9).Infof("%s executing", label)
 
-	return &rpc.UpdateResponse{}, nil
+	olds, err := plugin.UnmarshalProperties(req.GetOlds(), plugin.MarshalOptions{
+		Label:        fmt.Sprintf("%s.olds", label),
+		KeepUnknowns: true,
+		SkipNulls:    true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	news, err := plugin.UnmarshalProperties(req.GetNews(), plugin.MarshalOptions{
+		Label:        fmt.Sprintf("%s.news", label),
+		KeepUnknowns: true,
+		SkipNulls:    true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	oldOptions, err := propMapToClusterOptions(olds.Mappable())
+	if err != nil {
+		return nil, errors.Wrapf(err, "Update():")
+	}
+	newOptions, err := propMapToClusterOptions(news.Mappable())
+	if err != nil {
+		return nil, errors.Wrapf(err, "Update():")
+	}
+
+	clusterName := req.GetId()
+	newInputsMap := news.Mappable()
+	newInputsMap["kubeconfig"] = olds["kubeconfig"].Mappable()
+	newInputsMap["name"] = clusterName
+	for _, output := range liveOutputs {
+		if value, ok := olds[resource.PropertyKey(output)]; ok {
+			newInputsMap[output] = value.Mappable()
+		}
+	}
+
+	kindProviderConfig := k.newClusterProvider(urn)
+
+	// the old state is the live state as of the last refresh
+	stateChanged := oldOptions.clusterState() != newOptions.clusterState()
+	newInputsMap["state"] = newOptions.clusterState()
+
+	// the kubeconfig can only be read from running nodes, so they are started before exporting it
+	if stateChanged && newOptions.clusterState() == clusterStateRunning && !req.GetPreview() {
+		if err = k.setClusterState(kindProviderConfig, clusterName, clusterStateRunning); err != nil {
+			return nil, err
+		}
+	}
+
+	// Diff only allows in place changes of the KIND config that add or remove workers or upgrade the nodes
+	oldConfig, err := propMapToKindClusterConfig(olds.Mappable())
+	if err != nil {
+		return nil, errors.Wrapf(err, "Update():")
+	}
+	newConfig, err := propMapToKindClusterConfig(news.Mappable())
+	if err != nil {
+		return nil, errors.Wrapf(err, "Update():")
+	}
+	configChanged := !reflect.DeepEqual(oldConfig, newConfig)
+	switch {
+	case !configChanged:
+	case req.GetPreview():
+		newInputsMap["nodeDetails"] = resource.Computed{}
+	case oldOptions.clusterState() == clusterStateStopped && newOptions.clusterState() == clusterStateStopped:
+		return nil, errors.Errorf("KIND cluster %s must be running to change its nodes in place", clusterName)
+	case canScaleWorkers(oldConfig, newConfig):
+		// the new workers get the same host directory mounted as the existing nodes
+		workersConfig := newConfig.DeepCopy()
+		mountCACertificates(workersConfig, k.clusterDataDir(kindProviderConfig, clusterName))
+		if err = k.scaleWorkers(kindProviderConfig, clusterName, k.clusterNetworkName(newOptions.Network), oldConfig, workersConfig); err != nil {
+			return nil, err
+		}
+	default:
+		if err = k.upgradeCluster(kindProviderConfig, clusterName, newConfig); err != nil {
+			return nil, err
+		}
+	}
+
+	// the mounted directory of the certificates is shared by all nodes, new workers included
+	caCertificatesChanged := !reflect.DeepEqual(oldOptions.ExtraCACertificates, newOptions.ExtraCACertificates)
+	if caCertificatesChanged && !req.GetPreview() {
+		if newOptions.clusterState() == clusterStateStopped && oldOptions.clusterState() == clusterStateStopped {
+			return nil, errors.Errorf("KIND cluster %s must be running to update its CA certificates", clusterName)
+		}
+		if err = writeCACertificates(k.clusterDataDir(kindProviderConfig, clusterName), newOptions.ExtraCACertificates); err != nil {
+			return nil, err
+		}
+		if err = installCACertificates(kindProviderConfig, clusterName, true); err != nil {
+			return nil, err
+		}
+	}
+
+	// the proxy settings are rewritten to add the names of new workers to NO_PROXY
+	proxyChanged := !reflect.DeepEqual(oldOptions.Proxy, newOptions.Proxy)
+	if (proxyChanged || (newOptions.Proxy != nil && configChanged)) && !req.GetPreview() {
+		if newOptions.clusterState() == clusterStateStopped && oldOptions.clusterState() == clusterStateStopped {
+			return nil, errors.Errorf("KIND cluster %s must be running to update its proxy settings", clusterName)
+		}
+		if err = k.applyProxy(kindProviderConfig, clusterName, newConfig, k.clusterNetworkName(newOptions.Network), newOptions.Proxy); err != nil {
+			return nil, err
+		}
+	}
+
+	// new workers and started nodes get /etc/hosts without the host aliases
+	hostAliasesChanged := !reflect.DeepEqual(oldOptions.HostAliases, newOptions.HostAliases)
+	restarted := stateChanged && newOptions.clusterState() == clusterStateRunning
+	if (hostAliasesChanged || (len(newOptions.HostAliases) > 0 && (configChanged || restarted))) && !req.GetPreview() {
+		if newOptions.clusterState() == clusterStateStopped && oldOptions.clusterState() == clusterStateStopped {
+			return nil, errors.Errorf("KIND cluster %s must be running to update its host aliases", clusterName)
+		}
+		if err = applyHostAliases(kindProviderConfig, clusterName, newOptions.HostAliases); err != nil {
+			return nil, err
+		}
+	}
+
+	// new workers and new preload images both need the images imported
+	preloadChanged := !reflect.DeepEqual(oldOptions.PreloadImages, newOptions.PreloadImages)
+	if (configChanged || preloadChanged) && !req.GetPreview() {
+		if newOptions.clusterState() == clusterStateStopped && oldOptions.clusterState() == clusterStateStopped {
+			return nil, errors.Errorf("KIND cluster %s must be running to preload images", clusterName)
+		}
+		if err = k.preloadImages(kindProviderConfig, clusterName, newOptions); err != nil {
+			return nil, err
+		}
+	}
+
+	switch {
+	case !kubeconfigExportChanged(oldOptions, newOptions) || k.opts.StopBeforeSettingK8s:
+		// nothing to export again
+	case req.GetPreview():
+		if oldOptions.kubeconfigContext(clusterName) != newOptions.kubeconfigContext(clusterName) {
+			newInputsMap["kubeconfig"] = resource.Computed{}
+		}
+	default:
+		// Diff only allows in place updates of the options handled by the provider,
+		// so the kubeconfig is the only thing that needs to be exported again
+		if err = k.removeKubeconfig(clusterName, oldOptions); err != nil {
+			return nil, errors.Wrapf(err, "failed to remove cluster from kubeconfig")
+		}
+		kubeconfig, err := k.clusterKubeconfig(kindProviderConfig, clusterName, newOptions)
+		if err != nil {
+			return nil, err
+		}
+		if err = k.exportKubeconfig(kindProviderConfig, clusterName, newOptions, kubeconfig); err != nil {
+			return nil, errors.Wrapf(err, "failed to export kubeconfig")
+		}
+		newInputsMap["kubeconfig"] = kubeconfig
+	}
+
+	if (stateChanged || configChanged) && !req.GetPreview() {
+		if stateChanged && newOptions.clusterState() == clusterStateStopped {
+			if err = k.setClusterState(kindProviderConfig, clusterName, clusterStateStopped); err != nil {
+				return nil, err
+			}
+		}
+		// the nodes might get new addresses when started again and the nodes might have changed
+		if err = k.addLiveOutputs(newInputsMap, kindProviderConfig, clusterName, k.clusterNetworkName(newOptions.Network)); err != nil {
+			return nil, err
+		}
+	}
+
+	outputProperties, err := plugin.MarshalProperties(
+		resource.NewPropertyMapFromMap(newInputsMap),
+		plugin.MarshalOptions{
+			Label:        fmt.Sprintf("%s.news", label),
+			KeepUnknowns: true,
+			SkipNulls:    true,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &rpc.UpdateResponse{Properties: outputProperties}, nil
 }
 
 // Delete tears down an existing resource with the given ID.  If it fails, the resource is assumed
 // to still exist.
 func (k *kindProvider) Delete(ctx context.Context, req *rpc.DeleteRequest) (*pbempty.Empty, error) {
 	urn := resource.URN(req.GetUrn())
+	switch urn.Type() {
+	case loadedImageType:
+		return k.deleteLoadedImage(req)
+	case localRegistryType:
+		return k.deleteLocalRegistry(req)
+	case clusterPeeringType:
+		return k.deleteClusterPeering(req)
+	}
 	label := fmt.Sprintf("%s.Delete(%s)", k.name, urn)
 	pulumilog.V(9).Infof("%s executing", label)
 
-	var kindProviderOption cluster.ProviderOption
+	properties, err := plugin.UnmarshalProperties(req.GetProperties(), plugin.MarshalOptions{
+		Label:        fmt.Sprintf("%s.properties", label),
+		KeepUnknowns: true,
+		SkipNulls:    true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	options, err := propMapToClusterOptions(properties.Mappable())
+	if err != nil {
+		return nil, errors.Wrapf(err, "Delete():")
+	}
 
-	switch k.opts.Provider {
-	case kindDockerProvider:
-		kindProviderOption = cluster.ProviderWithDocker()
-	case kindPodmanProvider:
-		kindProviderOption = cluster.ProviderWithPodman()
+	kindKubeconfigPath, cleanup, err := k.kindKubeconfigPath(options)
+	if err != nil {
+		return nil, err
 	}
+	defer cleanup()
 
-	logger := logging.NewLogger(k.canceler.context, k.host, urn)
-	provider := cluster.NewProvider(kindProviderOption, cluster.ProviderWithLogger(logger))
-	if err := provider.Delete(req.Id, k.opts.KubeconfigFile); err != nil {
+	provider := k.newClusterProvider(urn)
+	// the mounts of the nodes are gone along with them
+	dataDir := k.clusterDataDir(provider, req.Id)
+	if err := provider.Delete(req.Id, kindKubeconfigPath); err != nil {
+		return &pbempty.Empty{}, err
+	}
+
+	if err := removeClusterData(dataDir); err != nil {
+		return &pbempty.Empty{}, errors.Wrapf(err, "failed to remove files mounted into the nodes")
+	}
+
+	// other clusters and local registries might still use the network
+	if err := k.removeUnusedNetwork(options.Network); err != nil {
 		return &pbempty.Empty{}, err
 	}
 
+	// KIND already removed the entries it owns from the kubeconfig
+	if options.kubeconfigExport() != "" {
+		if err := k.removeKubeconfig(req.Id, options); err != nil {
+			return &pbempty.Empty{}, errors.Wrapf(err, "failed to remove cluster from kubeconfig")
+		}
+	}
+
 	return &pbempty.Empty{}, nil
 }
 
@@ -574,6 +976,121 @@ func (k *kindProvider) Cancel(context.Context, *pbempty.Empty) (*pbempty.Empty,
 	return &pbempty.Empty{}, nil
 }
 
+// createCluster creates the KIND cluster using the provider configuration
+func (k *kindProvider) createCluster(kindProviderConfig *cluster.Provider, clusterConfig *v1alpha4.Cluster, kubeconfigPath string) error {
+	var kindClusterCreateOptions []cluster.CreateOption
+
+	if k.opts.ConfigFile != "" {
+		kindClusterCreateOptions = append(kindClusterCreateOptions, cluster.CreateWithConfigFile(k.opts.ConfigFile))
+	}
+	if kubeconfigPath != "" {
+		kindClusterCreateOptions = append(kindClusterCreateOptions, cluster.CreateWithKubeconfigPath(kubeconfigPath))
+	}
+	if k.opts.NodeImage != "" {
+		kindClusterCreateOptions = append(kindClusterCreateOptions, cluster.CreateWithNodeImage(k.opts.NodeImage))
+	}
+	if k.opts.RetainNodesOnFailure {
+		kindClusterCreateOptions = append(kindClusterCreateOptions, cluster.CreateWithRetain(k.opts.RetainNodesOnFailure))
+	}
+	if k.opts.StopBeforeSettingK8s {
+		kindClusterCreateOptions = append(kindClusterCreateOptions, cluster.CreateWithStopBeforeSettingUpKubernetes(k.opts.StopBeforeSettingK8s))
+	}
+
+	clusterName := clusterConfig.Name
+
+	kindClusterCreateOptions = append(kindClusterCreateOptions, cluster.CreateWithV1Alpha4Config(clusterConfig))
+	kindClusterCreateOptions = append(kindClusterCreateOptions, cluster.CreateWithWaitForReady(k.opts.WaitForNodeReady))
+
+	if err := kindProviderConfig.Create(clusterName, kindClusterCreateOptions...); err != nil {
+		// delete any kind cluster that failed to create like the kind cli, unless explicitly set not to
+		if !k.opts.RetainNodesOnFailure {
+			// a best effort to delete the cluster that failed to create
+			// without checking for errors. It's up to the user to cleanup
+			// kind clusters that may have been orphaned due to some serious
+			// config issues/weird edge cases
+			// nolint:errcheck
+			kindProviderConfig.Delete(clusterName, kubeconfigPath)
+		}
+		return err
+	}
+	return nil
+}
+
+// newClusterProvider returns the kind cluster provider for the configured container runtime
+// logging to the engine on behalf of the resource
+func (k *kindProvider) newClusterProvider(urn resource.URN) *cluster.Provider {
+	var kindProviderOption cluster.ProviderOption
+
+	switch k.opts.Provider {
+	case kindDockerProvider:
+		kindProviderOption = cluster.ProviderWithDocker()
+	case kindPodmanProvider:
+		kindProviderOption = cluster.ProviderWithPodman()
+	}
+
+	logger := logging.NewLogger(k.canceler.context, k.host, urn)
+	return cluster.NewProvider(kindProviderOption, cluster.ProviderWithLogger(logger))
+}
+
+// clusterOptions are the Cluster resource inputs handled by the provider
+// that are not part of the KIND cluster config
+type clusterOptions struct {
+	// AdoptExisting takes over a pre-existing cluster with the same name instead of failing
+	AdoptExisting bool `json:"adoptExisting,omitempty"`
+	// KubeconfigExport is the policy for exporting the kubeconfig: none, file or merge
+	KubeconfigExport string `json:"kubeconfigExport,omitempty"`
+	// KubeconfigPath is the file the kubeconfig is exported to
+	KubeconfigPath string `json:"kubeconfigPath,omitempty"`
+	// KubeconfigContext is the name of the context, cluster and user entries in the kubeconfig
+	KubeconfigContext string `json:"kubeconfigContext,omitempty"`
+	// State is the desired state of the node containers: running or stopped
+	State string `json:"state,omitempty"`
+	// UpgradeStrategy is how node image changes are rolled out: replace or inPlace
+	UpgradeStrategy string `json:"upgradeStrategy,omitempty"`
+	// PreloadImages are local images or image archives imported into every node
+	PreloadImages []string `json:"preloadImages,omitempty"`
+	// RegistryMirrors are the mirrors of registries rendered into the containerd config of the nodes
+	RegistryMirrors map[string]registryMirror `json:"registryMirrors,omitempty"`
+	// ExtraCACertificates are PEM certificates or paths of PEM files trusted by the nodes
+	ExtraCACertificates []string `json:"extraCACertificates,omitempty"`
+	// Proxy are the proxy settings of the node containers and of the containerd drop-in of the nodes
+	Proxy *proxySettings `json:"proxy,omitempty"`
+	// APIServer is the configuration of the API servers rendered into kubeadm patches
+	APIServer *apiServerConfig `json:"apiServer,omitempty"`
+	// HostAliases are written to /etc/hosts of the nodes and to the CoreDNS config
+	HostAliases []hostAlias `json:"hostAliases,omitempty"`
+	// Network is the container network of the nodes, created when missing
+	Network *networkSettings `json:"network,omitempty"`
+}
+
+func propMapToClusterOptions(inputs map[string]interface{}) (*clusterOptions, error) {
+	options := &clusterOptions{}
+	optionsData, err := json.Marshal(inputs)
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(optionsData, options); err != nil {
+		return nil, err
+	}
+	return options, nil
+}
+
+// replacingOptions returns the options that changed and are only applied when creating the nodes,
+// so they are shown as the reason of the replacement instead of what they are rendered into
+func replacingOptions(olds, news *clusterOptions) []string {
+	var replaces []string
+	if registryMirrorsChanged(olds, news) {
+		replaces = append(replaces, "registryMirrors")
+	}
+	if !reflect.DeepEqual(olds.APIServer, news.APIServer) {
+		replaces = append(replaces, "apiServer")
+	}
+	if !reflect.DeepEqual(olds.Network, news.Network) {
+		replaces = append(replaces, "network")
+	}
+	return replaces
+}
+
 func propMapToKindClusterConfig(inputs map[string]interface{}) (*v1alpha4.Cluster, error) {
 	clusterConfig := &v1alpha4.Cluster{}
 	clusterConfigData, err := json.Marshal(inputs)
@@ -583,5 +1100,29 @@ func propMapToKindClusterConfig(inputs map[string]interface{}) (*v1alpha4.Cluste
 	if err = json.Unmarshal(clusterConfigData, clusterConfig); err != nil {
 		return nil, err
 	}
+
+	// the inputs rendered into the KIND config are part of it for diffing and creating clusters
+	options, err := propMapToClusterOptions(inputs)
+	if err != nil {
+		return nil, err
+	}
+	applyRegistryMirrors(clusterConfig, options.RegistryMirrors)
+	if err = applyAPIServer(clusterConfig, options.APIServer); err != nil {
+		return nil, err
+	}
+	nodes, err := propMapToNodeOptions(inputs)
+	if err != nil {
+		return nil, err
+	}
+	if err = applyNodeOptions(clusterConfig, nodes); err != nil {
+		return nil, err
+	}
 	return clusterConfig, nil
 }
+
+// applyClusterDataMounts mounts the host directory of files of the cluster into the nodes being created.
+// The host path depends on the user running the provider, so it's left out of the KIND config that is diffed.
+func applyClusterDataMounts(config *v1alpha4.Cluster, options *clusterOptions, dataDir string) {
+	mountCACertificates(config, dataDir)
+	mountAPIServerFiles(config, options.APIServer, dataDir)
+}
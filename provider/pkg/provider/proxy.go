@@ -0,0 +1,218 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/frezbo/pulumi-provider-kind/provider/v3/pkg/container"
+	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	pulumilog "github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+	rpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+	"sigs.k8s.io/kind/pkg/apis/config/v1alpha4"
+	"sigs.k8s.io/kind/pkg/cluster"
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
+	"sigs.k8s.io/kind/pkg/exec"
+)
+
+// proxyDropInPath is the systemd drop-in of containerd with the proxy settings of the cluster.
+// Its Environment overrides the DefaultEnvironment the node image writes from the proxy variables KIND passes on.
+// ref: https://github.com/kubernetes-sigs/kind/blob/v0.11.1/images/base/files/usr/local/bin/entrypoint
+const proxyDropInPath = "/etc/systemd/system/containerd.service.d/pulumi-kind-proxy.conf"
+
+// proxySettings are the proxy settings of the node containers and of containerd in the nodes
+type proxySettings struct {
+	HTTPProxy  string `json:"httpProxy,omitempty"`
+	HTTPSProxy string `json:"httpsProxy,omitempty"`
+	NoProxy    string `json:"noProxy,omitempty"`
+}
+
+// env returns the proxy environment variables KIND passes to the node containers in upper and lower case,
+// empty values unset the ones of the provider. KIND extends NO_PROXY the same way noProxy does.
+// ref: https://github.com/kubernetes-sigs/kind/blob/v0.11.1/pkg/cluster/internal/providers/common/proxy.go
+func (p *proxySettings) env() map[string]string {
+	env := map[string]string{}
+	for name, value := range map[string]string{
+		"HTTP_PROXY":  p.HTTPProxy,
+		"HTTPS_PROXY": p.HTTPSProxy,
+		"NO_PROXY":    p.NoProxy,
+	} {
+		env[name] = value
+		env[strings.ToLower(name)] = value
+	}
+	return env
+}
+
+// noProxy extends NO_PROXY with the pod and service subnets, the subnets of the node network, the node names and
+// the service domains the same way KIND does with the NO_PROXY of its environment
+// ref: https://github.com/kubernetes-sigs/kind/blob/v0.11.1/pkg/cluster/internal/providers/docker/provision.go#L295
+func (p *proxySettings) noProxy(config *v1alpha4.Cluster, networkSubnets, nodeNames []string) string {
+	defaulted := config.DeepCopy()
+	v1alpha4.SetDefaultsCluster(defaulted)
+
+	var entries []string
+	if p.NoProxy != "" {
+		entries = append(entries, p.NoProxy)
+	}
+	entries = append(entries, splitCIDRs(defaulted.Networking.PodSubnet)...)
+	entries = append(entries, splitCIDRs(defaulted.Networking.ServiceSubnet)...)
+	entries = append(entries, networkSubnets...)
+	entries = append(entries, nodeNames...)
+	entries = append(entries, ".svc", ".svc.cluster", ".svc.cluster.local")
+	return strings.Join(entries, ",")
+}
+
+// systemdQuoteReplacer escapes the values of quoted systemd settings, % starts a specifier
+var systemdQuoteReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "%", "%%")
+
+// dropIn renders the containerd drop-in with the proxy variables in upper and lower case,
+// empty values override the proxy settings of the provider environment
+func (p *proxySettings) dropIn(noProxy string) string {
+	var environment []string
+	for _, variable := range []struct{ name, value string }{
+		{"HTTP_PROXY", p.HTTPProxy},
+		{"HTTPS_PROXY", p.HTTPSProxy},
+		{"NO_PROXY", noProxy},
+	} {
+		for _, name := range []string{variable.name, strings.ToLower(variable.name)} {
+			environment = append(environment, fmt.Sprintf(`"%s=%s"`, name, systemdQuoteReplacer.Replace(variable.value)))
+		}
+	}
+	return fmt.Sprintf("# generated by pulumi-resource-kind\n[Service]\nEnvironment=%s\n", strings.Join(environment, " "))
+}
+
+// applyProxy writes the proxy settings of the cluster into the containerd drop-in of every node, so they can be
+// changed in place. The node containers keep the environment they were created with, which containerd falls back
+// to once the settings are removed.
+func (k *kindProvider) applyProxy(provider *cluster.Provider, clusterName string, config *v1alpha4.Cluster, network string, proxy *proxySettings) error {
+	internalNodes, err := selectNodes(provider, clusterName, nil)
+	if err != nil {
+		return err
+	}
+	if proxy == nil {
+		for _, node := range internalNodes {
+			if err = removeProxyDropIn(node); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	networkSubnets, err := container.NewRuntime(k.opts.Provider).NetworkSubnets(network)
+	if err != nil {
+		return err
+	}
+	allNodes, err := provider.ListNodes(clusterName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to list nodes of KIND cluster %s", clusterName)
+	}
+	var nodeNames []string
+	for _, node := range allNodes {
+		nodeNames = append(nodeNames, node.String())
+	}
+	sort.Strings(nodeNames)
+
+	dropIn := proxy.dropIn(proxy.noProxy(config, networkSubnets, nodeNames))
+	for _, node := range internalNodes {
+		if err = writeProxyDropIn(node, dropIn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyProxyDropIn copies the containerd drop-in of the control plane to a new node, if the cluster has one
+func copyProxyDropIn(controlPlane, node nodes.Node) error {
+	if err := controlPlane.Command("test", "-f", proxyDropInPath).Run(); err != nil {
+		return nil
+	}
+	lines, err := exec.OutputLines(controlPlane.Command("cat", proxyDropInPath))
+	if err != nil {
+		return errors.Wrapf(err, "failed to read proxy settings of %s", controlPlane.String())
+	}
+	return writeProxyDropIn(node, strings.Join(lines, "\n")+"\n")
+}
+
+// writeProxyDropIn writes the containerd drop-in into the node and restarts containerd with it
+func writeProxyDropIn(node nodes.Node, dropIn string) error {
+	pulumilog.V(3).Infof("writing proxy settings to node %s", node.String())
+	if err := node.Command("mkdir", "-p", path.Dir(proxyDropInPath)).Run(); err != nil {
+		return errors.Wrapf(err, "failed to create containerd drop-in directory on %s", node.String())
+	}
+	if err := nodeutils.WriteFile(node, proxyDropInPath, dropIn); err != nil {
+		return errors.Wrapf(err, "failed to write proxy settings to %s", node.String())
+	}
+	if err := node.Command("systemctl", "daemon-reload").Run(); err != nil {
+		return errors.Wrapf(err, "failed to reload systemd on %s", node.String())
+	}
+	// running containers are not affected by restarting containerd
+	if err := node.Command("systemctl", "restart", "containerd").Run(); err != nil {
+		return errors.Wrapf(err, "failed to restart containerd on %s", node.String())
+	}
+	return nil
+}
+
+// removeProxyDropIn removes the containerd drop-in from the node, if it has one, and restarts containerd without it
+func removeProxyDropIn(node nodes.Node) error {
+	if err := node.Command("test", "-f", proxyDropInPath).Run(); err != nil {
+		return nil
+	}
+	pulumilog.V(3).Infof("removing proxy settings from node %s", node.String())
+	if err := node.Command("rm", "-f", proxyDropInPath).Run(); err != nil {
+		return errors.Wrapf(err, "failed to remove proxy settings from %s", node.String())
+	}
+	if err := node.Command("systemctl", "daemon-reload").Run(); err != nil {
+		return errors.Wrapf(err, "failed to reload systemd on %s", node.String())
+	}
+	if err := node.Command("systemctl", "restart", "containerd").Run(); err != nil {
+		return errors.Wrapf(err, "failed to restart containerd on %s", node.String())
+	}
+	return nil
+}
+
+// checkProxy validates the known proxy URLs
+func checkProxy(news resource.PropertyMap) []*rpc.CheckFailure {
+	proxy := news["proxy"]
+	if !proxy.IsObject() {
+		return nil
+	}
+	var failures []*rpc.CheckFailure
+	for _, key := range []resource.PropertyKey{"httpProxy", "httpsProxy"} {
+		value := proxy.ObjectValue()[key]
+		if !value.IsString() {
+			continue
+		}
+		u, err := url.Parse(value.StringValue())
+		if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "socks5") {
+			failures = append(failures, &rpc.CheckFailure{
+				Property: fmt.Sprintf("proxy.%s", key),
+				Reason:   fmt.Sprintf("%q is not a proxy URL like http://proxy.example.com:3128", value.StringValue()),
+			})
+		}
+	}
+	if noProxy := proxy.ObjectValue()["noProxy"]; noProxy.IsString() && strings.ContainsAny(noProxy.StringValue(), " \t\n") {
+		failures = append(failures, &rpc.CheckFailure{
+			Property: "proxy.noProxy",
+			Reason:   "noProxy is a comma separated list without spaces",
+		})
+	}
+	return failures
+}
@@ -0,0 +1,603 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/frezbo/pulumi-provider-kind/provider/v3/pkg/container"
+	pbempty "github.com/golang/protobuf/ptypes/empty"
+	structpb "github.com/golang/protobuf/ptypes/struct"
+	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	pulumilog "github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+	rpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+	"sigs.k8s.io/kind/pkg/cluster"
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
+	"sigs.k8s.io/kind/pkg/exec"
+)
+
+const (
+	localRegistryType = tokens.Type("kind:registry:LocalRegistry")
+
+	// registryPort is the port the registry image listens on
+	registryPort = 5000
+	// containerdConfig is the containerd config of the node images
+	containerdConfig = "/etc/containerd/config.toml"
+	// localRegistryHostingConfigMap documents the local registry in the cluster
+	// ref: https://github.com/kubernetes/enhancements/tree/master/keps/sig-cluster-lifecycle/generic/1755-communicating-a-local-registry
+	localRegistryHostingConfigMap = "local-registry-hosting"
+)
+
+// containerNameRE is the container name format of docker and podman
+var containerNameRE = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// localRegistry are the inputs and outputs of a LocalRegistry
+type localRegistry struct {
+	// Name is the name of the registry container
+	Name          string `json:"name"`
+	Image         string `json:"image"`
+	HostPort      int    `json:"hostPort"`
+	ListenAddress string `json:"listenAddress"`
+	// Network is the docker/podman network of the KIND clusters the registry joins
+	Network string `json:"network"`
+	// ClusterNames are the clusters whose nodes pull through the registry
+	ClusterNames []string `json:"clusterNames,omitempty"`
+	// HostAddress is the address of the registry on the host, which is also
+	// the registry host the nodes resolve to the registry container
+	HostAddress string `json:"hostAddress,omitempty"`
+	// ClusterAddress is the address of the registry on the KIND network
+	ClusterAddress string `json:"clusterAddress,omitempty"`
+	// ConfiguredClusters are the clusters the registry is configured in
+	ConfiguredClusters []string `json:"configuredClusters,omitempty"`
+}
+
+func propMapToLocalRegistry(props resource.PropertyMap) (*localRegistry, error) {
+	registry := &localRegistry{}
+	data, err := json.Marshal(props.Mappable())
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(data, registry); err != nil {
+		return nil, err
+	}
+	return registry, nil
+}
+
+func (r *localRegistry) marshal(label string) (*structpb.Struct, error) {
+	value, err := toOutputValue(r)
+	if err != nil {
+		return nil, err
+	}
+	return plugin.MarshalProperties(
+		resource.NewPropertyMapFromMap(value.(map[string]interface{})),
+		plugin.MarshalOptions{Label: label, KeepUnknowns: true, SkipNulls: true},
+	)
+}
+
+// resolveAddresses sets the addresses of the running registry container,
+// with the host port allocated by the runtime when hostPort is 0
+func (r *localRegistry) resolveAddresses(runtime *container.Runtime) error {
+	bindings, err := runtime.PortBindings(r.Name)
+	if err != nil {
+		return err
+	}
+	for _, binding := range bindings {
+		if binding.ContainerPort != registryPort {
+			continue
+		}
+		host := r.ListenAddress
+		if ip := net.ParseIP(host); ip == nil || ip.IsLoopback() || ip.IsUnspecified() {
+			host = "localhost"
+		}
+		r.HostAddress = net.JoinHostPort(host, strconv.Itoa(binding.HostPort))
+		r.ClusterAddress = net.JoinHostPort(r.Name, strconv.Itoa(registryPort))
+		return nil
+	}
+	return errors.Errorf("registry port %d of %s is not published", registryPort, r.Name)
+}
+
+// registryBlockMarkers delimit the containerd config of the registry in the nodes
+func registryBlockMarkers(name string) (string, string) {
+	return fmt.Sprintf("# BEGIN pulumi-kind local registry %s\n", name), fmt.Sprintf("# END pulumi-kind local registry %s\n", name)
+}
+
+// addRegistryBlock appends the mirror of the registry to the containerd config,
+// the config is returned as is when it already has it
+func addRegistryBlock(config string, registry *localRegistry) string {
+	begin, end := registryBlockMarkers(registry.Name)
+	if strings.Contains(config, begin) {
+		return config
+	}
+	if config != "" && !strings.HasSuffix(config, "\n") {
+		config += "\n"
+	}
+	mirror := registryMirrorsPatch(map[string]registryMirror{
+		registry.HostAddress: {Endpoints: []string{"http://" + registry.ClusterAddress}},
+	})
+	return config + begin + mirror + end
+}
+
+// removeRegistryBlock removes the mirror of the registry from the containerd config
+func removeRegistryBlock(config, name string) string {
+	begin, end := registryBlockMarkers(name)
+	start := strings.Index(config, begin)
+	if start < 0 {
+		return config
+	}
+	stop := strings.Index(config[start:], end)
+	if stop < 0 {
+		return config[:start]
+	}
+	return config[:start] + config[start+stop+len(end):]
+}
+
+// localRegistryHosting is the local-registry-hosting ConfigMap of the registry
+func localRegistryHosting(registry *localRegistry) string {
+	return fmt.Sprintf(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: %s
+  namespace: kube-public
+data:
+  localRegistryHosting.v1: |
+    host: %q
+    hostFromContainerRuntime: %q
+    hostFromClusterNetwork: %q
+    help: "https://kind.sigs.k8s.io/docs/user/local-registry/"
+`, localRegistryHostingConfigMap, registry.HostAddress, registry.HostAddress, registry.ClusterAddress)
+}
+
+// editContainerdConfig rewrites the containerd config of the node and restarts containerd when it changed
+func editContainerdConfig(node nodes.Node, edit func(string) string) error {
+	lines, err := exec.OutputLines(node.Command("cat", containerdConfig))
+	if err != nil {
+		return errors.Wrapf(err, "failed to read containerd config of %s", node.String())
+	}
+	config := strings.Join(lines, "\n") + "\n"
+	updated := edit(config)
+	if updated == config {
+		return nil
+	}
+	if err = nodeutils.WriteFile(node, containerdConfig, updated); err != nil {
+		return errors.Wrapf(err, "failed to write containerd config of %s", node.String())
+	}
+	// running containers are not affected by restarting containerd
+	if err = node.Command("systemctl", "restart", "containerd").Run(); err != nil {
+		return errors.Wrapf(err, "failed to restart containerd on %s", node.String())
+	}
+	return nil
+}
+
+// configureRegistry makes the nodes of the cluster pull the images of the registry host address
+// from the registry container and documents the registry in the cluster
+func configureRegistry(provider *cluster.Provider, clusterName string, registry *localRegistry) error {
+	pulumilog.V(3).Infof("configuring local registry %s in KIND cluster %s", registry.Name, clusterName)
+	internalNodes, err := selectNodes(provider, clusterName, nil)
+	if err != nil {
+		return err
+	}
+	for _, node := range internalNodes {
+		if err = editContainerdConfig(node, func(config string) string {
+			return addRegistryBlock(config, registry)
+		}); err != nil {
+			return err
+		}
+	}
+
+	controlPlane, err := nodeutils.BootstrapControlPlaneNode(internalNodes)
+	if err != nil {
+		return err
+	}
+	cmd := controlPlane.Command("kubectl", "--kubeconfig=/etc/kubernetes/admin.conf", "apply", "-f", "-")
+	cmd.SetStdin(strings.NewReader(localRegistryHosting(registry)))
+	if lines, err := exec.CombinedOutputLines(cmd); err != nil {
+		return errors.Wrapf(err, "failed to apply %s ConfigMap: %s", localRegistryHostingConfigMap, strings.Join(lines, "\n"))
+	}
+	return nil
+}
+
+// unconfigureRegistry reverts configureRegistry, clusters that are gone are skipped
+func unconfigureRegistry(provider *cluster.Provider, clusterName string, registry *localRegistry) error {
+	exists, err := clusterExists(provider, clusterName)
+	if err != nil || !exists {
+		return err
+	}
+	pulumilog.V(3).Infof("removing local registry %s from KIND cluster %s", registry.Name, clusterName)
+	internalNodes, err := selectNodes(provider, clusterName, nil)
+	if err != nil {
+		return err
+	}
+	for _, node := range internalNodes {
+		if err = editContainerdConfig(node, func(config string) string {
+			return removeRegistryBlock(config, registry.Name)
+		}); err != nil {
+			return err
+		}
+	}
+	controlPlane, err := nodeutils.BootstrapControlPlaneNode(internalNodes)
+	if err != nil {
+		return err
+	}
+	return kubectl(controlPlane, "delete", "configmap", localRegistryHostingConfigMap, "--namespace=kube-public", "--ignore-not-found")
+}
+
+// registryConfigured checks if all the nodes of the cluster pull through the registry
+func registryConfigured(provider *cluster.Provider, clusterName string, registry *localRegistry) bool {
+	internalNodes, err := selectNodes(provider, clusterName, nil)
+	if err != nil || len(internalNodes) == 0 {
+		return false
+	}
+	begin, _ := registryBlockMarkers(registry.Name)
+	for _, node := range internalNodes {
+		lines, err := exec.OutputLines(node.Command("cat", containerdConfig))
+		if err != nil || !strings.Contains(strings.Join(lines, "\n")+"\n", begin) {
+			return false
+		}
+	}
+	return true
+}
+
+// reconcileClusters configures the registry in the clusters it's not configured in yet
+// and removes it from the configured clusters that are not wanted anymore
+func reconcileClusters(provider *cluster.Provider, registry *localRegistry, configured []string) error {
+	wanted := map[string]bool{}
+	for _, clusterName := range registry.ClusterNames {
+		wanted[clusterName] = true
+	}
+	done := map[string]bool{}
+	for _, clusterName := range configured {
+		if !wanted[clusterName] {
+			if err := unconfigureRegistry(provider, clusterName, registry); err != nil {
+				return err
+			}
+			continue
+		}
+		done[clusterName] = true
+	}
+
+	registry.ConfiguredClusters = nil
+	for _, clusterName := range registry.ClusterNames {
+		if !done[clusterName] || !registryConfigured(provider, clusterName, registry) {
+			if err := configureRegistry(provider, clusterName, registry); err != nil {
+				return err
+			}
+		}
+		registry.ConfiguredClusters = append(registry.ConfiguredClusters, clusterName)
+	}
+	return nil
+}
+
+// defaultRegistryNetwork is the network of the KIND clusters, which can be overridden by KIND with an environment variable
+// ref: https://github.com/kubernetes-sigs/kind/blob/v0.11.1/pkg/cluster/internal/providers/docker/network.go#L42
+func defaultRegistryNetwork() string {
+	if network := getenv("KIND_EXPERIMENTAL_DOCKER_NETWORK"); network != "" {
+		return network
+	}
+	return "kind"
+}
+
+func (k *kindProvider) checkLocalRegistry(req *rpc.CheckRequest) (*rpc.CheckResponse, error) {
+	urn := resource.URN(req.GetUrn())
+	label := fmt.Sprintf("%s.Check(%s)", k.name, urn)
+
+	news, err := plugin.UnmarshalProperties(req.GetNews(), plugin.MarshalOptions{
+		Label:        fmt.Sprintf("%s.news", label),
+		KeepUnknowns: true,
+		SkipNulls:    true,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "check failed because malformed resource inputs")
+	}
+
+	defaults := resource.NewPropertyMapFromMap(map[string]interface{}{
+		"name":          "kind-registry",
+		"image":         "registry:2",
+		"hostPort":      5001,
+		"listenAddress": "127.0.0.1",
+		"network":       defaultRegistryNetwork(),
+	})
+	for key, value := range defaults {
+		if _, ok := news[key]; !ok {
+			news[key] = value
+		}
+	}
+
+	var failures []*rpc.CheckFailure
+	if name := news["name"]; name.IsString() && !containerNameRE.MatchString(name.StringValue()) {
+		failures = append(failures, &rpc.CheckFailure{
+			Property: "name",
+			Reason:   fmt.Sprintf("%q is not a valid container name", name.StringValue()),
+		})
+	}
+	if port := news["hostPort"]; port.IsNumber() && (port.NumberValue() < 0 || port.NumberValue() > 65535) {
+		failures = append(failures, &rpc.CheckFailure{
+			Property: "hostPort",
+			Reason:   fmt.Sprintf("%v is not a port, use 0 for a random port", port.NumberValue()),
+		})
+	}
+	if address := news["listenAddress"]; address.IsString() && net.ParseIP(address.StringValue()) == nil {
+		failures = append(failures, &rpc.CheckFailure{
+			Property: "listenAddress",
+			Reason:   fmt.Sprintf("%q is not an IP address", address.StringValue()),
+		})
+	}
+	if image := news["image"]; image.IsString() && !k.unknownConfig && k.offlineImageMissing(image.StringValue()) {
+		failures = append(failures, &rpc.CheckFailure{
+			Property: "image",
+			Reason:   fmt.Sprintf("image %s is not available locally and the provider is offline", image.StringValue()),
+		})
+	}
+
+	inputs, err := plugin.MarshalProperties(news, plugin.MarshalOptions{
+		Label:        fmt.Sprintf("%s.news", label),
+		KeepUnknowns: true,
+		SkipNulls:    true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &rpc.CheckResponse{Inputs: inputs, Failures: failures}, nil
+}
+
+func (k *kindProvider) diffLocalRegistry(req *rpc.DiffRequest) (*rpc.DiffResponse, error) {
+	label := fmt.Sprintf("%s.Diff(%s)", k.name, req.GetUrn())
+
+	olds, err := plugin.UnmarshalProperties(req.GetOlds(), plugin.MarshalOptions{
+		Label:        fmt.Sprintf("%s.olds", label),
+		KeepUnknowns: true,
+		SkipNulls:    true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	news, err := plugin.UnmarshalProperties(req.GetNews(), plugin.MarshalOptions{
+		Label:        fmt.Sprintf("%s.news", label),
+		KeepUnknowns: true,
+		SkipNulls:    true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var replaces []string
+	for _, key := range []resource.PropertyKey{"name", "image", "hostPort", "listenAddress", "network"} {
+		if !olds[key].DeepEquals(news[key]) {
+			replaces = append(replaces, string(key))
+		}
+	}
+	// clusters recreated since the registry was configured in them show up as drift after a refresh
+	clustersChanged := !olds["clusterNames"].DeepEquals(news["clusterNames"]) ||
+		!olds["configuredClusters"].DeepEquals(news["clusterNames"])
+
+	switch {
+	case len(replaces) > 0:
+		// the container name and host port can't be used by two registries at once
+		return &rpc.DiffResponse{
+			Changes:             rpc.DiffResponse_DIFF_SOME,
+			Replaces:            replaces,
+			DeleteBeforeReplace: true,
+		}, nil
+	case clustersChanged:
+		return &rpc.DiffResponse{
+			Changes: rpc.DiffResponse_DIFF_SOME,
+			Diffs:   []string{"clusterNames"},
+		}, nil
+	default:
+		return &rpc.DiffResponse{Changes: rpc.DiffResponse_DIFF_NONE}, nil
+	}
+}
+
+func (k *kindProvider) createLocalRegistry(req *rpc.CreateRequest) (*rpc.CreateResponse, error) {
+	urn := resource.URN(req.GetUrn())
+	label := fmt.Sprintf("%s.Create(%s)", k.name, urn)
+
+	news, err := plugin.UnmarshalProperties(req.GetProperties(), plugin.MarshalOptions{
+		Label:        fmt.Sprintf("%s.news", label),
+		KeepUnknowns: true,
+		SkipNulls:    true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetPreview() {
+		news["hostAddress"] = resource.MakeComputed(resource.NewStringProperty(""))
+		news["clusterAddress"] = resource.MakeComputed(resource.NewStringProperty(""))
+		news["configuredClusters"] = resource.MakeComputed(resource.NewStringProperty(""))
+		properties, err := plugin.MarshalProperties(news, plugin.MarshalOptions{KeepUnknowns: true, SkipNulls: true})
+		if err != nil {
+			return nil, err
+		}
+		return &rpc.CreateResponse{Properties: properties}, nil
+	}
+
+	registry, err := propMapToLocalRegistry(news)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Create():")
+	}
+
+	runtime := container.NewRuntime(k.opts.Provider)
+	if runtime.Exists(registry.Name) {
+		return nil, errors.Errorf("container %s already exists", registry.Name)
+	}
+	if k.offlineImageMissing(registry.Image) {
+		return nil, errors.Errorf("image %s is not available locally and the provider is offline", registry.Image)
+	}
+	// KIND creates its network along with the first cluster
+	if !runtime.NetworkExists(registry.Network) {
+		return nil, errors.Errorf("network %s does not exist, create a KIND cluster first", registry.Network)
+	}
+	if err = runtime.RunRegistry(&container.RegistrySpec{
+		Name:    registry.Name,
+		Image:   registry.Image,
+		Network: registry.Network,
+		Port:    fmt.Sprintf("%s:%d:%d", registry.ListenAddress, registry.HostPort, registryPort),
+	}); err != nil {
+		return nil, errors.Wrapf(err, "failed to run registry %s", registry.Name)
+	}
+	if err = registry.resolveAddresses(runtime); err != nil {
+		return nil, err
+	}
+	if err = reconcileClusters(k.newClusterProvider(urn), registry, nil); err != nil {
+		return nil, err
+	}
+
+	properties, err := registry.marshal(fmt.Sprintf("%s.outputs", label))
+	if err != nil {
+		return nil, err
+	}
+	return &rpc.CreateResponse{
+		Id:         registry.Name,
+		Properties: properties,
+	}, nil
+}
+
+func (k *kindProvider) readLocalRegistry(req *rpc.ReadRequest) (*rpc.ReadResponse, error) {
+	urn := resource.URN(req.GetUrn())
+	label := fmt.Sprintf("%s.Read(%s)", k.name, urn)
+
+	properties, err := plugin.UnmarshalProperties(req.GetProperties(), plugin.MarshalOptions{
+		Label:        fmt.Sprintf("%s.properties", label),
+		KeepUnknowns: true,
+		SkipNulls:    true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	registry, err := propMapToLocalRegistry(properties)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Read():")
+	}
+
+	runtime := container.NewRuntime(k.opts.Provider)
+	if !runtime.Exists(registry.Name) {
+		return &rpc.ReadResponse{}, nil
+	}
+	if running, err := runtime.Running(registry.Name); err == nil && running {
+		if err = registry.resolveAddresses(runtime); err != nil {
+			return nil, err
+		}
+	}
+
+	provider := k.newClusterProvider(urn)
+	configured := registry.ConfiguredClusters
+	registry.ConfiguredClusters = nil
+	for _, clusterName := range configured {
+		if registryConfigured(provider, clusterName, registry) {
+			registry.ConfiguredClusters = append(registry.ConfiguredClusters, clusterName)
+		}
+	}
+
+	outputs, err := registry.marshal(fmt.Sprintf("%s.outputs", label))
+	if err != nil {
+		return nil, err
+	}
+	return &rpc.ReadResponse{Id: req.GetId(), Properties: outputs}, nil
+}
+
+func (k *kindProvider) updateLocalRegistry(req *rpc.UpdateRequest) (*rpc.UpdateResponse, error) {
+	urn := resource.URN(req.GetUrn())
+	label := fmt.Sprintf("%s.Update(%s)", k.name, urn)
+
+	olds, err := plugin.UnmarshalProperties(req.GetOlds(), plugin.MarshalOptions{
+		Label:        fmt.Sprintf("%s.olds", label),
+		KeepUnknowns: true,
+		SkipNulls:    true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	news, err := plugin.UnmarshalProperties(req.GetNews(), plugin.MarshalOptions{
+		Label:        fmt.Sprintf("%s.news", label),
+		KeepUnknowns: true,
+		SkipNulls:    true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	oldRegistry, err := propMapToLocalRegistry(olds)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Update():")
+	}
+	registry, err := propMapToLocalRegistry(news)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Update():")
+	}
+
+	if req.GetPreview() {
+		news["hostAddress"] = resource.NewStringProperty(oldRegistry.HostAddress)
+		news["clusterAddress"] = resource.NewStringProperty(oldRegistry.ClusterAddress)
+		news["configuredClusters"] = resource.MakeComputed(resource.NewStringProperty(""))
+		properties, err := plugin.MarshalProperties(news, plugin.MarshalOptions{KeepUnknowns: true, SkipNulls: true})
+		if err != nil {
+			return nil, err
+		}
+		return &rpc.UpdateResponse{Properties: properties}, nil
+	}
+
+	// Diff only allows the clusters to change in place
+	registry.HostAddress, registry.ClusterAddress = oldRegistry.HostAddress, oldRegistry.ClusterAddress
+	if err = reconcileClusters(k.newClusterProvider(urn), registry, oldRegistry.ConfiguredClusters); err != nil {
+		return nil, err
+	}
+
+	properties, err := registry.marshal(fmt.Sprintf("%s.outputs", label))
+	if err != nil {
+		return nil, err
+	}
+	return &rpc.UpdateResponse{Properties: properties}, nil
+}
+
+func (k *kindProvider) deleteLocalRegistry(req *rpc.DeleteRequest) (*pbempty.Empty, error) {
+	urn := resource.URN(req.GetUrn())
+	label := fmt.Sprintf("%s.Delete(%s)", k.name, urn)
+
+	properties, err := plugin.UnmarshalProperties(req.GetProperties(), plugin.MarshalOptions{
+		Label:        fmt.Sprintf("%s.properties", label),
+		KeepUnknowns: true,
+		SkipNulls:    true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	registry, err := propMapToLocalRegistry(properties)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Delete():")
+	}
+
+	provider := k.newClusterProvider(urn)
+	for _, clusterName := range registry.ConfiguredClusters {
+		if err = unconfigureRegistry(provider, clusterName, registry); err != nil {
+			return nil, err
+		}
+	}
+
+	runtime := container.NewRuntime(k.opts.Provider)
+	if runtime.Exists(registry.Name) {
+		if err = runtime.Remove(registry.Name); err != nil {
+			return nil, errors.Wrapf(err, "failed to remove registry %s", registry.Name)
+		}
+	}
+	return &pbempty.Empty{}, nil
+}
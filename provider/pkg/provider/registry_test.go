@@ -0,0 +1,44 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRegistryBlock(t *testing.T) {
+	const config = "version = 2\n"
+	registry := &localRegistry{Name: "kind-registry", HostAddress: "localhost:5001", ClusterAddress: "kind-registry:5000"}
+
+	added := addRegistryBlock(config, registry)
+	expected := `version = 2
+# BEGIN pulumi-kind local registry kind-registry
+[plugins."io.containerd.grpc.v1.cri".registry.mirrors."localhost:5001"]
+  endpoint = ["http://kind-registry:5000"]
+# END pulumi-kind local registry kind-registry
+`
+	if added != expected {
+		t.Fatalf("expected\n%s\ngot\n%s", expected, added)
+	}
+	if again := addRegistryBlock(added, registry); again != added {
+		t.Errorf("expected the block to be added once, got\n%s", again)
+	}
+
+	other := &localRegistry{Name: "other", HostAddress: "localhost:5002", ClusterAddress: "other:5000"}
+	both := addRegistryBlock(added, other)
+	if removed := removeRegistryBlock(both, registry.Name); removed != addRegistryBlock(config, other) {
+		t.Errorf("expected only the other registry to be left, got\n%s", removed)
+	}
+	if removed := removeRegistryBlock(added, registry.Name); removed != config {
+		t.Errorf("expected %q, got %q", config, removed)
+	}
+}
+
+func TestLocalRegistryHosting(t *testing.T) {
+	registry := &localRegistry{Name: "kind-registry", HostAddress: "localhost:5001", ClusterAddress: "kind-registry:5000"}
+	manifest := localRegistryHosting(registry)
+	for _, expected := range []string{"name: local-registry-hosting", "namespace: kube-public", `host: "localhost:5001"`, `hostFromClusterNetwork: "kind-registry:5000"`} {
+		if !strings.Contains(manifest, expected) {
+			t.Errorf("expected %s in\n%s", expected, manifest)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"fmt"
+
+	"github.com/frezbo/pulumi-provider-kind/provider/v3/pkg/container"
+	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	rpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+	"sigs.k8s.io/kind/pkg/cluster"
+	"sigs.k8s.io/kind/pkg/cluster/constants"
+)
+
+const (
+	// clusterStateRunning has all the node containers running
+	clusterStateRunning = "running"
+	// clusterStateStopped has at least one node container stopped
+	clusterStateStopped = "stopped"
+)
+
+// nodeStartOrder is the order node containers are started in, they are stopped in reverse
+var nodeStartOrder = []string{
+	constants.ExternalLoadBalancerNodeRoleValue,
+	constants.ControlPlaneNodeRoleValue,
+	constants.WorkerNodeRoleValue,
+}
+
+// clusterState returns the desired state of the cluster
+func (o *clusterOptions) clusterState() string {
+	if o.State == "" {
+		return clusterStateRunning
+	}
+	return o.State
+}
+
+// nodesByRole returns the node container names of the cluster in start order
+func nodesByRole(details []nodeDetail) [][]string {
+	groups := make([][]string, len(nodeStartOrder))
+	for _, detail := range details {
+		for i, role := range nodeStartOrder {
+			if detail.Role == role {
+				groups[i] = append(groups[i], detail.Name)
+			}
+		}
+	}
+	return groups
+}
+
+// liveClusterState reports the cluster as running only when all of its node containers are
+func (k *kindProvider) liveClusterState(details []nodeDetail) (string, error) {
+	runtime := container.NewRuntime(k.opts.Provider)
+	for _, detail := range details {
+		running, err := runtime.Running(detail.Name)
+		if err != nil {
+			return "", err
+		}
+		if !running {
+			return clusterStateStopped, nil
+		}
+	}
+	return clusterStateRunning, nil
+}
+
+// setClusterState starts or stops the node containers of the cluster
+func (k *kindProvider) setClusterState(provider *cluster.Provider, clusterName, state string) error {
+	details, err := k.clusterNodeDetails(provider, clusterName)
+	if err != nil {
+		return err
+	}
+	groups := nodesByRole(details)
+	runtime := container.NewRuntime(k.opts.Provider)
+
+	switch state {
+	case clusterStateRunning:
+		for _, nodes := range groups {
+			if len(nodes) == 0 {
+				continue
+			}
+			if err = runtime.Start(nodes...); err != nil {
+				return errors.Wrapf(err, "failed to start nodes of KIND cluster %s", clusterName)
+			}
+		}
+	case clusterStateStopped:
+		// workers first, so the load balancer keeps serving the control planes until the end
+		for i := len(groups) - 1; i >= 0; i-- {
+			if len(groups[i]) == 0 {
+				continue
+			}
+			if err = runtime.Stop(groups[i]...); err != nil {
+				return errors.Wrapf(err, "failed to stop nodes of KIND cluster %s", clusterName)
+			}
+		}
+	default:
+		return errors.Errorf("unknown cluster state %q", state)
+	}
+	return nil
+}
+
+// checkClusterState validates the desired cluster state if it's known
+func checkClusterState(news resource.PropertyMap) []*rpc.CheckFailure {
+	state := news["state"]
+	if !state.IsString() {
+		return nil
+	}
+	switch state.StringValue() {
+	case clusterStateRunning, clusterStateStopped:
+		return nil
+	default:
+		return []*rpc.CheckFailure{{
+			Property: "state",
+			Reason:   fmt.Sprintf("Valid state values are %s/%s, got %q", clusterStateRunning, clusterStateStopped, state.StringValue()),
+		}}
+	}
+}
@@ -0,0 +1,281 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/frezbo/pulumi-provider-kind/provider/v3/pkg/container"
+	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	pulumilog "github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+	rpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+	"sigs.k8s.io/kind/pkg/apis/config/v1alpha4"
+	"sigs.k8s.io/kind/pkg/cluster"
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
+	"sigs.k8s.io/kind/pkg/exec"
+)
+
+const (
+	// upgradeStrategyReplace recreates the cluster when the node images change
+	upgradeStrategyReplace = "replace"
+	// upgradeStrategyInPlace upgrades the nodes with kubeadm when the node images change
+	upgradeStrategyInPlace = "inPlace"
+)
+
+// nodeImageVersionRE matches the Kubernetes version in the tag of kindest/node images
+var nodeImageVersionRE = regexp.MustCompile(`:v(\d+)\.(\d+)\.(\d+)(@sha256:[0-9a-f]+)?$`)
+
+// upgradeBinaries are the files of the node image needed to upgrade a node, along with the
+// archives of the images of the control plane components preloaded into the node image
+var upgradeBinaries = []string{"/usr/bin/kubeadm", "/usr/bin/kubelet", "/usr/bin/kubectl", "/kind/version", "/kind/images"}
+
+// upgradedImageFile records the node image a node was upgraded to in place, as its container keeps the old image
+const upgradedImageFile = "/kind/pulumi-upgraded-image"
+
+// nodeContainerImage returns the node image whose Kubernetes version the node runs
+func nodeContainerImage(runtime *container.Runtime, name string) (string, error) {
+	if upgraded, err := runtime.ReadFile(name, upgradedImageFile); err == nil && strings.TrimSpace(upgraded) != "" {
+		return strings.TrimSpace(upgraded), nil
+	}
+	return runtime.Image(name)
+}
+
+// imageVersion returns the major, minor and patch Kubernetes version of the node image from its tag
+func imageVersion(image string) ([3]int, bool) {
+	var version [3]int
+	match := nodeImageVersionRE.FindStringSubmatch(image)
+	if match == nil {
+		return version, false
+	}
+	for i := range version {
+		version[i], _ = strconv.Atoi(match[i+1])
+	}
+	return version, true
+}
+
+// canUpgradeInPlace checks if the configs only differ by node images that kubeadm can upgrade to:
+// all nodes move from the same version to the same version at most one minor version newer
+// ref: https://kubernetes.io/docs/tasks/administer-cluster/kubeadm/kubeadm-upgrade/
+func (k *kindProvider) canUpgradeInPlace(olds, news *v1alpha4.Cluster) bool {
+	oldNodes := desiredNodes(olds)
+	newNodes := desiredNodes(news)
+	if len(oldNodes) != len(newNodes) {
+		return false
+	}
+	oldRest, newRest := *olds, *news
+	oldRest.Nodes, newRest.Nodes = nil, nil
+	if !reflect.DeepEqual(oldRest, newRest) {
+		return false
+	}
+
+	var from, to [3]int
+	for i := range oldNodes {
+		oldNode, newNode := oldNodes[i], newNodes[i]
+		oldImage, newImage := k.nodeImage(oldNode), k.nodeImage(newNode)
+		oldNode.Image, newNode.Image = "", ""
+		if !reflect.DeepEqual(oldNode, newNode) {
+			return false
+		}
+
+		oldVersion, ok := imageVersion(oldImage)
+		if !ok {
+			return false
+		}
+		newVersion, ok := imageVersion(newImage)
+		if !ok {
+			return false
+		}
+		if i == 0 {
+			from, to = oldVersion, newVersion
+		}
+		if oldVersion != from || newVersion != to {
+			return false
+		}
+	}
+
+	switch {
+	case from == to, from[0] != to[0]:
+		return false
+	case to[1] == from[1]:
+		return to[2] > from[2]
+	default:
+		return to[1] == from[1]+1
+	}
+}
+
+// upgradeCluster upgrades the control plane nodes and then the workers with kubeadm using the binaries and preloaded
+// images of the new node images. The node containers keep their old image, so the new one is recorded in the nodes.
+func (k *kindProvider) upgradeCluster(provider *cluster.Provider, clusterName string, news *v1alpha4.Cluster) error {
+	// the container runtime pulls missing images when extracting the binaries
+	if err := k.verifyOfflineImages(news); err != nil {
+		return err
+	}
+
+	allNodes, err := provider.ListNodes(clusterName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to list nodes of KIND cluster %s", clusterName)
+	}
+	bootstrap, err := nodeutils.BootstrapControlPlaneNode(allNodes)
+	if err != nil {
+		return err
+	}
+	secondaries, err := nodeutils.SecondaryControlPlaneNodes(allNodes)
+	if err != nil {
+		return err
+	}
+	workers, err := nodeutils.SelectNodesByRole(allNodes, string(v1alpha4.WorkerRole))
+	if err != nil {
+		return err
+	}
+
+	dir, err := ioutil.TempDir("", "pulumi-kind-upgrade")
+	if err != nil {
+		return errors.Wrap(err, "failed to create temporary directory")
+	}
+	defer os.RemoveAll(dir)
+
+	// canUpgradeInPlace made sure every node is upgraded to the same version, so any of the new images will do
+	image := k.nodeImage(desiredNodes(news)[0])
+	runtime := container.NewRuntime(k.opts.Provider)
+	if err = runtime.CopyFromImage(image, dir, upgradeBinaries...); err != nil {
+		return errors.Wrapf(err, "failed to extract binaries from %s", image)
+	}
+	data, err := ioutil.ReadFile(filepath.Join(dir, "version"))
+	if err != nil {
+		return errors.Wrapf(err, "failed to read Kubernetes version of %s", image)
+	}
+	version := strings.TrimSpace(string(data))
+
+	pulumilog.V(3).Infof("upgrading KIND cluster %s to %s", clusterName, version)
+
+	// kubeadm pulls the images of the control plane components that are not in the nodes yet
+	archives, err := filepath.Glob(filepath.Join(dir, "images", "*.tar"))
+	if err != nil {
+		return err
+	}
+	internalNodes, err := selectNodes(provider, clusterName, nil)
+	if err != nil {
+		return err
+	}
+	for _, node := range internalNodes {
+		if err = importImageArchives(node, archives); err != nil {
+			return err
+		}
+	}
+
+	// the control plane is upgraded before the kubelets of any node
+	// ref: https://kubernetes.io/docs/tasks/administer-cluster/kubeadm/kubeadm-upgrade/
+	if err = k.upgradeNode(runtime, dir, image, bootstrap, bootstrap, "upgrade", "apply", version, "--yes"); err != nil {
+		return err
+	}
+	for _, node := range secondaries {
+		if err = k.upgradeNode(runtime, dir, image, bootstrap, node, "upgrade", "node"); err != nil {
+			return err
+		}
+	}
+	for _, node := range workers {
+		// kubectl of the control plane is upgraded already
+		if err = kubectl(bootstrap, "drain", node.String(), "--ignore-daemonsets", drainEmptyDirFlag(version), "--force"); err != nil {
+			return err
+		}
+		if err = k.upgradeNode(runtime, dir, image, bootstrap, node, "upgrade", "node"); err != nil {
+			return err
+		}
+		if err = kubectl(bootstrap, "uncordon", node.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// importImageArchives imports the image archives into the containerd of the node
+func importImageArchives(node nodes.Node, archives []string) error {
+	for _, archive := range archives {
+		f, err := os.Open(archive)
+		if err != nil {
+			return err
+		}
+		err = nodeutils.LoadImageArchive(node, f)
+		f.Close()
+		if err != nil {
+			return errors.Wrapf(err, "failed to import %s into %s", filepath.Base(archive), node.String())
+		}
+	}
+	return nil
+}
+
+// upgradeNode replaces kubeadm, runs the kubeadm upgrade command and then replaces the kubelet
+func (k *kindProvider) upgradeNode(runtime *container.Runtime, dir, image string, bootstrap, node nodes.Node, kubeadmArgs ...string) error {
+	name := node.String()
+	if err := runtime.CopyTo(name, filepath.Join(dir, "kubeadm"), "/usr/bin/kubeadm"); err != nil {
+		return err
+	}
+
+	lines, err := exec.CombinedOutputLines(node.Command("kubeadm", append(kubeadmArgs, "--v=6")...))
+	pulumilog.V(9).Infof(strings.Join(lines, "\n"))
+	if err != nil {
+		return errors.Wrapf(err, "failed to run kubeadm %s on %s", strings.Join(kubeadmArgs[:2], " "), name)
+	}
+
+	// the kubelet binary can't be replaced while it's running
+	if err = node.Command("systemctl", "stop", "kubelet").Run(); err != nil {
+		return errors.Wrapf(err, "failed to stop kubelet on %s", name)
+	}
+	for _, file := range []string{"kubelet", "kubectl"} {
+		if err = runtime.CopyTo(name, filepath.Join(dir, file), fmt.Sprintf("/usr/bin/%s", file)); err != nil {
+			return err
+		}
+	}
+	if err = runtime.CopyTo(name, filepath.Join(dir, "version"), "/kind/version"); err != nil {
+		return err
+	}
+	if err = nodeutils.WriteFile(node, upgradedImageFile, image+"\n"); err != nil {
+		return errors.Wrapf(err, "failed to record node image on %s", name)
+	}
+	if err = node.Command("systemctl", "start", "kubelet").Run(); err != nil {
+		return errors.Wrapf(err, "failed to start kubelet on %s", name)
+	}
+
+	if k.opts.WaitForNodeReady > 0 {
+		return kubectl(bootstrap, "wait", "--for=condition=Ready", "node/"+name, "--timeout="+k.opts.WaitForNodeReady.String())
+	}
+	return nil
+}
+
+// checkUpgradeStrategy validates the upgrade strategy if it's known
+func checkUpgradeStrategy(news resource.PropertyMap) []*rpc.CheckFailure {
+	strategy := news["upgradeStrategy"]
+	if !strategy.IsString() {
+		return nil
+	}
+	switch strategy.StringValue() {
+	case upgradeStrategyReplace, upgradeStrategyInPlace:
+		return nil
+	default:
+		return []*rpc.CheckFailure{{
+			Property: "upgradeStrategy",
+			Reason:   fmt.Sprintf("Valid upgradeStrategy values are %s/%s, got %q", upgradeStrategyReplace, upgradeStrategyInPlace, strategy.StringValue()),
+		}}
+	}
+}
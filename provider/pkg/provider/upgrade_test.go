@@ -0,0 +1,44 @@
+package provider
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/apis/config/v1alpha4"
+)
+
+func TestCanUpgradeInPlace(t *testing.T) {
+	const (
+		v1201 = "kindest/node:v1.20.7@sha256:cbeaf907fc78ac97ce7b625e4bf0de16e3ea725daf6b04f930bd14c67c671ff9"
+		v1211 = "kindest/node:v1.21.1"
+		v1212 = "kindest/node:v1.21.2"
+		v1221 = "kindest/node:v1.22.1"
+	)
+	k := &kindProvider{}
+
+	tests := []struct {
+		name     string
+		olds     []string
+		news     []string
+		expected bool
+	}{
+		{"patch upgrade", []string{v1211, v1211}, []string{v1212, v1212}, true},
+		{"minor upgrade", []string{v1201}, []string{v1211}, true},
+		{"two minor versions", []string{v1201}, []string{v1221}, false},
+		{"downgrade", []string{v1212}, []string{v1211}, false},
+		{"mixed versions", []string{v1211, v1211}, []string{v1212, v1221}, false},
+		{"untagged image", []string{v1211}, []string{"kindest/node@sha256:69860bda5563ac81e3c0057d654b5253219618a22ec3a346306239bba8cfa1a6"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			olds := &v1alpha4.Cluster{Name: "dev"}
+			news := &v1alpha4.Cluster{Name: "dev"}
+			for i := range tt.olds {
+				olds.Nodes = append(olds.Nodes, v1alpha4.Node{Role: v1alpha4.ControlPlaneRole, Image: tt.olds[i]})
+				news.Nodes = append(news.Nodes, v1alpha4.Node{Role: v1alpha4.ControlPlaneRole, Image: tt.news[i]})
+			}
+			if actual := k.canUpgradeInPlace(olds, news); actual != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, actual)
+			}
+		})
+	}
+}
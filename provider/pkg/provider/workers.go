@@ -0,0 +1,449 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/frezbo/pulumi-provider-kind/provider/v3/pkg/container"
+	"github.com/frezbo/pulumi-provider-kind/provider/v3/pkg/kubeconfig"
+	"github.com/pkg/errors"
+	pulumilog "github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+	"sigs.k8s.io/kind/pkg/apis/config/v1alpha4"
+	"sigs.k8s.io/kind/pkg/cluster"
+	"sigs.k8s.io/kind/pkg/cluster/constants"
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
+	"sigs.k8s.io/kind/pkg/exec"
+	"sigs.k8s.io/yaml"
+)
+
+// joinConfigTemplate is the kubeadm JoinConfiguration KIND generates for worker nodes.
+// The KubeletConfiguration and KubeProxyConfiguration are downloaded from the cluster when joining.
+// ref: https://github.com/kubernetes-sigs/kind/blob/v0.11.1/pkg/cluster/internal/kubeadm/config.go#L289
+var joinConfigTemplate = template.Must(template.New("join").Parse(`# config generated by pulumi-resource-kind
+apiVersion: kubeadm.k8s.io/v1beta2
+kind: JoinConfiguration
+metadata:
+  name: config
+nodeRegistration:
+  criSocket: "unix:///run/containerd/containerd.sock"
+  kubeletExtraArgs:
+    fail-swap-on: "false"
+    node-ip: "{{ .NodeAddress }}"
+    provider-id: "kind://{{ .NodeProvider }}/{{ .ClusterName }}/{{ .NodeName }}"
+    node-labels: "{{ .NodeLabels }}"
+discovery:
+  bootstrapToken:
+    apiServerEndpoint: "{{ .ControlPlaneEndpoint }}"
+    token: "{{ .Token }}"
+    unsafeSkipCAVerification: true
+`))
+
+type joinConfigData struct {
+	NodeAddress          string
+	NodeProvider         string
+	ClusterName          string
+	NodeName             string
+	NodeLabels           string
+	ControlPlaneEndpoint string
+	Token                string
+}
+
+// splitWorkers splits the nodes KIND creates for the config into the workers and the config without them
+func splitWorkers(config *v1alpha4.Cluster) (*v1alpha4.Cluster, []v1alpha4.Node) {
+	rest := *config
+	rest.Nodes = nil
+	var workers []v1alpha4.Node
+	for _, node := range desiredNodes(config) {
+		if node.Role == v1alpha4.WorkerRole {
+			workers = append(workers, node)
+		} else {
+			rest.Nodes = append(rest.Nodes, node)
+		}
+	}
+	return &rest, workers
+}
+
+// canScaleWorkers checks if the configs only differ by workers added to or removed from the end of the
+// worker list, which can be done without recreating the cluster. KIND names workers by their position
+// so any other change to the existing workers would rename them.
+func canScaleWorkers(olds, news *v1alpha4.Cluster) bool {
+	oldRest, oldWorkers := splitWorkers(olds)
+	newRest, newWorkers := splitWorkers(news)
+	if !reflect.DeepEqual(oldRest, newRest) || len(oldWorkers) == len(newWorkers) {
+		return false
+	}
+	for i := range oldWorkers {
+		if i < len(newWorkers) && !reflect.DeepEqual(oldWorkers[i], newWorkers[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// yamlDocumentSeparatorRE splits YAML document streams like KIND does for kubeadm patches
+var yamlDocumentSeparatorRE = regexp.MustCompile(`(?m)^---.*$`)
+
+// patchJoinConfig applies the kubeadm patches of the cluster and then those of the worker to the JoinConfiguration,
+// matching them on kind and apiVersion like KIND. The patches of the other kubeadm kinds were applied when creating
+// the cluster and the joining worker downloads their result from it.
+// ref: https://github.com/kubernetes-sigs/kind/blob/v0.11.1/pkg/cluster/internal/patch/resource.go#L63
+func patchJoinConfig(joinConfig string, config *v1alpha4.Cluster, worker v1alpha4.Node) (string, error) {
+	const kind, apiVersion = "JoinConfiguration", "kubeadm.k8s.io/v1beta2"
+	matches := func(patchKind, patchAPIVersion string) bool {
+		return patchKind == kind && (patchAPIVersion == "" || patchAPIVersion == apiVersion)
+	}
+
+	doc, err := yaml.YAMLToJSON([]byte(joinConfig))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse kubeadm config")
+	}
+	apply := func(patches []string, patches6902 []v1alpha4.PatchJSON6902) error {
+		for _, raw := range patches {
+			for _, document := range yamlDocumentSeparatorRE.Split(raw, -1) {
+				var typeMeta struct {
+					Kind       string `json:"kind"`
+					APIVersion string `json:"apiVersion"`
+				}
+				if err := yaml.Unmarshal([]byte(document), &typeMeta); err != nil {
+					return errors.Wrap(err, "failed to parse kubeadm patch")
+				}
+				if !matches(typeMeta.Kind, typeMeta.APIVersion) {
+					continue
+				}
+				patch, err := yaml.YAMLToJSON([]byte(document))
+				if err != nil {
+					return errors.Wrap(err, "failed to parse kubeadm patch")
+				}
+				if doc, err = jsonpatch.MergePatch(doc, patch); err != nil {
+					return errors.Wrap(err, "failed to apply kubeadm patch")
+				}
+			}
+		}
+		for _, p := range patches6902 {
+			patchAPIVersion := p.Version
+			if p.Group != "" {
+				patchAPIVersion = p.Group + "/" + p.Version
+			}
+			if !matches(p.Kind, patchAPIVersion) {
+				continue
+			}
+			data, err := yaml.YAMLToJSON([]byte(p.Patch))
+			if err != nil {
+				return errors.Wrap(err, "failed to parse kubeadm JSON 6902 patch")
+			}
+			patch, err := jsonpatch.DecodePatch(data)
+			if err != nil {
+				return errors.Wrap(err, "failed to parse kubeadm JSON 6902 patch")
+			}
+			if doc, err = patch.Apply(doc); err != nil {
+				return errors.Wrap(err, "failed to apply kubeadm JSON 6902 patch")
+			}
+		}
+		return nil
+	}
+	if err = apply(config.KubeadmConfigPatches, config.KubeadmConfigPatchesJSON6902); err != nil {
+		return "", err
+	}
+	if err = apply(worker.KubeadmConfigPatches, worker.KubeadmConfigPatchesJSON6902); err != nil {
+		return "", err
+	}
+	patched, err := yaml.JSONToYAML(doc)
+	if err != nil {
+		return "", err
+	}
+	return string(patched), nil
+}
+
+// kubernetesMinorVersionRE matches the minor version of the Kubernetes version of a node
+var kubernetesMinorVersionRE = regexp.MustCompile(`^v?1\.(\d+)\.`)
+
+// drainEmptyDirFlag returns the kubectl drain flag deleting the emptyDir data of the pods,
+// which was renamed in kubectl 1.20 and older node images still ship with the old name only
+func drainEmptyDirFlag(kubernetesVersion string) string {
+	if match := kubernetesMinorVersionRE.FindStringSubmatch(kubernetesVersion); match != nil {
+		if minor, _ := strconv.Atoi(match[1]); minor < 20 {
+			return "--delete-local-data"
+		}
+	}
+	return "--delete-emptydir-data"
+}
+
+// workerName returns the name KIND gives to the worker at the position in the worker list
+// ref: https://github.com/kubernetes-sigs/kind/blob/v0.11.1/pkg/cluster/internal/providers/common/namer.go#L25
+func workerName(clusterName string, index int) string {
+	if index == 0 {
+		return fmt.Sprintf("%s-%s", clusterName, constants.WorkerNodeRoleValue)
+	}
+	return fmt.Sprintf("%s-%s%d", clusterName, constants.WorkerNodeRoleValue, index+1)
+}
+
+// scaleWorkers adds the new workers to the end of the worker list on the network of the cluster
+// and drains and deletes the removed ones
+func (k *kindProvider) scaleWorkers(provider *cluster.Provider, clusterName, network string, olds, news *v1alpha4.Cluster) error {
+	// the container runtime pulls missing images when creating the workers
+	if err := k.verifyOfflineImages(news); err != nil {
+		return err
+	}
+
+	_, oldWorkers := splitWorkers(olds)
+	_, newWorkers := splitWorkers(news)
+
+	allNodes, err := provider.ListNodes(clusterName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to list nodes of KIND cluster %s", clusterName)
+	}
+	controlPlane, err := nodeutils.BootstrapControlPlaneNode(allNodes)
+	if err != nil {
+		return err
+	}
+
+	for i := len(oldWorkers); i < len(newWorkers); i++ {
+		if err = k.addWorker(provider, clusterName, network, controlPlane, news, newWorkers[i], workerName(clusterName, i)); err != nil {
+			return errors.Wrapf(err, "failed to add worker %s", workerName(clusterName, i))
+		}
+	}
+	// the last workers are removed first, same as the order they are named in
+	for i := len(oldWorkers) - 1; i >= len(newWorkers); i-- {
+		if err = k.removeWorker(controlPlane, workerName(clusterName, i)); err != nil {
+			return errors.Wrapf(err, "failed to remove worker %s", workerName(clusterName, i))
+		}
+	}
+	return nil
+}
+
+// addWorker creates a worker node container the same way KIND does and joins it to the cluster
+func (k *kindProvider) addWorker(provider *cluster.Provider, clusterName, network string, controlPlane nodes.Node,
+	config *v1alpha4.Cluster, worker v1alpha4.Node, name string) error {
+	runtime := container.NewRuntime(k.opts.Provider)
+
+	// the cluster wide settings like the proxy are taken from the existing nodes
+	spec, err := runtime.NodeSettings(controlPlane.String(), network)
+	if err != nil {
+		return err
+	}
+	spec.Name = name
+	spec.Role = constants.WorkerNodeRoleValue
+	spec.Image = k.nodeImage(worker)
+	for _, mount := range worker.ExtraMounts {
+		spec.Volumes = append(spec.Volumes, mountBinding(mount))
+	}
+	for _, mapping := range worker.ExtraPortMappings {
+		spec.Ports = append(spec.Ports, portBinding(config.Networking.IPFamily, mapping))
+	}
+	pulumilog.V(3).Infof("creating worker node %s of KIND cluster %s", name, clusterName)
+	if err = runtime.RunNode(spec); err != nil {
+		return err
+	}
+
+	node, err := findNode(provider, clusterName, name)
+	if err != nil {
+		return err
+	}
+
+	// the containerd config patches were applied to the config of every node
+	containerdConfig, err := exec.OutputLines(controlPlane.Command("cat", "/etc/containerd/config.toml"))
+	if err != nil {
+		return errors.Wrap(err, "failed to read containerd config")
+	}
+	if err = nodeutils.WriteFile(node, "/etc/containerd/config.toml", strings.Join(containerdConfig, "\n")+"\n"); err != nil {
+		return errors.Wrap(err, "failed to write containerd config")
+	}
+	if err = node.Command("systemctl", "restart", "containerd").Run(); err != nil {
+		return errors.Wrap(err, "failed to restart containerd")
+	}
+	if err = copyProxyDropIn(controlPlane, node); err != nil {
+		return err
+	}
+
+	joinConfig, err := k.joinConfig(provider, clusterName, network, controlPlane, config, worker, node)
+	if err != nil {
+		return err
+	}
+	if err = nodeutils.WriteFile(node, "/kind/kubeadm.conf", joinConfig); err != nil {
+		return errors.Wrap(err, "failed to write kubeadm config")
+	}
+	// same as KIND
+	// ref: https://github.com/kubernetes-sigs/kind/blob/v0.11.1/pkg/cluster/internal/create/actions/kubeadmjoin/join.go#L119
+	lines, err := exec.CombinedOutputLines(node.Command("kubeadm", "join", "--config", "/kind/kubeadm.conf", "--skip-phases=preflight", "--v=6"))
+	pulumilog.V(9).Infof(strings.Join(lines, "\n"))
+	if err != nil {
+		return errors.Wrap(err, "failed to join node with kubeadm")
+	}
+
+	if k.opts.WaitForNodeReady > 0 {
+		if err = kubectl(controlPlane, "wait", "--for=condition=Ready", "node/"+name, "--timeout="+k.opts.WaitForNodeReady.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// joinConfig renders the kubeadm JoinConfiguration for the worker with a fresh bootstrap token
+func (k *kindProvider) joinConfig(provider *cluster.Provider, clusterName, network string, controlPlane nodes.Node,
+	config *v1alpha4.Cluster, worker v1alpha4.Node, node nodes.Node) (string, error) {
+	// the internal kubeconfig points at the load balancer when there is one
+	internal, err := provider.KubeConfig(clusterName, true)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get internal kubeconfig of KIND cluster %s", clusterName)
+	}
+	server, _, err := kubeconfig.CurrentCluster(internal)
+	if err != nil {
+		return "", err
+	}
+	endpoint, err := url.Parse(server)
+	if err != nil {
+		return "", errors.Wrapf(err, "unexpected API server endpoint %s", server)
+	}
+
+	// the token KIND created the cluster with expires after a day
+	token, err := exec.OutputLines(controlPlane.Command("kubeadm", "token", "create", "--ttl", "15m"))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create bootstrap token")
+	}
+	if len(token) == 0 {
+		return "", errors.New("failed to create bootstrap token: no token printed")
+	}
+
+	// KIND fails to get the addresses of nodes attached to more than one network, like those of peered clusters
+	ipv4, ipv6, err := container.NewRuntime(k.opts.Provider).NetworkAddresses(node.String(), network)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get IP of node %s", node.String())
+	}
+	address := ipv4
+	if config.Networking.IPFamily == v1alpha4.IPv6Family {
+		address = ipv6
+	}
+	if address == "" {
+		return "", errors.Errorf("node %s has no address on network %s", node.String(), network)
+	}
+
+	var labels []string
+	for key, value := range worker.Labels {
+		labels = append(labels, fmt.Sprintf("%s=%s", key, value))
+	}
+	sort.Strings(labels)
+
+	var buf bytes.Buffer
+	err = joinConfigTemplate.Execute(&buf, joinConfigData{
+		NodeAddress:          address,
+		NodeProvider:         k.opts.Provider,
+		ClusterName:          clusterName,
+		NodeName:             node.String(),
+		NodeLabels:           strings.Join(labels, ","),
+		ControlPlaneEndpoint: endpoint.Host,
+		Token:                strings.TrimSpace(token[len(token)-1]),
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "failed to render kubeadm config")
+	}
+	return patchJoinConfig(buf.String(), config, worker)
+}
+
+// removeWorker drains the worker, removes it from the cluster and deletes its container
+func (k *kindProvider) removeWorker(controlPlane nodes.Node, name string) error {
+	pulumilog.V(3).Infof("removing worker node %s", name)
+	version, err := nodeutils.KubeVersion(controlPlane)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get Kubernetes version of %s", controlPlane.String())
+	}
+	if err = kubectl(controlPlane, "drain", name, "--ignore-daemonsets", drainEmptyDirFlag(version), "--force"); err != nil {
+		return err
+	}
+	if err = kubectl(controlPlane, "delete", "node", name); err != nil {
+		return err
+	}
+	return container.NewRuntime(k.opts.Provider).RemoveNode(name)
+}
+
+// kubectl runs kubectl on the control plane node with the admin kubeconfig
+func kubectl(controlPlane nodes.Node, args ...string) error {
+	args = append([]string{"--kubeconfig=/etc/kubernetes/admin.conf"}, args...)
+	lines, err := exec.CombinedOutputLines(controlPlane.Command("kubectl", args...))
+	if err != nil {
+		return errors.Wrapf(err, "failed to run kubectl %s: %s", strings.Join(args[1:], " "), strings.Join(lines, "\n"))
+	}
+	return nil
+}
+
+// findNode returns the node of the cluster with the name
+func findNode(provider *cluster.Provider, clusterName, name string) (nodes.Node, error) {
+	allNodes, err := provider.ListNodes(clusterName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list nodes of KIND cluster %s", clusterName)
+	}
+	for _, node := range allNodes {
+		if node.String() == name {
+			return node, nil
+		}
+	}
+	return nil, errors.Errorf("node %s not found in KIND cluster %s", name, clusterName)
+}
+
+// mountBinding converts the mount to a --volume value the same way KIND does
+// ref: https://github.com/kubernetes-sigs/kind/blob/v0.11.1/pkg/cluster/internal/providers/docker/provision.go#L334
+func mountBinding(m v1alpha4.Mount) string {
+	bind := fmt.Sprintf("%s:%s", m.HostPath, m.ContainerPath)
+	var attrs []string
+	if m.Readonly {
+		attrs = append(attrs, "ro")
+	}
+	if m.SelinuxRelabel {
+		attrs = append(attrs, "Z")
+	}
+	switch m.Propagation {
+	case v1alpha4.MountPropagationBidirectional:
+		attrs = append(attrs, "rshared")
+	case v1alpha4.MountPropagationHostToContainer:
+		attrs = append(attrs, "rslave")
+	}
+	if len(attrs) > 0 {
+		bind = fmt.Sprintf("%s:%s", bind, strings.Join(attrs, ","))
+	}
+	return bind
+}
+
+// portBinding converts the port mapping to a --publish value the same way KIND does,
+// except that host port 0 is left to the container runtime to allocate
+// ref: https://github.com/kubernetes-sigs/kind/blob/v0.11.1/pkg/cluster/internal/providers/docker/provision.go#L366
+func portBinding(ipFamily v1alpha4.ClusterIPFamily, pm v1alpha4.PortMapping) string {
+	listenAddress := pm.ListenAddress
+	if listenAddress == "" {
+		listenAddress = "0.0.0.0"
+		if ipFamily == v1alpha4.IPv6Family {
+			listenAddress = "::"
+		}
+	}
+	protocol := string(pm.Protocol)
+	if protocol == "" {
+		protocol = string(v1alpha4.PortMappingProtocolTCP)
+	}
+	hostPort := ""
+	if pm.HostPort > 0 {
+		hostPort = strconv.Itoa(int(pm.HostPort))
+	}
+	return fmt.Sprintf("%s:%d/%s", net.JoinHostPort(listenAddress, hostPort), pm.ContainerPort, strings.ToLower(protocol))
+}
@@ -0,0 +1,99 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/apis/config/v1alpha4"
+)
+
+func TestCanScaleWorkers(t *testing.T) {
+	controlPlane := v1alpha4.Node{Role: v1alpha4.ControlPlaneRole}
+	worker := v1alpha4.Node{Role: v1alpha4.WorkerRole}
+	labelled := v1alpha4.Node{Role: v1alpha4.WorkerRole, Labels: map[string]string{"tier": "db"}}
+	patched := v1alpha4.Node{Role: v1alpha4.WorkerRole, KubeadmConfigPatches: []string{"kind: JoinConfiguration"}}
+
+	tests := []struct {
+		name     string
+		olds     []v1alpha4.Node
+		news     []v1alpha4.Node
+		expected bool
+	}{
+		{"add worker to default config", nil, []v1alpha4.Node{controlPlane, worker}, true},
+		{"add worker", []v1alpha4.Node{controlPlane, worker}, []v1alpha4.Node{controlPlane, worker, labelled}, true},
+		{"remove last worker", []v1alpha4.Node{controlPlane, worker, labelled}, []v1alpha4.Node{controlPlane, worker}, true},
+		{"remove first worker", []v1alpha4.Node{controlPlane, worker, labelled}, []v1alpha4.Node{controlPlane, labelled}, false},
+		{"change worker", []v1alpha4.Node{controlPlane, worker}, []v1alpha4.Node{controlPlane, labelled}, false},
+		{"add patched worker", []v1alpha4.Node{controlPlane, worker}, []v1alpha4.Node{controlPlane, worker, patched}, true},
+		{"add control plane", []v1alpha4.Node{controlPlane}, []v1alpha4.Node{controlPlane, controlPlane}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			olds := &v1alpha4.Cluster{Name: "dev", Nodes: tt.olds}
+			news := &v1alpha4.Cluster{Name: "dev", Nodes: tt.news}
+			if actual := canScaleWorkers(olds, news); actual != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestWorkerName(t *testing.T) {
+	if name := workerName("dev", 0); name != "dev-worker" {
+		t.Errorf("expected dev-worker, got %s", name)
+	}
+	if name := workerName("dev", 2); name != "dev-worker3" {
+		t.Errorf("expected dev-worker3, got %s", name)
+	}
+}
+
+func TestPatchJoinConfig(t *testing.T) {
+	config := &v1alpha4.Cluster{
+		KubeadmConfigPatches: []string{
+			"kind: ClusterConfiguration\napiServer:\n  extraArgs:\n    v: \"4\"\n",
+			"kind: JoinConfiguration\nnodeRegistration:\n  kubeletExtraArgs:\n    v: \"2\"\n---\nkind: JoinConfiguration\napiVersion: kubeadm.k8s.io/v1beta1\nfoo: bar\n",
+		},
+		KubeadmConfigPatchesJSON6902: []v1alpha4.PatchJSON6902{{
+			Group:   "kubeadm.k8s.io",
+			Version: "v1beta2",
+			Kind:    "JoinConfiguration",
+			Patch:   `[{"op": "add", "path": "/nodeRegistration/kubeletExtraArgs/max-pods", "value": "50"}]`,
+		}},
+	}
+	worker := v1alpha4.Node{
+		Role:                 v1alpha4.WorkerRole,
+		KubeadmConfigPatches: []string{"kind: JoinConfiguration\nnodeRegistration:\n  kubeletExtraArgs:\n    v: \"6\"\n"},
+	}
+	joinConfig := "apiVersion: kubeadm.k8s.io/v1beta2\nkind: JoinConfiguration\nnodeRegistration:\n  kubeletExtraArgs:\n    fail-swap-on: \"false\"\n"
+	patched, err := patchJoinConfig(joinConfig, config, worker)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `apiVersion: kubeadm.k8s.io/v1beta2
+kind: JoinConfiguration
+nodeRegistration:
+  kubeletExtraArgs:
+    fail-swap-on: "false"
+    max-pods: "50"
+    v: "6"
+`
+	if patched != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, patched)
+	}
+	if strings.Contains(patched, "foo") {
+		t.Errorf("expected the patch of another apiVersion to be skipped, got\n%s", patched)
+	}
+}
+
+func TestDrainEmptyDirFlag(t *testing.T) {
+	for version, expected := range map[string]string{
+		"v1.19.11": "--delete-local-data",
+		"v1.20.7":  "--delete-emptydir-data",
+		"v1.21.1":  "--delete-emptydir-data",
+		"unknown":  "--delete-emptydir-data",
+	} {
+		if flag := drainEmptyDirFlag(version); flag != expected {
+			t.Errorf("expected %s for %s, got %s", expected, version, flag)
+		}
+	}
+}
@@ -17,10 +17,26 @@ import (
 type Cluster struct {
 	pulumi.CustomResourceState
 
+	// API server endpoint reachable from the host
+	ApiServerEndpoint pulumi.StringPtrOutput `pulumi:"apiServerEndpoint"`
+	// host port of the API server, resolved when networking.apiServerPort is 0
+	ApiServerPort pulumi.IntPtrOutput `pulumi:"apiServerPort"`
+	// PEM encoded certificate authority of the cluster
+	CaCertificate pulumi.StringPtrOutput `pulumi:"caCertificate"`
+	// API server endpoint reachable from containers on the cluster network
+	InternalApiServerEndpoint pulumi.StringPtrOutput `pulumi:"internalApiServerEndpoint"`
 	// kubeconfig content
 	Kubeconfig pulumi.StringOutput `pulumi:"kubeconfig"`
 	// cluster name
 	Name pulumi.StringOutput `pulumi:"name"`
+	// name of the docker/podman network the nodes joined
+	NetworkName pulumi.StringPtrOutput `pulumi:"networkName"`
+	// subnets of the docker/podman network the nodes joined
+	NetworkSubnets pulumi.StringArrayOutput `pulumi:"networkSubnets"`
+	// live information of the node containers of the cluster
+	NodeDetails NodeDetailArrayOutput `pulumi:"nodeDetails"`
+	// live state of the node containers, stopped if any of them is not running
+	State pulumi.StringOutput `pulumi:"state"`
 }
 
 // NewCluster registers a new resource with the given unique name, arguments, and options.
@@ -62,32 +78,88 @@ func (ClusterState) ElementType() reflect.Type {
 }
 
 type clusterArgs struct {
-	ApiVersion                      *string                       `pulumi:"apiVersion"`
-	ContainerdConfigPatches         []string                      `pulumi:"containerdConfigPatches"`
-	ContainerdConfigPatchesJSON6902 []string                      `pulumi:"containerdConfigPatchesJSON6902"`
-	FeatureGates                    map[string]string             `pulumi:"featureGates"`
-	Kind                            *string                       `pulumi:"kind"`
-	KubeadmConfigPatches            []string                      `pulumi:"kubeadmConfigPatches"`
-	KubeadmConfigPatchesJSON6902    []patchjson6902.PatchJSON6902 `pulumi:"kubeadmConfigPatchesJSON6902"`
-	Name                            *string                       `pulumi:"name"`
-	Networking                      *networking.Networking        `pulumi:"networking"`
-	Nodes                           []node.Node                   `pulumi:"nodes"`
-	RuntimeConfig                   map[string]string             `pulumi:"runtimeConfig"`
+	// Take over a pre-existing KIND cluster with the same name if its nodes match the config instead of failing. Default: false
+	AdoptExisting *bool `pulumi:"adoptExisting"`
+	// Configuration of the API servers rendered into kubeadm patches after kubeadmConfigPatches, with the files it reads mounted into the control plane nodes. Changes recreate the cluster
+	ApiServer                       *APIServer `pulumi:"apiServer"`
+	ApiVersion                      *string    `pulumi:"apiVersion"`
+	ContainerdConfigPatches         []string   `pulumi:"containerdConfigPatches"`
+	ContainerdConfigPatchesJSON6902 []string   `pulumi:"containerdConfigPatchesJSON6902"`
+	// PEM encoded CA certificates, or paths of PEM files, trusted by the nodes and containerd from the moment the nodes start. Changes to the list are installed in place, changes to the content of the files are not detected
+	ExtraCACertificates []string          `pulumi:"extraCACertificates"`
+	FeatureGates        map[string]string `pulumi:"featureGates"`
+	// host names resolving to IP addresses in the nodes and in the cluster, written to /etc/hosts of the nodes and to the CoreDNS config. Changes are applied in place
+	HostAliases                  []HostAlias                   `pulumi:"hostAliases"`
+	Kind                         *string                       `pulumi:"kind"`
+	KubeadmConfigPatches         []string                      `pulumi:"kubeadmConfigPatches"`
+	KubeadmConfigPatchesJSON6902 []patchjson6902.PatchJSON6902 `pulumi:"kubeadmConfigPatchesJSON6902"`
+	// Name of the context, cluster and user entries of the cluster in the kubeconfig. Default: kind-<name>
+	KubeconfigContext *string `pulumi:"kubeconfigContext"`
+	// Where to export the kubeconfig of the cluster: none keeps it only in the kubeconfig output, file writes it to kubeconfigPath and merge merges it into kubeconfigPath without switching the current context. Default: KIND's behaviour of merging into the default kubeconfig and switching the current context
+	KubeconfigExport *string `pulumi:"kubeconfigExport"`
+	// Kubeconfig file used by the file and merge kubeconfig exports. Required for file. Default for merge: the default kubeconfig
+	KubeconfigPath *string `pulumi:"kubeconfigPath"`
+	// Kubernetes version like 1.21 or v1.21.1 of the nodes without an image or kubernetesVersion of their own, resolved to the node image pinned by digest for the KIND release of the provider
+	KubernetesVersion *string `pulumi:"kubernetesVersion"`
+	Name              *string `pulumi:"name"`
+	// docker/podman network of the node containers instead of the kind network or KIND_EXPERIMENTAL_DOCKER_NETWORK, created when missing and removed with the last cluster using it if the provider created it. Changes recreate the cluster
+	Network    *NetworkSettings       `pulumi:"network"`
+	Networking *networking.Networking `pulumi:"networking"`
+	Nodes      []node.Node            `pulumi:"nodes"`
+	// Local images or paths of local image archives imported into every node right after the cluster is created, and into new nodes
+	PreloadImages []string `pulumi:"preloadImages"`
+	// Proxy settings of the node containers and containerd instead of the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables of the provider. NO_PROXY is extended with the pod and service subnets, the node network and the node names. Changes are applied to containerd in place, the node containers keep the settings they were created with
+	Proxy *ProxySettings `pulumi:"proxy"`
+	// Mirrors of registries by registry host like docker.io, rendered into the containerd config of every node after containerdConfigPatches. Changes recreate the cluster
+	RegistryMirrors map[string]RegistryMirror `pulumi:"registryMirrors"`
+	RuntimeConfig   map[string]string         `pulumi:"runtimeConfig"`
+	// Desired state of the node containers: running or stopped. Stopped clusters keep their nodes and can be started again. Default: running
+	State *string `pulumi:"state"`
+	// How node image changes are rolled out: replace recreates the cluster, inPlace upgrades the control plane and then the workers with kubeadm when all nodes move to the same version at most one minor version newer and recreates the cluster otherwise. Default: replace
+	UpgradeStrategy *string `pulumi:"upgradeStrategy"`
 }
 
 // The set of arguments for constructing a Cluster resource.
 type ClusterArgs struct {
+	// Take over a pre-existing KIND cluster with the same name if its nodes match the config instead of failing. Default: false
+	AdoptExisting pulumi.BoolPtrInput
+	// Configuration of the API servers rendered into kubeadm patches after kubeadmConfigPatches, with the files it reads mounted into the control plane nodes. Changes recreate the cluster
+	ApiServer                       APIServerPtrInput
 	ApiVersion                      pulumi.StringPtrInput
 	ContainerdConfigPatches         pulumi.StringArrayInput
 	ContainerdConfigPatchesJSON6902 pulumi.StringArrayInput
-	FeatureGates                    pulumi.StringMapInput
-	Kind                            pulumi.StringPtrInput
-	KubeadmConfigPatches            pulumi.StringArrayInput
-	KubeadmConfigPatchesJSON6902    patchjson6902.PatchJSON6902ArrayInput
-	Name                            pulumi.StringPtrInput
-	Networking                      networking.NetworkingPtrInput
-	Nodes                           node.NodeArrayInput
-	RuntimeConfig                   pulumi.StringMapInput
+	// PEM encoded CA certificates, or paths of PEM files, trusted by the nodes and containerd from the moment the nodes start. Changes to the list are installed in place, changes to the content of the files are not detected
+	ExtraCACertificates pulumi.StringArrayInput
+	FeatureGates        pulumi.StringMapInput
+	// host names resolving to IP addresses in the nodes and in the cluster, written to /etc/hosts of the nodes and to the CoreDNS config. Changes are applied in place
+	HostAliases                  HostAliasArrayInput
+	Kind                         pulumi.StringPtrInput
+	KubeadmConfigPatches         pulumi.StringArrayInput
+	KubeadmConfigPatchesJSON6902 patchjson6902.PatchJSON6902ArrayInput
+	// Name of the context, cluster and user entries of the cluster in the kubeconfig. Default: kind-<name>
+	KubeconfigContext pulumi.StringPtrInput
+	// Where to export the kubeconfig of the cluster: none keeps it only in the kubeconfig output, file writes it to kubeconfigPath and merge merges it into kubeconfigPath without switching the current context. Default: KIND's behaviour of merging into the default kubeconfig and switching the current context
+	KubeconfigExport pulumi.StringPtrInput
+	// Kubeconfig file used by the file and merge kubeconfig exports. Required for file. Default for merge: the default kubeconfig
+	KubeconfigPath pulumi.StringPtrInput
+	// Kubernetes version like 1.21 or v1.21.1 of the nodes without an image or kubernetesVersion of their own, resolved to the node image pinned by digest for the KIND release of the provider
+	KubernetesVersion pulumi.StringPtrInput
+	Name              pulumi.StringPtrInput
+	// docker/podman network of the node containers instead of the kind network or KIND_EXPERIMENTAL_DOCKER_NETWORK, created when missing and removed with the last cluster using it if the provider created it. Changes recreate the cluster
+	Network    NetworkSettingsPtrInput
+	Networking networking.NetworkingPtrInput
+	Nodes      node.NodeArrayInput
+	// Local images or paths of local image archives imported into every node right after the cluster is created, and into new nodes
+	PreloadImages pulumi.StringArrayInput
+	// Proxy settings of the node containers and containerd instead of the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables of the provider. NO_PROXY is extended with the pod and service subnets, the node network and the node names. Changes are applied to containerd in place, the node containers keep the settings they were created with
+	Proxy ProxySettingsPtrInput
+	// Mirrors of registries by registry host like docker.io, rendered into the containerd config of every node after containerdConfigPatches. Changes recreate the cluster
+	RegistryMirrors RegistryMirrorMapInput
+	RuntimeConfig   pulumi.StringMapInput
+	// Desired state of the node containers: running or stopped. Stopped clusters keep their nodes and can be started again. Default: running
+	State pulumi.StringPtrInput
+	// How node image changes are rolled out: replace recreates the cluster, inPlace upgrades the control plane and then the workers with kubeadm when all nodes move to the same version at most one minor version newer and recreates the cluster otherwise. Default: replace
+	UpgradeStrategy pulumi.StringPtrInput
 }
 
 func (ClusterArgs) ElementType() reflect.Type {
@@ -0,0 +1,526 @@
+// *** WARNING: this file was generated by pulumigen. ***
+// *** Do not edit by hand unless you're certain you know what you are doing! ***
+
+package cluster
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+type ClusterStateEnum string
+
+const (
+	// all node containers are running
+	ClusterStateEnumRunning = ClusterStateEnum("running")
+	// the node containers are stopped
+	ClusterStateEnumStopped = ClusterStateEnum("stopped")
+)
+
+func (ClusterStateEnum) ElementType() reflect.Type {
+	return reflect.TypeOf((*ClusterStateEnum)(nil)).Elem()
+}
+
+func (e ClusterStateEnum) ToClusterStateEnumOutput() ClusterStateEnumOutput {
+	return pulumi.ToOutput(e).(ClusterStateEnumOutput)
+}
+
+func (e ClusterStateEnum) ToClusterStateEnumOutputWithContext(ctx context.Context) ClusterStateEnumOutput {
+	return pulumi.ToOutputWithContext(ctx, e).(ClusterStateEnumOutput)
+}
+
+func (e ClusterStateEnum) ToClusterStateEnumPtrOutput() ClusterStateEnumPtrOutput {
+	return e.ToClusterStateEnumPtrOutputWithContext(context.Background())
+}
+
+func (e ClusterStateEnum) ToClusterStateEnumPtrOutputWithContext(ctx context.Context) ClusterStateEnumPtrOutput {
+	return ClusterStateEnum(e).ToClusterStateEnumOutputWithContext(ctx).ToClusterStateEnumPtrOutputWithContext(ctx)
+}
+
+func (e ClusterStateEnum) ToStringOutput() pulumi.StringOutput {
+	return pulumi.ToOutput(pulumi.String(e)).(pulumi.StringOutput)
+}
+
+func (e ClusterStateEnum) ToStringOutputWithContext(ctx context.Context) pulumi.StringOutput {
+	return pulumi.ToOutputWithContext(ctx, pulumi.String(e)).(pulumi.StringOutput)
+}
+
+func (e ClusterStateEnum) ToStringPtrOutput() pulumi.StringPtrOutput {
+	return pulumi.String(e).ToStringPtrOutputWithContext(context.Background())
+}
+
+func (e ClusterStateEnum) ToStringPtrOutputWithContext(ctx context.Context) pulumi.StringPtrOutput {
+	return pulumi.String(e).ToStringOutputWithContext(ctx).ToStringPtrOutputWithContext(ctx)
+}
+
+type ClusterStateEnumOutput struct{ *pulumi.OutputState }
+
+func (ClusterStateEnumOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*ClusterStateEnum)(nil)).Elem()
+}
+
+func (o ClusterStateEnumOutput) ToClusterStateEnumOutput() ClusterStateEnumOutput {
+	return o
+}
+
+func (o ClusterStateEnumOutput) ToClusterStateEnumOutputWithContext(ctx context.Context) ClusterStateEnumOutput {
+	return o
+}
+
+func (o ClusterStateEnumOutput) ToClusterStateEnumPtrOutput() ClusterStateEnumPtrOutput {
+	return o.ToClusterStateEnumPtrOutputWithContext(context.Background())
+}
+
+func (o ClusterStateEnumOutput) ToClusterStateEnumPtrOutputWithContext(ctx context.Context) ClusterStateEnumPtrOutput {
+	return o.ApplyTWithContext(ctx, func(_ context.Context, v ClusterStateEnum) *ClusterStateEnum {
+		return &v
+	}).(ClusterStateEnumPtrOutput)
+}
+
+func (o ClusterStateEnumOutput) ToStringOutput() pulumi.StringOutput {
+	return o.ToStringOutputWithContext(context.Background())
+}
+
+func (o ClusterStateEnumOutput) ToStringOutputWithContext(ctx context.Context) pulumi.StringOutput {
+	return o.ApplyTWithContext(ctx, func(_ context.Context, e ClusterStateEnum) string {
+		return string(e)
+	}).(pulumi.StringOutput)
+}
+
+func (o ClusterStateEnumOutput) ToStringPtrOutput() pulumi.StringPtrOutput {
+	return o.ToStringPtrOutputWithContext(context.Background())
+}
+
+func (o ClusterStateEnumOutput) ToStringPtrOutputWithContext(ctx context.Context) pulumi.StringPtrOutput {
+	return o.ApplyTWithContext(ctx, func(_ context.Context, e ClusterStateEnum) *string {
+		v := string(e)
+		return &v
+	}).(pulumi.StringPtrOutput)
+}
+
+type ClusterStateEnumPtrOutput struct{ *pulumi.OutputState }
+
+func (ClusterStateEnumPtrOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((**ClusterStateEnum)(nil)).Elem()
+}
+
+func (o ClusterStateEnumPtrOutput) ToClusterStateEnumPtrOutput() ClusterStateEnumPtrOutput {
+	return o
+}
+
+func (o ClusterStateEnumPtrOutput) ToClusterStateEnumPtrOutputWithContext(ctx context.Context) ClusterStateEnumPtrOutput {
+	return o
+}
+
+func (o ClusterStateEnumPtrOutput) Elem() ClusterStateEnumOutput {
+	return o.ApplyT(func(v *ClusterStateEnum) ClusterStateEnum {
+		if v != nil {
+			return *v
+		}
+		var ret ClusterStateEnum
+		return ret
+	}).(ClusterStateEnumOutput)
+}
+
+func (o ClusterStateEnumPtrOutput) ToStringPtrOutput() pulumi.StringPtrOutput {
+	return o.ToStringPtrOutputWithContext(context.Background())
+}
+
+func (o ClusterStateEnumPtrOutput) ToStringPtrOutputWithContext(ctx context.Context) pulumi.StringPtrOutput {
+	return o.ApplyTWithContext(ctx, func(_ context.Context, e *ClusterStateEnum) *string {
+		if e == nil {
+			return nil
+		}
+		v := string(*e)
+		return &v
+	}).(pulumi.StringPtrOutput)
+}
+
+// ClusterStateEnumInput is an input type that accepts ClusterStateEnumArgs and ClusterStateEnumOutput values.
+// You can construct a concrete instance of `ClusterStateEnumInput` via:
+//
+//          ClusterStateEnumArgs{...}
+type ClusterStateEnumInput interface {
+	pulumi.Input
+
+	ToClusterStateEnumOutput() ClusterStateEnumOutput
+	ToClusterStateEnumOutputWithContext(context.Context) ClusterStateEnumOutput
+}
+
+var clusterStateEnumPtrType = reflect.TypeOf((**ClusterStateEnum)(nil)).Elem()
+
+type ClusterStateEnumPtrInput interface {
+	pulumi.Input
+
+	ToClusterStateEnumPtrOutput() ClusterStateEnumPtrOutput
+	ToClusterStateEnumPtrOutputWithContext(context.Context) ClusterStateEnumPtrOutput
+}
+
+type clusterStateEnumPtr string
+
+func ClusterStateEnumPtr(v string) ClusterStateEnumPtrInput {
+	return (*clusterStateEnumPtr)(&v)
+}
+
+func (*clusterStateEnumPtr) ElementType() reflect.Type {
+	return clusterStateEnumPtrType
+}
+
+func (in *clusterStateEnumPtr) ToClusterStateEnumPtrOutput() ClusterStateEnumPtrOutput {
+	return pulumi.ToOutput(in).(ClusterStateEnumPtrOutput)
+}
+
+func (in *clusterStateEnumPtr) ToClusterStateEnumPtrOutputWithContext(ctx context.Context) ClusterStateEnumPtrOutput {
+	return pulumi.ToOutputWithContext(ctx, in).(ClusterStateEnumPtrOutput)
+}
+
+type KubeconfigExport string
+
+const (
+	// only keep the kubeconfig in the resource outputs
+	KubeconfigExportNone = KubeconfigExport("none")
+	// write the kubeconfig to a dedicated file
+	KubeconfigExportFile = KubeconfigExport("file")
+	// merge the kubeconfig into a file without switching the current context
+	KubeconfigExportMerge = KubeconfigExport("merge")
+)
+
+func (KubeconfigExport) ElementType() reflect.Type {
+	return reflect.TypeOf((*KubeconfigExport)(nil)).Elem()
+}
+
+func (e KubeconfigExport) ToKubeconfigExportOutput() KubeconfigExportOutput {
+	return pulumi.ToOutput(e).(KubeconfigExportOutput)
+}
+
+func (e KubeconfigExport) ToKubeconfigExportOutputWithContext(ctx context.Context) KubeconfigExportOutput {
+	return pulumi.ToOutputWithContext(ctx, e).(KubeconfigExportOutput)
+}
+
+func (e KubeconfigExport) ToKubeconfigExportPtrOutput() KubeconfigExportPtrOutput {
+	return e.ToKubeconfigExportPtrOutputWithContext(context.Background())
+}
+
+func (e KubeconfigExport) ToKubeconfigExportPtrOutputWithContext(ctx context.Context) KubeconfigExportPtrOutput {
+	return KubeconfigExport(e).ToKubeconfigExportOutputWithContext(ctx).ToKubeconfigExportPtrOutputWithContext(ctx)
+}
+
+func (e KubeconfigExport) ToStringOutput() pulumi.StringOutput {
+	return pulumi.ToOutput(pulumi.String(e)).(pulumi.StringOutput)
+}
+
+func (e KubeconfigExport) ToStringOutputWithContext(ctx context.Context) pulumi.StringOutput {
+	return pulumi.ToOutputWithContext(ctx, pulumi.String(e)).(pulumi.StringOutput)
+}
+
+func (e KubeconfigExport) ToStringPtrOutput() pulumi.StringPtrOutput {
+	return pulumi.String(e).ToStringPtrOutputWithContext(context.Background())
+}
+
+func (e KubeconfigExport) ToStringPtrOutputWithContext(ctx context.Context) pulumi.StringPtrOutput {
+	return pulumi.String(e).ToStringOutputWithContext(ctx).ToStringPtrOutputWithContext(ctx)
+}
+
+type KubeconfigExportOutput struct{ *pulumi.OutputState }
+
+func (KubeconfigExportOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*KubeconfigExport)(nil)).Elem()
+}
+
+func (o KubeconfigExportOutput) ToKubeconfigExportOutput() KubeconfigExportOutput {
+	return o
+}
+
+func (o KubeconfigExportOutput) ToKubeconfigExportOutputWithContext(ctx context.Context) KubeconfigExportOutput {
+	return o
+}
+
+func (o KubeconfigExportOutput) ToKubeconfigExportPtrOutput() KubeconfigExportPtrOutput {
+	return o.ToKubeconfigExportPtrOutputWithContext(context.Background())
+}
+
+func (o KubeconfigExportOutput) ToKubeconfigExportPtrOutputWithContext(ctx context.Context) KubeconfigExportPtrOutput {
+	return o.ApplyTWithContext(ctx, func(_ context.Context, v KubeconfigExport) *KubeconfigExport {
+		return &v
+	}).(KubeconfigExportPtrOutput)
+}
+
+func (o KubeconfigExportOutput) ToStringOutput() pulumi.StringOutput {
+	return o.ToStringOutputWithContext(context.Background())
+}
+
+func (o KubeconfigExportOutput) ToStringOutputWithContext(ctx context.Context) pulumi.StringOutput {
+	return o.ApplyTWithContext(ctx, func(_ context.Context, e KubeconfigExport) string {
+		return string(e)
+	}).(pulumi.StringOutput)
+}
+
+func (o KubeconfigExportOutput) ToStringPtrOutput() pulumi.StringPtrOutput {
+	return o.ToStringPtrOutputWithContext(context.Background())
+}
+
+func (o KubeconfigExportOutput) ToStringPtrOutputWithContext(ctx context.Context) pulumi.StringPtrOutput {
+	return o.ApplyTWithContext(ctx, func(_ context.Context, e KubeconfigExport) *string {
+		v := string(e)
+		return &v
+	}).(pulumi.StringPtrOutput)
+}
+
+type KubeconfigExportPtrOutput struct{ *pulumi.OutputState }
+
+func (KubeconfigExportPtrOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((**KubeconfigExport)(nil)).Elem()
+}
+
+func (o KubeconfigExportPtrOutput) ToKubeconfigExportPtrOutput() KubeconfigExportPtrOutput {
+	return o
+}
+
+func (o KubeconfigExportPtrOutput) ToKubeconfigExportPtrOutputWithContext(ctx context.Context) KubeconfigExportPtrOutput {
+	return o
+}
+
+func (o KubeconfigExportPtrOutput) Elem() KubeconfigExportOutput {
+	return o.ApplyT(func(v *KubeconfigExport) KubeconfigExport {
+		if v != nil {
+			return *v
+		}
+		var ret KubeconfigExport
+		return ret
+	}).(KubeconfigExportOutput)
+}
+
+func (o KubeconfigExportPtrOutput) ToStringPtrOutput() pulumi.StringPtrOutput {
+	return o.ToStringPtrOutputWithContext(context.Background())
+}
+
+func (o KubeconfigExportPtrOutput) ToStringPtrOutputWithContext(ctx context.Context) pulumi.StringPtrOutput {
+	return o.ApplyTWithContext(ctx, func(_ context.Context, e *KubeconfigExport) *string {
+		if e == nil {
+			return nil
+		}
+		v := string(*e)
+		return &v
+	}).(pulumi.StringPtrOutput)
+}
+
+// KubeconfigExportInput is an input type that accepts KubeconfigExportArgs and KubeconfigExportOutput values.
+// You can construct a concrete instance of `KubeconfigExportInput` via:
+//
+//          KubeconfigExportArgs{...}
+type KubeconfigExportInput interface {
+	pulumi.Input
+
+	ToKubeconfigExportOutput() KubeconfigExportOutput
+	ToKubeconfigExportOutputWithContext(context.Context) KubeconfigExportOutput
+}
+
+var kubeconfigExportPtrType = reflect.TypeOf((**KubeconfigExport)(nil)).Elem()
+
+type KubeconfigExportPtrInput interface {
+	pulumi.Input
+
+	ToKubeconfigExportPtrOutput() KubeconfigExportPtrOutput
+	ToKubeconfigExportPtrOutputWithContext(context.Context) KubeconfigExportPtrOutput
+}
+
+type kubeconfigExportPtr string
+
+func KubeconfigExportPtr(v string) KubeconfigExportPtrInput {
+	return (*kubeconfigExportPtr)(&v)
+}
+
+func (*kubeconfigExportPtr) ElementType() reflect.Type {
+	return kubeconfigExportPtrType
+}
+
+func (in *kubeconfigExportPtr) ToKubeconfigExportPtrOutput() KubeconfigExportPtrOutput {
+	return pulumi.ToOutput(in).(KubeconfigExportPtrOutput)
+}
+
+func (in *kubeconfigExportPtr) ToKubeconfigExportPtrOutputWithContext(ctx context.Context) KubeconfigExportPtrOutput {
+	return pulumi.ToOutputWithContext(ctx, in).(KubeconfigExportPtrOutput)
+}
+
+type UpgradeStrategy string
+
+const (
+	// recreate the cluster with the new node images
+	UpgradeStrategyReplace = UpgradeStrategy("replace")
+	// upgrade the nodes with kubeadm
+	UpgradeStrategyInPlace = UpgradeStrategy("inPlace")
+)
+
+func (UpgradeStrategy) ElementType() reflect.Type {
+	return reflect.TypeOf((*UpgradeStrategy)(nil)).Elem()
+}
+
+func (e UpgradeStrategy) ToUpgradeStrategyOutput() UpgradeStrategyOutput {
+	return pulumi.ToOutput(e).(UpgradeStrategyOutput)
+}
+
+func (e UpgradeStrategy) ToUpgradeStrategyOutputWithContext(ctx context.Context) UpgradeStrategyOutput {
+	return pulumi.ToOutputWithContext(ctx, e).(UpgradeStrategyOutput)
+}
+
+func (e UpgradeStrategy) ToUpgradeStrategyPtrOutput() UpgradeStrategyPtrOutput {
+	return e.ToUpgradeStrategyPtrOutputWithContext(context.Background())
+}
+
+func (e UpgradeStrategy) ToUpgradeStrategyPtrOutputWithContext(ctx context.Context) UpgradeStrategyPtrOutput {
+	return UpgradeStrategy(e).ToUpgradeStrategyOutputWithContext(ctx).ToUpgradeStrategyPtrOutputWithContext(ctx)
+}
+
+func (e UpgradeStrategy) ToStringOutput() pulumi.StringOutput {
+	return pulumi.ToOutput(pulumi.String(e)).(pulumi.StringOutput)
+}
+
+func (e UpgradeStrategy) ToStringOutputWithContext(ctx context.Context) pulumi.StringOutput {
+	return pulumi.ToOutputWithContext(ctx, pulumi.String(e)).(pulumi.StringOutput)
+}
+
+func (e UpgradeStrategy) ToStringPtrOutput() pulumi.StringPtrOutput {
+	return pulumi.String(e).ToStringPtrOutputWithContext(context.Background())
+}
+
+func (e UpgradeStrategy) ToStringPtrOutputWithContext(ctx context.Context) pulumi.StringPtrOutput {
+	return pulumi.String(e).ToStringOutputWithContext(ctx).ToStringPtrOutputWithContext(ctx)
+}
+
+type UpgradeStrategyOutput struct{ *pulumi.OutputState }
+
+func (UpgradeStrategyOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*UpgradeStrategy)(nil)).Elem()
+}
+
+func (o UpgradeStrategyOutput) ToUpgradeStrategyOutput() UpgradeStrategyOutput {
+	return o
+}
+
+func (o UpgradeStrategyOutput) ToUpgradeStrategyOutputWithContext(ctx context.Context) UpgradeStrategyOutput {
+	return o
+}
+
+func (o UpgradeStrategyOutput) ToUpgradeStrategyPtrOutput() UpgradeStrategyPtrOutput {
+	return o.ToUpgradeStrategyPtrOutputWithContext(context.Background())
+}
+
+func (o UpgradeStrategyOutput) ToUpgradeStrategyPtrOutputWithContext(ctx context.Context) UpgradeStrategyPtrOutput {
+	return o.ApplyTWithContext(ctx, func(_ context.Context, v UpgradeStrategy) *UpgradeStrategy {
+		return &v
+	}).(UpgradeStrategyPtrOutput)
+}
+
+func (o UpgradeStrategyOutput) ToStringOutput() pulumi.StringOutput {
+	return o.ToStringOutputWithContext(context.Background())
+}
+
+func (o UpgradeStrategyOutput) ToStringOutputWithContext(ctx context.Context) pulumi.StringOutput {
+	return o.ApplyTWithContext(ctx, func(_ context.Context, e UpgradeStrategy) string {
+		return string(e)
+	}).(pulumi.StringOutput)
+}
+
+func (o UpgradeStrategyOutput) ToStringPtrOutput() pulumi.StringPtrOutput {
+	return o.ToStringPtrOutputWithContext(context.Background())
+}
+
+func (o UpgradeStrategyOutput) ToStringPtrOutputWithContext(ctx context.Context) pulumi.StringPtrOutput {
+	return o.ApplyTWithContext(ctx, func(_ context.Context, e UpgradeStrategy) *string {
+		v := string(e)
+		return &v
+	}).(pulumi.StringPtrOutput)
+}
+
+type UpgradeStrategyPtrOutput struct{ *pulumi.OutputState }
+
+func (UpgradeStrategyPtrOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((**UpgradeStrategy)(nil)).Elem()
+}
+
+func (o UpgradeStrategyPtrOutput) ToUpgradeStrategyPtrOutput() UpgradeStrategyPtrOutput {
+	return o
+}
+
+func (o UpgradeStrategyPtrOutput) ToUpgradeStrategyPtrOutputWithContext(ctx context.Context) UpgradeStrategyPtrOutput {
+	return o
+}
+
+func (o UpgradeStrategyPtrOutput) Elem() UpgradeStrategyOutput {
+	return o.ApplyT(func(v *UpgradeStrategy) UpgradeStrategy {
+		if v != nil {
+			return *v
+		}
+		var ret UpgradeStrategy
+		return ret
+	}).(UpgradeStrategyOutput)
+}
+
+func (o UpgradeStrategyPtrOutput) ToStringPtrOutput() pulumi.StringPtrOutput {
+	return o.ToStringPtrOutputWithContext(context.Background())
+}
+
+func (o UpgradeStrategyPtrOutput) ToStringPtrOutputWithContext(ctx context.Context) pulumi.StringPtrOutput {
+	return o.ApplyTWithContext(ctx, func(_ context.Context, e *UpgradeStrategy) *string {
+		if e == nil {
+			return nil
+		}
+		v := string(*e)
+		return &v
+	}).(pulumi.StringPtrOutput)
+}
+
+// UpgradeStrategyInput is an input type that accepts UpgradeStrategyArgs and UpgradeStrategyOutput values.
+// You can construct a concrete instance of `UpgradeStrategyInput` via:
+//
+//          UpgradeStrategyArgs{...}
+type UpgradeStrategyInput interface {
+	pulumi.Input
+
+	ToUpgradeStrategyOutput() UpgradeStrategyOutput
+	ToUpgradeStrategyOutputWithContext(context.Context) UpgradeStrategyOutput
+}
+
+var upgradeStrategyPtrType = reflect.TypeOf((**UpgradeStrategy)(nil)).Elem()
+
+type UpgradeStrategyPtrInput interface {
+	pulumi.Input
+
+	ToUpgradeStrategyPtrOutput() UpgradeStrategyPtrOutput
+	ToUpgradeStrategyPtrOutputWithContext(context.Context) UpgradeStrategyPtrOutput
+}
+
+type upgradeStrategyPtr string
+
+func UpgradeStrategyPtr(v string) UpgradeStrategyPtrInput {
+	return (*upgradeStrategyPtr)(&v)
+}
+
+func (*upgradeStrategyPtr) ElementType() reflect.Type {
+	return upgradeStrategyPtrType
+}
+
+func (in *upgradeStrategyPtr) ToUpgradeStrategyPtrOutput() UpgradeStrategyPtrOutput {
+	return pulumi.ToOutput(in).(UpgradeStrategyPtrOutput)
+}
+
+func (in *upgradeStrategyPtr) ToUpgradeStrategyPtrOutputWithContext(ctx context.Context) UpgradeStrategyPtrOutput {
+	return pulumi.ToOutputWithContext(ctx, in).(UpgradeStrategyPtrOutput)
+}
+
+func init() {
+	pulumi.RegisterInputType(reflect.TypeOf((*ClusterStateEnumInput)(nil)).Elem(), ClusterStateEnum("running"))
+	pulumi.RegisterInputType(reflect.TypeOf((*ClusterStateEnumPtrInput)(nil)).Elem(), ClusterStateEnum("running"))
+	pulumi.RegisterInputType(reflect.TypeOf((*KubeconfigExportInput)(nil)).Elem(), KubeconfigExport("none"))
+	pulumi.RegisterInputType(reflect.TypeOf((*KubeconfigExportPtrInput)(nil)).Elem(), KubeconfigExport("none"))
+	pulumi.RegisterInputType(reflect.TypeOf((*UpgradeStrategyInput)(nil)).Elem(), UpgradeStrategy("replace"))
+	pulumi.RegisterInputType(reflect.TypeOf((*UpgradeStrategyPtrInput)(nil)).Elem(), UpgradeStrategy("replace"))
+	pulumi.RegisterOutputType(ClusterStateEnumOutput{})
+	pulumi.RegisterOutputType(ClusterStateEnumPtrOutput{})
+	pulumi.RegisterOutputType(KubeconfigExportOutput{})
+	pulumi.RegisterOutputType(KubeconfigExportPtrOutput{})
+	pulumi.RegisterOutputType(UpgradeStrategyOutput{})
+	pulumi.RegisterOutputType(UpgradeStrategyPtrOutput{})
+}
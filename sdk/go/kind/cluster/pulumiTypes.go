@@ -0,0 +1,1628 @@
+// *** WARNING: this file was generated by pulumigen. ***
+// *** Do not edit by hand unless you're certain you know what you are doing! ***
+
+package cluster
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// configuration of the API servers of the control plane nodes
+type APIServer struct {
+	// audit policy YAML, enables audit logging to /var/log/kubernetes/audit/audit.log in the control plane nodes
+	AuditPolicy *string `pulumi:"auditPolicy"`
+	// extra IP addresses and DNS names of the API server serving certificate
+	CertSANs []string `pulumi:"certSANs"`
+	// EncryptionConfiguration YAML for the encryption of resources at rest
+	EncryptionConfig *string `pulumi:"encryptionConfig"`
+	// API server flags without the leading dashes, overriding the flags rendered from the other settings. feature-gates and runtime-config are set with the featureGates and runtimeConfig inputs
+	ExtraArgs map[string]string `pulumi:"extraArgs"`
+	// OpenID Connect authentication
+	Oidc *OIDC `pulumi:"oidc"`
+}
+
+// APIServerInput is an input type that accepts APIServerArgs and APIServerOutput values.
+// You can construct a concrete instance of `APIServerInput` via:
+//
+//          APIServerArgs{...}
+type APIServerInput interface {
+	pulumi.Input
+
+	ToAPIServerOutput() APIServerOutput
+	ToAPIServerOutputWithContext(context.Context) APIServerOutput
+}
+
+// configuration of the API servers of the control plane nodes
+type APIServerArgs struct {
+	// audit policy YAML, enables audit logging to /var/log/kubernetes/audit/audit.log in the control plane nodes
+	AuditPolicy pulumi.StringPtrInput `pulumi:"auditPolicy"`
+	// extra IP addresses and DNS names of the API server serving certificate
+	CertSANs pulumi.StringArrayInput `pulumi:"certSANs"`
+	// EncryptionConfiguration YAML for the encryption of resources at rest
+	EncryptionConfig pulumi.StringPtrInput `pulumi:"encryptionConfig"`
+	// API server flags without the leading dashes, overriding the flags rendered from the other settings. feature-gates and runtime-config are set with the featureGates and runtimeConfig inputs
+	ExtraArgs pulumi.StringMapInput `pulumi:"extraArgs"`
+	// OpenID Connect authentication
+	Oidc OIDCPtrInput `pulumi:"oidc"`
+}
+
+func (APIServerArgs) ElementType() reflect.Type {
+	return reflect.TypeOf((*APIServer)(nil)).Elem()
+}
+
+func (i APIServerArgs) ToAPIServerOutput() APIServerOutput {
+	return i.ToAPIServerOutputWithContext(context.Background())
+}
+
+func (i APIServerArgs) ToAPIServerOutputWithContext(ctx context.Context) APIServerOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(APIServerOutput)
+}
+
+func (i APIServerArgs) ToAPIServerPtrOutput() APIServerPtrOutput {
+	return i.ToAPIServerPtrOutputWithContext(context.Background())
+}
+
+func (i APIServerArgs) ToAPIServerPtrOutputWithContext(ctx context.Context) APIServerPtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(APIServerOutput).ToAPIServerPtrOutputWithContext(ctx)
+}
+
+// APIServerPtrInput is an input type that accepts APIServerArgs, APIServerPtr and APIServerPtrOutput values.
+// You can construct a concrete instance of `APIServerPtrInput` via:
+//
+//          APIServerArgs{...}
+//
+//  or:
+//
+//          nil
+type APIServerPtrInput interface {
+	pulumi.Input
+
+	ToAPIServerPtrOutput() APIServerPtrOutput
+	ToAPIServerPtrOutputWithContext(context.Context) APIServerPtrOutput
+}
+
+type apiserverPtrType APIServerArgs
+
+func APIServerPtr(v *APIServerArgs) APIServerPtrInput {
+	return (*apiserverPtrType)(v)
+}
+
+func (*apiserverPtrType) ElementType() reflect.Type {
+	return reflect.TypeOf((**APIServer)(nil)).Elem()
+}
+
+func (i *apiserverPtrType) ToAPIServerPtrOutput() APIServerPtrOutput {
+	return i.ToAPIServerPtrOutputWithContext(context.Background())
+}
+
+func (i *apiserverPtrType) ToAPIServerPtrOutputWithContext(ctx context.Context) APIServerPtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(APIServerPtrOutput)
+}
+
+// configuration of the API servers of the control plane nodes
+type APIServerOutput struct{ *pulumi.OutputState }
+
+func (APIServerOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*APIServer)(nil)).Elem()
+}
+
+func (o APIServerOutput) ToAPIServerOutput() APIServerOutput {
+	return o
+}
+
+func (o APIServerOutput) ToAPIServerOutputWithContext(ctx context.Context) APIServerOutput {
+	return o
+}
+
+func (o APIServerOutput) ToAPIServerPtrOutput() APIServerPtrOutput {
+	return o.ToAPIServerPtrOutputWithContext(context.Background())
+}
+
+func (o APIServerOutput) ToAPIServerPtrOutputWithContext(ctx context.Context) APIServerPtrOutput {
+	return o.ApplyTWithContext(ctx, func(_ context.Context, v APIServer) *APIServer {
+		return &v
+	}).(APIServerPtrOutput)
+}
+
+// audit policy YAML, enables audit logging to /var/log/kubernetes/audit/audit.log in the control plane nodes
+func (o APIServerOutput) AuditPolicy() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v APIServer) *string { return v.AuditPolicy }).(pulumi.StringPtrOutput)
+}
+
+// extra IP addresses and DNS names of the API server serving certificate
+func (o APIServerOutput) CertSANs() pulumi.StringArrayOutput {
+	return o.ApplyT(func(v APIServer) []string { return v.CertSANs }).(pulumi.StringArrayOutput)
+}
+
+// EncryptionConfiguration YAML for the encryption of resources at rest
+func (o APIServerOutput) EncryptionConfig() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v APIServer) *string { return v.EncryptionConfig }).(pulumi.StringPtrOutput)
+}
+
+// API server flags without the leading dashes, overriding the flags rendered from the other settings. feature-gates and runtime-config are set with the featureGates and runtimeConfig inputs
+func (o APIServerOutput) ExtraArgs() pulumi.StringMapOutput {
+	return o.ApplyT(func(v APIServer) map[string]string { return v.ExtraArgs }).(pulumi.StringMapOutput)
+}
+
+// OpenID Connect authentication
+func (o APIServerOutput) Oidc() OIDCPtrOutput {
+	return o.ApplyT(func(v APIServer) *OIDC { return v.Oidc }).(OIDCPtrOutput)
+}
+
+type APIServerPtrOutput struct{ *pulumi.OutputState }
+
+func (APIServerPtrOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((**APIServer)(nil)).Elem()
+}
+
+func (o APIServerPtrOutput) ToAPIServerPtrOutput() APIServerPtrOutput {
+	return o
+}
+
+func (o APIServerPtrOutput) ToAPIServerPtrOutputWithContext(ctx context.Context) APIServerPtrOutput {
+	return o
+}
+
+func (o APIServerPtrOutput) Elem() APIServerOutput {
+	return o.ApplyT(func(v *APIServer) APIServer {
+		if v != nil {
+			return *v
+		}
+		var ret APIServer
+		return ret
+	}).(APIServerOutput)
+}
+
+// audit policy YAML, enables audit logging to /var/log/kubernetes/audit/audit.log in the control plane nodes
+func (o APIServerPtrOutput) AuditPolicy() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *APIServer) *string {
+		if v == nil {
+			return nil
+		}
+		return v.AuditPolicy
+	}).(pulumi.StringPtrOutput)
+}
+
+// extra IP addresses and DNS names of the API server serving certificate
+func (o APIServerPtrOutput) CertSANs() pulumi.StringArrayOutput {
+	return o.ApplyT(func(v *APIServer) []string {
+		if v == nil {
+			return nil
+		}
+		return v.CertSANs
+	}).(pulumi.StringArrayOutput)
+}
+
+// EncryptionConfiguration YAML for the encryption of resources at rest
+func (o APIServerPtrOutput) EncryptionConfig() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *APIServer) *string {
+		if v == nil {
+			return nil
+		}
+		return v.EncryptionConfig
+	}).(pulumi.StringPtrOutput)
+}
+
+// API server flags without the leading dashes, overriding the flags rendered from the other settings. feature-gates and runtime-config are set with the featureGates and runtimeConfig inputs
+func (o APIServerPtrOutput) ExtraArgs() pulumi.StringMapOutput {
+	return o.ApplyT(func(v *APIServer) map[string]string {
+		if v == nil {
+			return nil
+		}
+		return v.ExtraArgs
+	}).(pulumi.StringMapOutput)
+}
+
+// OpenID Connect authentication
+func (o APIServerPtrOutput) Oidc() OIDCPtrOutput {
+	return o.ApplyT(func(v *APIServer) *OIDC {
+		if v == nil {
+			return nil
+		}
+		return v.Oidc
+	}).(OIDCPtrOutput)
+}
+
+// host names resolving to an IP address
+type HostAlias struct {
+	// host names resolving to the IP address
+	Hostnames []string `pulumi:"hostnames"`
+	// IP address the host names resolve to
+	Ip string `pulumi:"ip"`
+}
+
+// HostAliasInput is an input type that accepts HostAliasArgs and HostAliasOutput values.
+// You can construct a concrete instance of `HostAliasInput` via:
+//
+//          HostAliasArgs{...}
+type HostAliasInput interface {
+	pulumi.Input
+
+	ToHostAliasOutput() HostAliasOutput
+	ToHostAliasOutputWithContext(context.Context) HostAliasOutput
+}
+
+// host names resolving to an IP address
+type HostAliasArgs struct {
+	// host names resolving to the IP address
+	Hostnames pulumi.StringArrayInput `pulumi:"hostnames"`
+	// IP address the host names resolve to
+	Ip pulumi.StringInput `pulumi:"ip"`
+}
+
+func (HostAliasArgs) ElementType() reflect.Type {
+	return reflect.TypeOf((*HostAlias)(nil)).Elem()
+}
+
+func (i HostAliasArgs) ToHostAliasOutput() HostAliasOutput {
+	return i.ToHostAliasOutputWithContext(context.Background())
+}
+
+func (i HostAliasArgs) ToHostAliasOutputWithContext(ctx context.Context) HostAliasOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(HostAliasOutput)
+}
+
+// HostAliasArrayInput is an input type that accepts HostAliasArray and HostAliasArrayOutput values.
+// You can construct a concrete instance of `HostAliasArrayInput` via:
+//
+//          HostAliasArray{ HostAliasArgs{...} }
+type HostAliasArrayInput interface {
+	pulumi.Input
+
+	ToHostAliasArrayOutput() HostAliasArrayOutput
+	ToHostAliasArrayOutputWithContext(context.Context) HostAliasArrayOutput
+}
+
+type HostAliasArray []HostAliasInput
+
+func (HostAliasArray) ElementType() reflect.Type {
+	return reflect.TypeOf((*[]HostAlias)(nil)).Elem()
+}
+
+func (i HostAliasArray) ToHostAliasArrayOutput() HostAliasArrayOutput {
+	return i.ToHostAliasArrayOutputWithContext(context.Background())
+}
+
+func (i HostAliasArray) ToHostAliasArrayOutputWithContext(ctx context.Context) HostAliasArrayOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(HostAliasArrayOutput)
+}
+
+// host names resolving to an IP address
+type HostAliasOutput struct{ *pulumi.OutputState }
+
+func (HostAliasOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*HostAlias)(nil)).Elem()
+}
+
+func (o HostAliasOutput) ToHostAliasOutput() HostAliasOutput {
+	return o
+}
+
+func (o HostAliasOutput) ToHostAliasOutputWithContext(ctx context.Context) HostAliasOutput {
+	return o
+}
+
+// host names resolving to the IP address
+func (o HostAliasOutput) Hostnames() pulumi.StringArrayOutput {
+	return o.ApplyT(func(v HostAlias) []string { return v.Hostnames }).(pulumi.StringArrayOutput)
+}
+
+// IP address the host names resolve to
+func (o HostAliasOutput) Ip() pulumi.StringOutput {
+	return o.ApplyT(func(v HostAlias) string { return v.Ip }).(pulumi.StringOutput)
+}
+
+type HostAliasArrayOutput struct{ *pulumi.OutputState }
+
+func (HostAliasArrayOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*[]HostAlias)(nil)).Elem()
+}
+
+func (o HostAliasArrayOutput) ToHostAliasArrayOutput() HostAliasArrayOutput {
+	return o
+}
+
+func (o HostAliasArrayOutput) ToHostAliasArrayOutputWithContext(ctx context.Context) HostAliasArrayOutput {
+	return o
+}
+
+func (o HostAliasArrayOutput) Index(i pulumi.IntInput) HostAliasOutput {
+	return pulumi.All(o, i).ApplyT(func(vs []interface{}) HostAlias {
+		return vs[0].([]HostAlias)[vs[1].(int)]
+	}).(HostAliasOutput)
+}
+
+// container network of the node containers
+type NetworkSettings struct {
+	// IPv6 subnet of the network when it is created, required for ipv6 and dual stack clusters
+	Ipv6Subnet *string `pulumi:"ipv6Subnet"`
+	// MTU of the network when it is created
+	Mtu *int `pulumi:"mtu"`
+	// network name
+	Name string `pulumi:"name"`
+	// IPv4 subnet of the network when it is created, picked by the container runtime otherwise
+	Subnet *string `pulumi:"subnet"`
+}
+
+// NetworkSettingsInput is an input type that accepts NetworkSettingsArgs and NetworkSettingsOutput values.
+// You can construct a concrete instance of `NetworkSettingsInput` via:
+//
+//          NetworkSettingsArgs{...}
+type NetworkSettingsInput interface {
+	pulumi.Input
+
+	ToNetworkSettingsOutput() NetworkSettingsOutput
+	ToNetworkSettingsOutputWithContext(context.Context) NetworkSettingsOutput
+}
+
+// container network of the node containers
+type NetworkSettingsArgs struct {
+	// IPv6 subnet of the network when it is created, required for ipv6 and dual stack clusters
+	Ipv6Subnet pulumi.StringPtrInput `pulumi:"ipv6Subnet"`
+	// MTU of the network when it is created
+	Mtu pulumi.IntPtrInput `pulumi:"mtu"`
+	// network name
+	Name pulumi.StringInput `pulumi:"name"`
+	// IPv4 subnet of the network when it is created, picked by the container runtime otherwise
+	Subnet pulumi.StringPtrInput `pulumi:"subnet"`
+}
+
+func (NetworkSettingsArgs) ElementType() reflect.Type {
+	return reflect.TypeOf((*NetworkSettings)(nil)).Elem()
+}
+
+func (i NetworkSettingsArgs) ToNetworkSettingsOutput() NetworkSettingsOutput {
+	return i.ToNetworkSettingsOutputWithContext(context.Background())
+}
+
+func (i NetworkSettingsArgs) ToNetworkSettingsOutputWithContext(ctx context.Context) NetworkSettingsOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(NetworkSettingsOutput)
+}
+
+func (i NetworkSettingsArgs) ToNetworkSettingsPtrOutput() NetworkSettingsPtrOutput {
+	return i.ToNetworkSettingsPtrOutputWithContext(context.Background())
+}
+
+func (i NetworkSettingsArgs) ToNetworkSettingsPtrOutputWithContext(ctx context.Context) NetworkSettingsPtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(NetworkSettingsOutput).ToNetworkSettingsPtrOutputWithContext(ctx)
+}
+
+// NetworkSettingsPtrInput is an input type that accepts NetworkSettingsArgs, NetworkSettingsPtr and NetworkSettingsPtrOutput values.
+// You can construct a concrete instance of `NetworkSettingsPtrInput` via:
+//
+//          NetworkSettingsArgs{...}
+//
+//  or:
+//
+//          nil
+type NetworkSettingsPtrInput interface {
+	pulumi.Input
+
+	ToNetworkSettingsPtrOutput() NetworkSettingsPtrOutput
+	ToNetworkSettingsPtrOutputWithContext(context.Context) NetworkSettingsPtrOutput
+}
+
+type networkSettingsPtrType NetworkSettingsArgs
+
+func NetworkSettingsPtr(v *NetworkSettingsArgs) NetworkSettingsPtrInput {
+	return (*networkSettingsPtrType)(v)
+}
+
+func (*networkSettingsPtrType) ElementType() reflect.Type {
+	return reflect.TypeOf((**NetworkSettings)(nil)).Elem()
+}
+
+func (i *networkSettingsPtrType) ToNetworkSettingsPtrOutput() NetworkSettingsPtrOutput {
+	return i.ToNetworkSettingsPtrOutputWithContext(context.Background())
+}
+
+func (i *networkSettingsPtrType) ToNetworkSettingsPtrOutputWithContext(ctx context.Context) NetworkSettingsPtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(NetworkSettingsPtrOutput)
+}
+
+// container network of the node containers
+type NetworkSettingsOutput struct{ *pulumi.OutputState }
+
+func (NetworkSettingsOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*NetworkSettings)(nil)).Elem()
+}
+
+func (o NetworkSettingsOutput) ToNetworkSettingsOutput() NetworkSettingsOutput {
+	return o
+}
+
+func (o NetworkSettingsOutput) ToNetworkSettingsOutputWithContext(ctx context.Context) NetworkSettingsOutput {
+	return o
+}
+
+func (o NetworkSettingsOutput) ToNetworkSettingsPtrOutput() NetworkSettingsPtrOutput {
+	return o.ToNetworkSettingsPtrOutputWithContext(context.Background())
+}
+
+func (o NetworkSettingsOutput) ToNetworkSettingsPtrOutputWithContext(ctx context.Context) NetworkSettingsPtrOutput {
+	return o.ApplyTWithContext(ctx, func(_ context.Context, v NetworkSettings) *NetworkSettings {
+		return &v
+	}).(NetworkSettingsPtrOutput)
+}
+
+// IPv6 subnet of the network when it is created, required for ipv6 and dual stack clusters
+func (o NetworkSettingsOutput) Ipv6Subnet() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v NetworkSettings) *string { return v.Ipv6Subnet }).(pulumi.StringPtrOutput)
+}
+
+// MTU of the network when it is created
+func (o NetworkSettingsOutput) Mtu() pulumi.IntPtrOutput {
+	return o.ApplyT(func(v NetworkSettings) *int { return v.Mtu }).(pulumi.IntPtrOutput)
+}
+
+// network name
+func (o NetworkSettingsOutput) Name() pulumi.StringOutput {
+	return o.ApplyT(func(v NetworkSettings) string { return v.Name }).(pulumi.StringOutput)
+}
+
+// IPv4 subnet of the network when it is created, picked by the container runtime otherwise
+func (o NetworkSettingsOutput) Subnet() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v NetworkSettings) *string { return v.Subnet }).(pulumi.StringPtrOutput)
+}
+
+type NetworkSettingsPtrOutput struct{ *pulumi.OutputState }
+
+func (NetworkSettingsPtrOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((**NetworkSettings)(nil)).Elem()
+}
+
+func (o NetworkSettingsPtrOutput) ToNetworkSettingsPtrOutput() NetworkSettingsPtrOutput {
+	return o
+}
+
+func (o NetworkSettingsPtrOutput) ToNetworkSettingsPtrOutputWithContext(ctx context.Context) NetworkSettingsPtrOutput {
+	return o
+}
+
+func (o NetworkSettingsPtrOutput) Elem() NetworkSettingsOutput {
+	return o.ApplyT(func(v *NetworkSettings) NetworkSettings {
+		if v != nil {
+			return *v
+		}
+		var ret NetworkSettings
+		return ret
+	}).(NetworkSettingsOutput)
+}
+
+// IPv6 subnet of the network when it is created, required for ipv6 and dual stack clusters
+func (o NetworkSettingsPtrOutput) Ipv6Subnet() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *NetworkSettings) *string {
+		if v == nil {
+			return nil
+		}
+		return v.Ipv6Subnet
+	}).(pulumi.StringPtrOutput)
+}
+
+// MTU of the network when it is created
+func (o NetworkSettingsPtrOutput) Mtu() pulumi.IntPtrOutput {
+	return o.ApplyT(func(v *NetworkSettings) *int {
+		if v == nil {
+			return nil
+		}
+		return v.Mtu
+	}).(pulumi.IntPtrOutput)
+}
+
+// network name
+func (o NetworkSettingsPtrOutput) Name() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *NetworkSettings) *string {
+		if v == nil {
+			return nil
+		}
+		return &v.Name
+	}).(pulumi.StringPtrOutput)
+}
+
+// IPv4 subnet of the network when it is created, picked by the container runtime otherwise
+func (o NetworkSettingsPtrOutput) Subnet() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *NetworkSettings) *string {
+		if v == nil {
+			return nil
+		}
+		return v.Subnet
+	}).(pulumi.StringPtrOutput)
+}
+
+// live information of a KIND node container
+type NodeDetail struct {
+	// host address and port of the published container ports keyed by <containerPort>/<protocol> like 80/tcp, with the ports allocated for a hostPort of 0
+	HostPorts map[string]string `pulumi:"hostPorts"`
+	// node image the container was created from
+	Image string `pulumi:"image"`
+	// IPv4 address of the node on the KIND network
+	Ipv4Address *string `pulumi:"ipv4Address"`
+	// IPv6 address of the node on the KIND network
+	Ipv6Address *string `pulumi:"ipv6Address"`
+	// node container name
+	Name string `pulumi:"name"`
+	// container ports published on the host with the host ports allocated by the container runtime
+	PortMappings []NodePortMapping `pulumi:"portMappings"`
+	// node role
+	Role string `pulumi:"role"`
+}
+
+// NodeDetailInput is an input type that accepts NodeDetailArgs and NodeDetailOutput values.
+// You can construct a concrete instance of `NodeDetailInput` via:
+//
+//          NodeDetailArgs{...}
+type NodeDetailInput interface {
+	pulumi.Input
+
+	ToNodeDetailOutput() NodeDetailOutput
+	ToNodeDetailOutputWithContext(context.Context) NodeDetailOutput
+}
+
+// live information of a KIND node container
+type NodeDetailArgs struct {
+	// host address and port of the published container ports keyed by <containerPort>/<protocol> like 80/tcp, with the ports allocated for a hostPort of 0
+	HostPorts pulumi.StringMapInput `pulumi:"hostPorts"`
+	// node image the container was created from
+	Image pulumi.StringInput `pulumi:"image"`
+	// IPv4 address of the node on the KIND network
+	Ipv4Address pulumi.StringPtrInput `pulumi:"ipv4Address"`
+	// IPv6 address of the node on the KIND network
+	Ipv6Address pulumi.StringPtrInput `pulumi:"ipv6Address"`
+	// node container name
+	Name pulumi.StringInput `pulumi:"name"`
+	// container ports published on the host with the host ports allocated by the container runtime
+	PortMappings NodePortMappingArrayInput `pulumi:"portMappings"`
+	// node role
+	Role pulumi.StringInput `pulumi:"role"`
+}
+
+func (NodeDetailArgs) ElementType() reflect.Type {
+	return reflect.TypeOf((*NodeDetail)(nil)).Elem()
+}
+
+func (i NodeDetailArgs) ToNodeDetailOutput() NodeDetailOutput {
+	return i.ToNodeDetailOutputWithContext(context.Background())
+}
+
+func (i NodeDetailArgs) ToNodeDetailOutputWithContext(ctx context.Context) NodeDetailOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(NodeDetailOutput)
+}
+
+// NodeDetailArrayInput is an input type that accepts NodeDetailArray and NodeDetailArrayOutput values.
+// You can construct a concrete instance of `NodeDetailArrayInput` via:
+//
+//          NodeDetailArray{ NodeDetailArgs{...} }
+type NodeDetailArrayInput interface {
+	pulumi.Input
+
+	ToNodeDetailArrayOutput() NodeDetailArrayOutput
+	ToNodeDetailArrayOutputWithContext(context.Context) NodeDetailArrayOutput
+}
+
+type NodeDetailArray []NodeDetailInput
+
+func (NodeDetailArray) ElementType() reflect.Type {
+	return reflect.TypeOf((*[]NodeDetail)(nil)).Elem()
+}
+
+func (i NodeDetailArray) ToNodeDetailArrayOutput() NodeDetailArrayOutput {
+	return i.ToNodeDetailArrayOutputWithContext(context.Background())
+}
+
+func (i NodeDetailArray) ToNodeDetailArrayOutputWithContext(ctx context.Context) NodeDetailArrayOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(NodeDetailArrayOutput)
+}
+
+// live information of a KIND node container
+type NodeDetailOutput struct{ *pulumi.OutputState }
+
+func (NodeDetailOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*NodeDetail)(nil)).Elem()
+}
+
+func (o NodeDetailOutput) ToNodeDetailOutput() NodeDetailOutput {
+	return o
+}
+
+func (o NodeDetailOutput) ToNodeDetailOutputWithContext(ctx context.Context) NodeDetailOutput {
+	return o
+}
+
+// host address and port of the published container ports keyed by <containerPort>/<protocol> like 80/tcp, with the ports allocated for a hostPort of 0
+func (o NodeDetailOutput) HostPorts() pulumi.StringMapOutput {
+	return o.ApplyT(func(v NodeDetail) map[string]string { return v.HostPorts }).(pulumi.StringMapOutput)
+}
+
+// node image the container was created from
+func (o NodeDetailOutput) Image() pulumi.StringOutput {
+	return o.ApplyT(func(v NodeDetail) string { return v.Image }).(pulumi.StringOutput)
+}
+
+// IPv4 address of the node on the KIND network
+func (o NodeDetailOutput) Ipv4Address() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v NodeDetail) *string { return v.Ipv4Address }).(pulumi.StringPtrOutput)
+}
+
+// IPv6 address of the node on the KIND network
+func (o NodeDetailOutput) Ipv6Address() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v NodeDetail) *string { return v.Ipv6Address }).(pulumi.StringPtrOutput)
+}
+
+// node container name
+func (o NodeDetailOutput) Name() pulumi.StringOutput {
+	return o.ApplyT(func(v NodeDetail) string { return v.Name }).(pulumi.StringOutput)
+}
+
+// container ports published on the host with the host ports allocated by the container runtime
+func (o NodeDetailOutput) PortMappings() NodePortMappingArrayOutput {
+	return o.ApplyT(func(v NodeDetail) []NodePortMapping { return v.PortMappings }).(NodePortMappingArrayOutput)
+}
+
+// node role
+func (o NodeDetailOutput) Role() pulumi.StringOutput {
+	return o.ApplyT(func(v NodeDetail) string { return v.Role }).(pulumi.StringOutput)
+}
+
+type NodeDetailArrayOutput struct{ *pulumi.OutputState }
+
+func (NodeDetailArrayOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*[]NodeDetail)(nil)).Elem()
+}
+
+func (o NodeDetailArrayOutput) ToNodeDetailArrayOutput() NodeDetailArrayOutput {
+	return o
+}
+
+func (o NodeDetailArrayOutput) ToNodeDetailArrayOutputWithContext(ctx context.Context) NodeDetailArrayOutput {
+	return o
+}
+
+func (o NodeDetailArrayOutput) Index(i pulumi.IntInput) NodeDetailOutput {
+	return pulumi.All(o, i).ApplyT(func(vs []interface{}) NodeDetail {
+		return vs[0].([]NodeDetail)[vs[1].(int)]
+	}).(NodeDetailOutput)
+}
+
+// container port of a KIND node published on the host
+type NodePortMapping struct {
+	// port in the node container
+	ContainerPort int `pulumi:"containerPort"`
+	// host port the container port is published on
+	HostPort int `pulumi:"hostPort"`
+	// host address the port is published on
+	ListenAddress string `pulumi:"listenAddress"`
+	// port protocol
+	Protocol string `pulumi:"protocol"`
+}
+
+// NodePortMappingInput is an input type that accepts NodePortMappingArgs and NodePortMappingOutput values.
+// You can construct a concrete instance of `NodePortMappingInput` via:
+//
+//          NodePortMappingArgs{...}
+type NodePortMappingInput interface {
+	pulumi.Input
+
+	ToNodePortMappingOutput() NodePortMappingOutput
+	ToNodePortMappingOutputWithContext(context.Context) NodePortMappingOutput
+}
+
+// container port of a KIND node published on the host
+type NodePortMappingArgs struct {
+	// port in the node container
+	ContainerPort pulumi.IntInput `pulumi:"containerPort"`
+	// host port the container port is published on
+	HostPort pulumi.IntInput `pulumi:"hostPort"`
+	// host address the port is published on
+	ListenAddress pulumi.StringInput `pulumi:"listenAddress"`
+	// port protocol
+	Protocol pulumi.StringInput `pulumi:"protocol"`
+}
+
+func (NodePortMappingArgs) ElementType() reflect.Type {
+	return reflect.TypeOf((*NodePortMapping)(nil)).Elem()
+}
+
+func (i NodePortMappingArgs) ToNodePortMappingOutput() NodePortMappingOutput {
+	return i.ToNodePortMappingOutputWithContext(context.Background())
+}
+
+func (i NodePortMappingArgs) ToNodePortMappingOutputWithContext(ctx context.Context) NodePortMappingOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(NodePortMappingOutput)
+}
+
+// NodePortMappingArrayInput is an input type that accepts NodePortMappingArray and NodePortMappingArrayOutput values.
+// You can construct a concrete instance of `NodePortMappingArrayInput` via:
+//
+//          NodePortMappingArray{ NodePortMappingArgs{...} }
+type NodePortMappingArrayInput interface {
+	pulumi.Input
+
+	ToNodePortMappingArrayOutput() NodePortMappingArrayOutput
+	ToNodePortMappingArrayOutputWithContext(context.Context) NodePortMappingArrayOutput
+}
+
+type NodePortMappingArray []NodePortMappingInput
+
+func (NodePortMappingArray) ElementType() reflect.Type {
+	return reflect.TypeOf((*[]NodePortMapping)(nil)).Elem()
+}
+
+func (i NodePortMappingArray) ToNodePortMappingArrayOutput() NodePortMappingArrayOutput {
+	return i.ToNodePortMappingArrayOutputWithContext(context.Background())
+}
+
+func (i NodePortMappingArray) ToNodePortMappingArrayOutputWithContext(ctx context.Context) NodePortMappingArrayOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(NodePortMappingArrayOutput)
+}
+
+// container port of a KIND node published on the host
+type NodePortMappingOutput struct{ *pulumi.OutputState }
+
+func (NodePortMappingOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*NodePortMapping)(nil)).Elem()
+}
+
+func (o NodePortMappingOutput) ToNodePortMappingOutput() NodePortMappingOutput {
+	return o
+}
+
+func (o NodePortMappingOutput) ToNodePortMappingOutputWithContext(ctx context.Context) NodePortMappingOutput {
+	return o
+}
+
+// port in the node container
+func (o NodePortMappingOutput) ContainerPort() pulumi.IntOutput {
+	return o.ApplyT(func(v NodePortMapping) int { return v.ContainerPort }).(pulumi.IntOutput)
+}
+
+// host port the container port is published on
+func (o NodePortMappingOutput) HostPort() pulumi.IntOutput {
+	return o.ApplyT(func(v NodePortMapping) int { return v.HostPort }).(pulumi.IntOutput)
+}
+
+// host address the port is published on
+func (o NodePortMappingOutput) ListenAddress() pulumi.StringOutput {
+	return o.ApplyT(func(v NodePortMapping) string { return v.ListenAddress }).(pulumi.StringOutput)
+}
+
+// port protocol
+func (o NodePortMappingOutput) Protocol() pulumi.StringOutput {
+	return o.ApplyT(func(v NodePortMapping) string { return v.Protocol }).(pulumi.StringOutput)
+}
+
+type NodePortMappingArrayOutput struct{ *pulumi.OutputState }
+
+func (NodePortMappingArrayOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*[]NodePortMapping)(nil)).Elem()
+}
+
+func (o NodePortMappingArrayOutput) ToNodePortMappingArrayOutput() NodePortMappingArrayOutput {
+	return o
+}
+
+func (o NodePortMappingArrayOutput) ToNodePortMappingArrayOutputWithContext(ctx context.Context) NodePortMappingArrayOutput {
+	return o
+}
+
+func (o NodePortMappingArrayOutput) Index(i pulumi.IntInput) NodePortMappingOutput {
+	return pulumi.All(o, i).ApplyT(func(vs []interface{}) NodePortMapping {
+		return vs[0].([]NodePortMapping)[vs[1].(int)]
+	}).(NodePortMappingOutput)
+}
+
+// OpenID Connect settings of the API server
+type OIDC struct {
+	// PEM encoded CA certificate of the provider, or the path of a PEM file
+	CaCertificate *string `pulumi:"caCertificate"`
+	// client ID the tokens are issued for
+	ClientId string `pulumi:"clientId"`
+	// claim used as the groups of the user
+	GroupsClaim *string `pulumi:"groupsClaim"`
+	// prefix of the groups
+	GroupsPrefix *string `pulumi:"groupsPrefix"`
+	// https URL of the provider
+	IssuerUrl string `pulumi:"issuerUrl"`
+	// claims the tokens must have with the values
+	RequiredClaims map[string]string `pulumi:"requiredClaims"`
+	// accepted signing algorithms
+	SigningAlgs []string `pulumi:"signingAlgs"`
+	// claim used as the user name
+	UsernameClaim *string `pulumi:"usernameClaim"`
+	// prefix of the user names
+	UsernamePrefix *string `pulumi:"usernamePrefix"`
+}
+
+// OIDCInput is an input type that accepts OIDCArgs and OIDCOutput values.
+// You can construct a concrete instance of `OIDCInput` via:
+//
+//          OIDCArgs{...}
+type OIDCInput interface {
+	pulumi.Input
+
+	ToOIDCOutput() OIDCOutput
+	ToOIDCOutputWithContext(context.Context) OIDCOutput
+}
+
+// OpenID Connect settings of the API server
+type OIDCArgs struct {
+	// PEM encoded CA certificate of the provider, or the path of a PEM file
+	CaCertificate pulumi.StringPtrInput `pulumi:"caCertificate"`
+	// client ID the tokens are issued for
+	ClientId pulumi.StringInput `pulumi:"clientId"`
+	// claim used as the groups of the user
+	GroupsClaim pulumi.StringPtrInput `pulumi:"groupsClaim"`
+	// prefix of the groups
+	GroupsPrefix pulumi.StringPtrInput `pulumi:"groupsPrefix"`
+	// https URL of the provider
+	IssuerUrl pulumi.StringInput `pulumi:"issuerUrl"`
+	// claims the tokens must have with the values
+	RequiredClaims pulumi.StringMapInput `pulumi:"requiredClaims"`
+	// accepted signing algorithms
+	SigningAlgs pulumi.StringArrayInput `pulumi:"signingAlgs"`
+	// claim used as the user name
+	UsernameClaim pulumi.StringPtrInput `pulumi:"usernameClaim"`
+	// prefix of the user names
+	UsernamePrefix pulumi.StringPtrInput `pulumi:"usernamePrefix"`
+}
+
+func (OIDCArgs) ElementType() reflect.Type {
+	return reflect.TypeOf((*OIDC)(nil)).Elem()
+}
+
+func (i OIDCArgs) ToOIDCOutput() OIDCOutput {
+	return i.ToOIDCOutputWithContext(context.Background())
+}
+
+func (i OIDCArgs) ToOIDCOutputWithContext(ctx context.Context) OIDCOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(OIDCOutput)
+}
+
+func (i OIDCArgs) ToOIDCPtrOutput() OIDCPtrOutput {
+	return i.ToOIDCPtrOutputWithContext(context.Background())
+}
+
+func (i OIDCArgs) ToOIDCPtrOutputWithContext(ctx context.Context) OIDCPtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(OIDCOutput).ToOIDCPtrOutputWithContext(ctx)
+}
+
+// OIDCPtrInput is an input type that accepts OIDCArgs, OIDCPtr and OIDCPtrOutput values.
+// You can construct a concrete instance of `OIDCPtrInput` via:
+//
+//          OIDCArgs{...}
+//
+//  or:
+//
+//          nil
+type OIDCPtrInput interface {
+	pulumi.Input
+
+	ToOIDCPtrOutput() OIDCPtrOutput
+	ToOIDCPtrOutputWithContext(context.Context) OIDCPtrOutput
+}
+
+type oidcPtrType OIDCArgs
+
+func OIDCPtr(v *OIDCArgs) OIDCPtrInput {
+	return (*oidcPtrType)(v)
+}
+
+func (*oidcPtrType) ElementType() reflect.Type {
+	return reflect.TypeOf((**OIDC)(nil)).Elem()
+}
+
+func (i *oidcPtrType) ToOIDCPtrOutput() OIDCPtrOutput {
+	return i.ToOIDCPtrOutputWithContext(context.Background())
+}
+
+func (i *oidcPtrType) ToOIDCPtrOutputWithContext(ctx context.Context) OIDCPtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(OIDCPtrOutput)
+}
+
+// OpenID Connect settings of the API server
+type OIDCOutput struct{ *pulumi.OutputState }
+
+func (OIDCOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*OIDC)(nil)).Elem()
+}
+
+func (o OIDCOutput) ToOIDCOutput() OIDCOutput {
+	return o
+}
+
+func (o OIDCOutput) ToOIDCOutputWithContext(ctx context.Context) OIDCOutput {
+	return o
+}
+
+func (o OIDCOutput) ToOIDCPtrOutput() OIDCPtrOutput {
+	return o.ToOIDCPtrOutputWithContext(context.Background())
+}
+
+func (o OIDCOutput) ToOIDCPtrOutputWithContext(ctx context.Context) OIDCPtrOutput {
+	return o.ApplyTWithContext(ctx, func(_ context.Context, v OIDC) *OIDC {
+		return &v
+	}).(OIDCPtrOutput)
+}
+
+// PEM encoded CA certificate of the provider, or the path of a PEM file
+func (o OIDCOutput) CaCertificate() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v OIDC) *string { return v.CaCertificate }).(pulumi.StringPtrOutput)
+}
+
+// client ID the tokens are issued for
+func (o OIDCOutput) ClientId() pulumi.StringOutput {
+	return o.ApplyT(func(v OIDC) string { return v.ClientId }).(pulumi.StringOutput)
+}
+
+// claim used as the groups of the user
+func (o OIDCOutput) GroupsClaim() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v OIDC) *string { return v.GroupsClaim }).(pulumi.StringPtrOutput)
+}
+
+// prefix of the groups
+func (o OIDCOutput) GroupsPrefix() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v OIDC) *string { return v.GroupsPrefix }).(pulumi.StringPtrOutput)
+}
+
+// https URL of the provider
+func (o OIDCOutput) IssuerUrl() pulumi.StringOutput {
+	return o.ApplyT(func(v OIDC) string { return v.IssuerUrl }).(pulumi.StringOutput)
+}
+
+// claims the tokens must have with the values
+func (o OIDCOutput) RequiredClaims() pulumi.StringMapOutput {
+	return o.ApplyT(func(v OIDC) map[string]string { return v.RequiredClaims }).(pulumi.StringMapOutput)
+}
+
+// accepted signing algorithms
+func (o OIDCOutput) SigningAlgs() pulumi.StringArrayOutput {
+	return o.ApplyT(func(v OIDC) []string { return v.SigningAlgs }).(pulumi.StringArrayOutput)
+}
+
+// claim used as the user name
+func (o OIDCOutput) UsernameClaim() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v OIDC) *string { return v.UsernameClaim }).(pulumi.StringPtrOutput)
+}
+
+// prefix of the user names
+func (o OIDCOutput) UsernamePrefix() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v OIDC) *string { return v.UsernamePrefix }).(pulumi.StringPtrOutput)
+}
+
+type OIDCPtrOutput struct{ *pulumi.OutputState }
+
+func (OIDCPtrOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((**OIDC)(nil)).Elem()
+}
+
+func (o OIDCPtrOutput) ToOIDCPtrOutput() OIDCPtrOutput {
+	return o
+}
+
+func (o OIDCPtrOutput) ToOIDCPtrOutputWithContext(ctx context.Context) OIDCPtrOutput {
+	return o
+}
+
+func (o OIDCPtrOutput) Elem() OIDCOutput {
+	return o.ApplyT(func(v *OIDC) OIDC {
+		if v != nil {
+			return *v
+		}
+		var ret OIDC
+		return ret
+	}).(OIDCOutput)
+}
+
+// PEM encoded CA certificate of the provider, or the path of a PEM file
+func (o OIDCPtrOutput) CaCertificate() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *OIDC) *string {
+		if v == nil {
+			return nil
+		}
+		return v.CaCertificate
+	}).(pulumi.StringPtrOutput)
+}
+
+// client ID the tokens are issued for
+func (o OIDCPtrOutput) ClientId() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *OIDC) *string {
+		if v == nil {
+			return nil
+		}
+		return &v.ClientId
+	}).(pulumi.StringPtrOutput)
+}
+
+// claim used as the groups of the user
+func (o OIDCPtrOutput) GroupsClaim() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *OIDC) *string {
+		if v == nil {
+			return nil
+		}
+		return v.GroupsClaim
+	}).(pulumi.StringPtrOutput)
+}
+
+// prefix of the groups
+func (o OIDCPtrOutput) GroupsPrefix() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *OIDC) *string {
+		if v == nil {
+			return nil
+		}
+		return v.GroupsPrefix
+	}).(pulumi.StringPtrOutput)
+}
+
+// https URL of the provider
+func (o OIDCPtrOutput) IssuerUrl() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *OIDC) *string {
+		if v == nil {
+			return nil
+		}
+		return &v.IssuerUrl
+	}).(pulumi.StringPtrOutput)
+}
+
+// claims the tokens must have with the values
+func (o OIDCPtrOutput) RequiredClaims() pulumi.StringMapOutput {
+	return o.ApplyT(func(v *OIDC) map[string]string {
+		if v == nil {
+			return nil
+		}
+		return v.RequiredClaims
+	}).(pulumi.StringMapOutput)
+}
+
+// accepted signing algorithms
+func (o OIDCPtrOutput) SigningAlgs() pulumi.StringArrayOutput {
+	return o.ApplyT(func(v *OIDC) []string {
+		if v == nil {
+			return nil
+		}
+		return v.SigningAlgs
+	}).(pulumi.StringArrayOutput)
+}
+
+// claim used as the user name
+func (o OIDCPtrOutput) UsernameClaim() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *OIDC) *string {
+		if v == nil {
+			return nil
+		}
+		return v.UsernameClaim
+	}).(pulumi.StringPtrOutput)
+}
+
+// prefix of the user names
+func (o OIDCPtrOutput) UsernamePrefix() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *OIDC) *string {
+		if v == nil {
+			return nil
+		}
+		return v.UsernamePrefix
+	}).(pulumi.StringPtrOutput)
+}
+
+// proxy settings of the nodes
+type ProxySettings struct {
+	// proxy URL for HTTP requests
+	HttpProxy *string `pulumi:"httpProxy"`
+	// proxy URL for HTTPS requests
+	HttpsProxy *string `pulumi:"httpsProxy"`
+	// comma separated hosts, domains and CIDRs that are not proxied
+	NoProxy *string `pulumi:"noProxy"`
+}
+
+// ProxySettingsInput is an input type that accepts ProxySettingsArgs and ProxySettingsOutput values.
+// You can construct a concrete instance of `ProxySettingsInput` via:
+//
+//          ProxySettingsArgs{...}
+type ProxySettingsInput interface {
+	pulumi.Input
+
+	ToProxySettingsOutput() ProxySettingsOutput
+	ToProxySettingsOutputWithContext(context.Context) ProxySettingsOutput
+}
+
+// proxy settings of the nodes
+type ProxySettingsArgs struct {
+	// proxy URL for HTTP requests
+	HttpProxy pulumi.StringPtrInput `pulumi:"httpProxy"`
+	// proxy URL for HTTPS requests
+	HttpsProxy pulumi.StringPtrInput `pulumi:"httpsProxy"`
+	// comma separated hosts, domains and CIDRs that are not proxied
+	NoProxy pulumi.StringPtrInput `pulumi:"noProxy"`
+}
+
+func (ProxySettingsArgs) ElementType() reflect.Type {
+	return reflect.TypeOf((*ProxySettings)(nil)).Elem()
+}
+
+func (i ProxySettingsArgs) ToProxySettingsOutput() ProxySettingsOutput {
+	return i.ToProxySettingsOutputWithContext(context.Background())
+}
+
+func (i ProxySettingsArgs) ToProxySettingsOutputWithContext(ctx context.Context) ProxySettingsOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(ProxySettingsOutput)
+}
+
+func (i ProxySettingsArgs) ToProxySettingsPtrOutput() ProxySettingsPtrOutput {
+	return i.ToProxySettingsPtrOutputWithContext(context.Background())
+}
+
+func (i ProxySettingsArgs) ToProxySettingsPtrOutputWithContext(ctx context.Context) ProxySettingsPtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(ProxySettingsOutput).ToProxySettingsPtrOutputWithContext(ctx)
+}
+
+// ProxySettingsPtrInput is an input type that accepts ProxySettingsArgs, ProxySettingsPtr and ProxySettingsPtrOutput values.
+// You can construct a concrete instance of `ProxySettingsPtrInput` via:
+//
+//          ProxySettingsArgs{...}
+//
+//  or:
+//
+//          nil
+type ProxySettingsPtrInput interface {
+	pulumi.Input
+
+	ToProxySettingsPtrOutput() ProxySettingsPtrOutput
+	ToProxySettingsPtrOutputWithContext(context.Context) ProxySettingsPtrOutput
+}
+
+type proxySettingsPtrType ProxySettingsArgs
+
+func ProxySettingsPtr(v *ProxySettingsArgs) ProxySettingsPtrInput {
+	return (*proxySettingsPtrType)(v)
+}
+
+func (*proxySettingsPtrType) ElementType() reflect.Type {
+	return reflect.TypeOf((**ProxySettings)(nil)).Elem()
+}
+
+func (i *proxySettingsPtrType) ToProxySettingsPtrOutput() ProxySettingsPtrOutput {
+	return i.ToProxySettingsPtrOutputWithContext(context.Background())
+}
+
+func (i *proxySettingsPtrType) ToProxySettingsPtrOutputWithContext(ctx context.Context) ProxySettingsPtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(ProxySettingsPtrOutput)
+}
+
+// proxy settings of the nodes
+type ProxySettingsOutput struct{ *pulumi.OutputState }
+
+func (ProxySettingsOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*ProxySettings)(nil)).Elem()
+}
+
+func (o ProxySettingsOutput) ToProxySettingsOutput() ProxySettingsOutput {
+	return o
+}
+
+func (o ProxySettingsOutput) ToProxySettingsOutputWithContext(ctx context.Context) ProxySettingsOutput {
+	return o
+}
+
+func (o ProxySettingsOutput) ToProxySettingsPtrOutput() ProxySettingsPtrOutput {
+	return o.ToProxySettingsPtrOutputWithContext(context.Background())
+}
+
+func (o ProxySettingsOutput) ToProxySettingsPtrOutputWithContext(ctx context.Context) ProxySettingsPtrOutput {
+	return o.ApplyTWithContext(ctx, func(_ context.Context, v ProxySettings) *ProxySettings {
+		return &v
+	}).(ProxySettingsPtrOutput)
+}
+
+// proxy URL for HTTP requests
+func (o ProxySettingsOutput) HttpProxy() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v ProxySettings) *string { return v.HttpProxy }).(pulumi.StringPtrOutput)
+}
+
+// proxy URL for HTTPS requests
+func (o ProxySettingsOutput) HttpsProxy() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v ProxySettings) *string { return v.HttpsProxy }).(pulumi.StringPtrOutput)
+}
+
+// comma separated hosts, domains and CIDRs that are not proxied
+func (o ProxySettingsOutput) NoProxy() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v ProxySettings) *string { return v.NoProxy }).(pulumi.StringPtrOutput)
+}
+
+type ProxySettingsPtrOutput struct{ *pulumi.OutputState }
+
+func (ProxySettingsPtrOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((**ProxySettings)(nil)).Elem()
+}
+
+func (o ProxySettingsPtrOutput) ToProxySettingsPtrOutput() ProxySettingsPtrOutput {
+	return o
+}
+
+func (o ProxySettingsPtrOutput) ToProxySettingsPtrOutputWithContext(ctx context.Context) ProxySettingsPtrOutput {
+	return o
+}
+
+func (o ProxySettingsPtrOutput) Elem() ProxySettingsOutput {
+	return o.ApplyT(func(v *ProxySettings) ProxySettings {
+		if v != nil {
+			return *v
+		}
+		var ret ProxySettings
+		return ret
+	}).(ProxySettingsOutput)
+}
+
+// proxy URL for HTTP requests
+func (o ProxySettingsPtrOutput) HttpProxy() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *ProxySettings) *string {
+		if v == nil {
+			return nil
+		}
+		return v.HttpProxy
+	}).(pulumi.StringPtrOutput)
+}
+
+// proxy URL for HTTPS requests
+func (o ProxySettingsPtrOutput) HttpsProxy() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *ProxySettings) *string {
+		if v == nil {
+			return nil
+		}
+		return v.HttpsProxy
+	}).(pulumi.StringPtrOutput)
+}
+
+// comma separated hosts, domains and CIDRs that are not proxied
+func (o ProxySettingsPtrOutput) NoProxy() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *ProxySettings) *string {
+		if v == nil {
+			return nil
+		}
+		return v.NoProxy
+	}).(pulumi.StringPtrOutput)
+}
+
+// credentials containerd sends to registry mirror endpoints, either username and password or an identity token
+type RegistryAuth struct {
+	// identity token
+	IdentityToken *string `pulumi:"identityToken"`
+	// password of the user
+	Password *string `pulumi:"password"`
+	// user name
+	Username *string `pulumi:"username"`
+}
+
+// RegistryAuthInput is an input type that accepts RegistryAuthArgs and RegistryAuthOutput values.
+// You can construct a concrete instance of `RegistryAuthInput` via:
+//
+//          RegistryAuthArgs{...}
+type RegistryAuthInput interface {
+	pulumi.Input
+
+	ToRegistryAuthOutput() RegistryAuthOutput
+	ToRegistryAuthOutputWithContext(context.Context) RegistryAuthOutput
+}
+
+// credentials containerd sends to registry mirror endpoints, either username and password or an identity token
+type RegistryAuthArgs struct {
+	// identity token
+	IdentityToken pulumi.StringPtrInput `pulumi:"identityToken"`
+	// password of the user
+	Password pulumi.StringPtrInput `pulumi:"password"`
+	// user name
+	Username pulumi.StringPtrInput `pulumi:"username"`
+}
+
+func (RegistryAuthArgs) ElementType() reflect.Type {
+	return reflect.TypeOf((*RegistryAuth)(nil)).Elem()
+}
+
+func (i RegistryAuthArgs) ToRegistryAuthOutput() RegistryAuthOutput {
+	return i.ToRegistryAuthOutputWithContext(context.Background())
+}
+
+func (i RegistryAuthArgs) ToRegistryAuthOutputWithContext(ctx context.Context) RegistryAuthOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(RegistryAuthOutput)
+}
+
+func (i RegistryAuthArgs) ToRegistryAuthPtrOutput() RegistryAuthPtrOutput {
+	return i.ToRegistryAuthPtrOutputWithContext(context.Background())
+}
+
+func (i RegistryAuthArgs) ToRegistryAuthPtrOutputWithContext(ctx context.Context) RegistryAuthPtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(RegistryAuthOutput).ToRegistryAuthPtrOutputWithContext(ctx)
+}
+
+// RegistryAuthPtrInput is an input type that accepts RegistryAuthArgs, RegistryAuthPtr and RegistryAuthPtrOutput values.
+// You can construct a concrete instance of `RegistryAuthPtrInput` via:
+//
+//          RegistryAuthArgs{...}
+//
+//  or:
+//
+//          nil
+type RegistryAuthPtrInput interface {
+	pulumi.Input
+
+	ToRegistryAuthPtrOutput() RegistryAuthPtrOutput
+	ToRegistryAuthPtrOutputWithContext(context.Context) RegistryAuthPtrOutput
+}
+
+type registryAuthPtrType RegistryAuthArgs
+
+func RegistryAuthPtr(v *RegistryAuthArgs) RegistryAuthPtrInput {
+	return (*registryAuthPtrType)(v)
+}
+
+func (*registryAuthPtrType) ElementType() reflect.Type {
+	return reflect.TypeOf((**RegistryAuth)(nil)).Elem()
+}
+
+func (i *registryAuthPtrType) ToRegistryAuthPtrOutput() RegistryAuthPtrOutput {
+	return i.ToRegistryAuthPtrOutputWithContext(context.Background())
+}
+
+func (i *registryAuthPtrType) ToRegistryAuthPtrOutputWithContext(ctx context.Context) RegistryAuthPtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(RegistryAuthPtrOutput)
+}
+
+// credentials containerd sends to registry mirror endpoints, either username and password or an identity token
+type RegistryAuthOutput struct{ *pulumi.OutputState }
+
+func (RegistryAuthOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*RegistryAuth)(nil)).Elem()
+}
+
+func (o RegistryAuthOutput) ToRegistryAuthOutput() RegistryAuthOutput {
+	return o
+}
+
+func (o RegistryAuthOutput) ToRegistryAuthOutputWithContext(ctx context.Context) RegistryAuthOutput {
+	return o
+}
+
+func (o RegistryAuthOutput) ToRegistryAuthPtrOutput() RegistryAuthPtrOutput {
+	return o.ToRegistryAuthPtrOutputWithContext(context.Background())
+}
+
+func (o RegistryAuthOutput) ToRegistryAuthPtrOutputWithContext(ctx context.Context) RegistryAuthPtrOutput {
+	return o.ApplyTWithContext(ctx, func(_ context.Context, v RegistryAuth) *RegistryAuth {
+		return &v
+	}).(RegistryAuthPtrOutput)
+}
+
+// identity token
+func (o RegistryAuthOutput) IdentityToken() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v RegistryAuth) *string { return v.IdentityToken }).(pulumi.StringPtrOutput)
+}
+
+// password of the user
+func (o RegistryAuthOutput) Password() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v RegistryAuth) *string { return v.Password }).(pulumi.StringPtrOutput)
+}
+
+// user name
+func (o RegistryAuthOutput) Username() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v RegistryAuth) *string { return v.Username }).(pulumi.StringPtrOutput)
+}
+
+type RegistryAuthPtrOutput struct{ *pulumi.OutputState }
+
+func (RegistryAuthPtrOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((**RegistryAuth)(nil)).Elem()
+}
+
+func (o RegistryAuthPtrOutput) ToRegistryAuthPtrOutput() RegistryAuthPtrOutput {
+	return o
+}
+
+func (o RegistryAuthPtrOutput) ToRegistryAuthPtrOutputWithContext(ctx context.Context) RegistryAuthPtrOutput {
+	return o
+}
+
+func (o RegistryAuthPtrOutput) Elem() RegistryAuthOutput {
+	return o.ApplyT(func(v *RegistryAuth) RegistryAuth {
+		if v != nil {
+			return *v
+		}
+		var ret RegistryAuth
+		return ret
+	}).(RegistryAuthOutput)
+}
+
+// identity token
+func (o RegistryAuthPtrOutput) IdentityToken() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *RegistryAuth) *string {
+		if v == nil {
+			return nil
+		}
+		return v.IdentityToken
+	}).(pulumi.StringPtrOutput)
+}
+
+// password of the user
+func (o RegistryAuthPtrOutput) Password() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *RegistryAuth) *string {
+		if v == nil {
+			return nil
+		}
+		return v.Password
+	}).(pulumi.StringPtrOutput)
+}
+
+// user name
+func (o RegistryAuthPtrOutput) Username() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *RegistryAuth) *string {
+		if v == nil {
+			return nil
+		}
+		return v.Username
+	}).(pulumi.StringPtrOutput)
+}
+
+// pull-through mirror of a registry
+type RegistryMirror struct {
+	// credentials of the endpoints
+	Auth *RegistryAuth `pulumi:"auth"`
+	// absolute path in the nodes of the CA certificate of the endpoints, mounted with extraMounts
+	CaFile *string `pulumi:"caFile"`
+	// http or https URLs of the mirrors, tried in order before the registry itself
+	Endpoints []string `pulumi:"endpoints"`
+	// skip the TLS verification of the endpoints
+	InsecureSkipVerify *bool `pulumi:"insecureSkipVerify"`
+}
+
+// RegistryMirrorInput is an input type that accepts RegistryMirrorArgs and RegistryMirrorOutput values.
+// You can construct a concrete instance of `RegistryMirrorInput` via:
+//
+//          RegistryMirrorArgs{...}
+type RegistryMirrorInput interface {
+	pulumi.Input
+
+	ToRegistryMirrorOutput() RegistryMirrorOutput
+	ToRegistryMirrorOutputWithContext(context.Context) RegistryMirrorOutput
+}
+
+// pull-through mirror of a registry
+type RegistryMirrorArgs struct {
+	// credentials of the endpoints
+	Auth RegistryAuthPtrInput `pulumi:"auth"`
+	// absolute path in the nodes of the CA certificate of the endpoints, mounted with extraMounts
+	CaFile pulumi.StringPtrInput `pulumi:"caFile"`
+	// http or https URLs of the mirrors, tried in order before the registry itself
+	Endpoints pulumi.StringArrayInput `pulumi:"endpoints"`
+	// skip the TLS verification of the endpoints
+	InsecureSkipVerify pulumi.BoolPtrInput `pulumi:"insecureSkipVerify"`
+}
+
+func (RegistryMirrorArgs) ElementType() reflect.Type {
+	return reflect.TypeOf((*RegistryMirror)(nil)).Elem()
+}
+
+func (i RegistryMirrorArgs) ToRegistryMirrorOutput() RegistryMirrorOutput {
+	return i.ToRegistryMirrorOutputWithContext(context.Background())
+}
+
+func (i RegistryMirrorArgs) ToRegistryMirrorOutputWithContext(ctx context.Context) RegistryMirrorOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(RegistryMirrorOutput)
+}
+
+// RegistryMirrorMapInput is an input type that accepts RegistryMirrorMap and RegistryMirrorMapOutput values.
+// You can construct a concrete instance of `RegistryMirrorMapInput` via:
+//
+//          RegistryMirrorMap{ "key": RegistryMirrorArgs{...} }
+type RegistryMirrorMapInput interface {
+	pulumi.Input
+
+	ToRegistryMirrorMapOutput() RegistryMirrorMapOutput
+	ToRegistryMirrorMapOutputWithContext(context.Context) RegistryMirrorMapOutput
+}
+
+type RegistryMirrorMap map[string]RegistryMirrorInput
+
+func (RegistryMirrorMap) ElementType() reflect.Type {
+	return reflect.TypeOf((*map[string]RegistryMirror)(nil)).Elem()
+}
+
+func (i RegistryMirrorMap) ToRegistryMirrorMapOutput() RegistryMirrorMapOutput {
+	return i.ToRegistryMirrorMapOutputWithContext(context.Background())
+}
+
+func (i RegistryMirrorMap) ToRegistryMirrorMapOutputWithContext(ctx context.Context) RegistryMirrorMapOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(RegistryMirrorMapOutput)
+}
+
+// pull-through mirror of a registry
+type RegistryMirrorOutput struct{ *pulumi.OutputState }
+
+func (RegistryMirrorOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*RegistryMirror)(nil)).Elem()
+}
+
+func (o RegistryMirrorOutput) ToRegistryMirrorOutput() RegistryMirrorOutput {
+	return o
+}
+
+func (o RegistryMirrorOutput) ToRegistryMirrorOutputWithContext(ctx context.Context) RegistryMirrorOutput {
+	return o
+}
+
+// credentials of the endpoints
+func (o RegistryMirrorOutput) Auth() RegistryAuthPtrOutput {
+	return o.ApplyT(func(v RegistryMirror) *RegistryAuth { return v.Auth }).(RegistryAuthPtrOutput)
+}
+
+// absolute path in the nodes of the CA certificate of the endpoints, mounted with extraMounts
+func (o RegistryMirrorOutput) CaFile() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v RegistryMirror) *string { return v.CaFile }).(pulumi.StringPtrOutput)
+}
+
+// http or https URLs of the mirrors, tried in order before the registry itself
+func (o RegistryMirrorOutput) Endpoints() pulumi.StringArrayOutput {
+	return o.ApplyT(func(v RegistryMirror) []string { return v.Endpoints }).(pulumi.StringArrayOutput)
+}
+
+// skip the TLS verification of the endpoints
+func (o RegistryMirrorOutput) InsecureSkipVerify() pulumi.BoolPtrOutput {
+	return o.ApplyT(func(v RegistryMirror) *bool { return v.InsecureSkipVerify }).(pulumi.BoolPtrOutput)
+}
+
+type RegistryMirrorMapOutput struct{ *pulumi.OutputState }
+
+func (RegistryMirrorMapOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*map[string]RegistryMirror)(nil)).Elem()
+}
+
+func (o RegistryMirrorMapOutput) ToRegistryMirrorMapOutput() RegistryMirrorMapOutput {
+	return o
+}
+
+func (o RegistryMirrorMapOutput) ToRegistryMirrorMapOutputWithContext(ctx context.Context) RegistryMirrorMapOutput {
+	return o
+}
+
+func (o RegistryMirrorMapOutput) MapIndex(k pulumi.StringInput) RegistryMirrorOutput {
+	return pulumi.All(o, k).ApplyT(func(vs []interface{}) RegistryMirror {
+		return vs[0].(map[string]RegistryMirror)[vs[1].(string)]
+	}).(RegistryMirrorOutput)
+}
+
+func init() {
+	pulumi.RegisterInputType(reflect.TypeOf((*APIServerInput)(nil)).Elem(), APIServerArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*APIServerPtrInput)(nil)).Elem(), APIServerArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*HostAliasInput)(nil)).Elem(), HostAliasArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*HostAliasArrayInput)(nil)).Elem(), HostAliasArray{})
+	pulumi.RegisterInputType(reflect.TypeOf((*NetworkSettingsInput)(nil)).Elem(), NetworkSettingsArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*NetworkSettingsPtrInput)(nil)).Elem(), NetworkSettingsArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*NodeDetailInput)(nil)).Elem(), NodeDetailArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*NodeDetailArrayInput)(nil)).Elem(), NodeDetailArray{})
+	pulumi.RegisterInputType(reflect.TypeOf((*NodePortMappingInput)(nil)).Elem(), NodePortMappingArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*NodePortMappingArrayInput)(nil)).Elem(), NodePortMappingArray{})
+	pulumi.RegisterInputType(reflect.TypeOf((*OIDCInput)(nil)).Elem(), OIDCArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*OIDCPtrInput)(nil)).Elem(), OIDCArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*ProxySettingsInput)(nil)).Elem(), ProxySettingsArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*ProxySettingsPtrInput)(nil)).Elem(), ProxySettingsArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*RegistryAuthInput)(nil)).Elem(), RegistryAuthArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*RegistryAuthPtrInput)(nil)).Elem(), RegistryAuthArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*RegistryMirrorInput)(nil)).Elem(), RegistryMirrorArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*RegistryMirrorMapInput)(nil)).Elem(), RegistryMirrorMap{})
+	pulumi.RegisterOutputType(APIServerOutput{})
+	pulumi.RegisterOutputType(APIServerPtrOutput{})
+	pulumi.RegisterOutputType(HostAliasOutput{})
+	pulumi.RegisterOutputType(HostAliasArrayOutput{})
+	pulumi.RegisterOutputType(NetworkSettingsOutput{})
+	pulumi.RegisterOutputType(NetworkSettingsPtrOutput{})
+	pulumi.RegisterOutputType(NodeDetailOutput{})
+	pulumi.RegisterOutputType(NodeDetailArrayOutput{})
+	pulumi.RegisterOutputType(NodePortMappingOutput{})
+	pulumi.RegisterOutputType(NodePortMappingArrayOutput{})
+	pulumi.RegisterOutputType(OIDCOutput{})
+	pulumi.RegisterOutputType(OIDCPtrOutput{})
+	pulumi.RegisterOutputType(ProxySettingsOutput{})
+	pulumi.RegisterOutputType(ProxySettingsPtrOutput{})
+	pulumi.RegisterOutputType(RegistryAuthOutput{})
+	pulumi.RegisterOutputType(RegistryAuthPtrOutput{})
+	pulumi.RegisterOutputType(RegistryMirrorOutput{})
+	pulumi.RegisterOutputType(RegistryMirrorMapOutput{})
+}
@@ -8,6 +8,26 @@ import (
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi/config"
 )
 
+// Derive the suffix of generated cluster names from the stack and resource name instead of picking it at random. Default: false. Optional
+func GetAutonameDeterministic(ctx *pulumi.Context) bool {
+	return config.GetBool(ctx, "kind:autonameDeterministic")
+}
+
+// Maximum length of generated cluster names, longer resource names are truncated. Default: 40. Maximum: 50. Optional
+func GetAutonameMaxLength(ctx *pulumi.Context) int {
+	return config.GetInt(ctx, "kind:autonameMaxLength")
+}
+
+// Prefix to add to generated cluster names. Optional
+func GetAutonamePrefix(ctx *pulumi.Context) string {
+	return config.Get(ctx, "kind:autonamePrefix")
+}
+
+// Length of the random suffix added to generated cluster names, 0 disables the suffix. Default: 8. Optional
+func GetAutonameSuffixLength(ctx *pulumi.Context) int {
+	return config.GetInt(ctx, "kind:autonameSuffixLength")
+}
+
 // Kind config file to use. Optional
 func GetConfigFile(ctx *pulumi.Context) string {
 	return config.Get(ctx, "kind:configFile")
@@ -23,6 +43,11 @@ func GetNodeImage(ctx *pulumi.Context) string {
 	return config.Get(ctx, "kind:nodeImage")
 }
 
+// Never pull images. Node and registry images that are not available locally fail the preview instead. Default: false. Optional
+func GetOffline(ctx *pulumi.Context) bool {
+	return config.GetBool(ctx, "kind:offline")
+}
+
 // Provider to use. Supports docker/podman. Default: docker. Optional
 func GetProvider(ctx *pulumi.Context) string {
 	return config.Get(ctx, "kind:provider")
@@ -38,7 +63,7 @@ func GetStopBeforeSettingK8s(ctx *pulumi.Context) bool {
 	return config.GetBool(ctx, "kind:stopBeforeSettingK8s")
 }
 
-// Time in seconds to wait for nodes to become ready. Default: none. Optional
-func GetWaitForNodeReady(ctx *pulumi.Context) int {
-	return config.GetInt(ctx, "kind:waitForNodeReady")
+// Time to wait for nodes to become ready, either in seconds or as a duration like 90s or 5m. Default: none. Optional
+func GetWaitForNodeReady(ctx *pulumi.Context) string {
+	return config.Get(ctx, "kind:waitForNodeReady")
 }
@@ -0,0 +1,79 @@
+// *** WARNING: this file was generated by pulumigen. ***
+// *** Do not edit by hand unless you're certain you know what you are doing! ***
+
+package kind
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// Node image pinned by digest for a Kubernetes version, for the KIND release of the provider
+func GetNodeImage(ctx *pulumi.Context, args *GetNodeImageArgs, opts ...pulumi.InvokeOption) (*GetNodeImageResult, error) {
+	var rv GetNodeImageResult
+	err := ctx.Invoke("kind:index:getNodeImage", args, &rv, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &rv, nil
+}
+
+type GetNodeImageArgs struct {
+	// Kubernetes version like 1.21 or v1.21.1. Default: the version of KIND's default node image
+	KubernetesVersion *string `pulumi:"kubernetesVersion"`
+}
+
+type GetNodeImageResult struct {
+	// node image reference with its digest
+	Image string `pulumi:"image"`
+	// exact Kubernetes version of the node image
+	KubernetesVersion string `pulumi:"kubernetesVersion"`
+}
+
+func GetNodeImageOutput(ctx *pulumi.Context, args GetNodeImageOutputArgs, opts ...pulumi.InvokeOption) GetNodeImageResultOutput {
+	return pulumi.ToOutputWithContext(context.Background(), args).
+		ApplyT(func(v interface{}) (GetNodeImageResult, error) {
+			args := v.(GetNodeImageArgs)
+			r, err := GetNodeImage(ctx, &args, opts...)
+			return *r, err
+		}).(GetNodeImageResultOutput)
+}
+
+type GetNodeImageOutputArgs struct {
+	// Kubernetes version like 1.21 or v1.21.1. Default: the version of KIND's default node image
+	KubernetesVersion pulumi.StringPtrInput `pulumi:"kubernetesVersion"`
+}
+
+func (GetNodeImageOutputArgs) ElementType() reflect.Type {
+	return reflect.TypeOf((*GetNodeImageArgs)(nil)).Elem()
+}
+
+type GetNodeImageResultOutput struct{ *pulumi.OutputState }
+
+func (GetNodeImageResultOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*GetNodeImageResult)(nil)).Elem()
+}
+
+func (o GetNodeImageResultOutput) ToGetNodeImageResultOutput() GetNodeImageResultOutput {
+	return o
+}
+
+func (o GetNodeImageResultOutput) ToGetNodeImageResultOutputWithContext(ctx context.Context) GetNodeImageResultOutput {
+	return o
+}
+
+// node image reference with its digest
+func (o GetNodeImageResultOutput) Image() pulumi.StringOutput {
+	return o.ApplyT(func(v GetNodeImageResult) string { return v.Image }).(pulumi.StringOutput)
+}
+
+// exact Kubernetes version of the node image
+func (o GetNodeImageResultOutput) KubernetesVersion() pulumi.StringOutput {
+	return o.ApplyT(func(v GetNodeImageResult) string { return v.KubernetesVersion }).(pulumi.StringOutput)
+}
+
+func init() {
+	pulumi.RegisterOutputType(GetNodeImageResultOutput{})
+}
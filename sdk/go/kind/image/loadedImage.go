@@ -0,0 +1,297 @@
+// *** WARNING: this file was generated by pulumigen. ***
+// *** Do not edit by hand unless you're certain you know what you are doing! ***
+
+package image
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// Local image or image archive loaded into the nodes of a KIND cluster, like kind load
+type LoadedImage struct {
+	pulumi.CustomResourceState
+
+	// path of the local image archive
+	Archive pulumi.StringPtrOutput `pulumi:"archive"`
+	// cluster name
+	ClusterName pulumi.StringOutput `pulumi:"clusterName"`
+	// local image ID, or the IDs of the images in the archive
+	Digest pulumi.StringPtrOutput `pulumi:"digest"`
+	// local image reference
+	Image pulumi.StringPtrOutput `pulumi:"image"`
+	// names of the nodes the image was loaded into
+	LoadedNodes pulumi.StringArrayOutput `pulumi:"loadedNodes"`
+	// names of the nodes the image is loaded into
+	Nodes pulumi.StringArrayOutput `pulumi:"nodes"`
+	// references the image is known by in the nodes
+	References pulumi.StringArrayOutput `pulumi:"references"`
+}
+
+// NewLoadedImage registers a new resource with the given unique name, arguments, and options.
+func NewLoadedImage(ctx *pulumi.Context,
+	name string, args *LoadedImageArgs, opts ...pulumi.ResourceOption) (*LoadedImage, error) {
+	if args == nil {
+		return nil, errors.New("missing one or more required arguments")
+	}
+
+	if args.ClusterName == nil {
+		return nil, errors.New("invalid value for required argument 'ClusterName'")
+	}
+	var resource LoadedImage
+	err := ctx.RegisterResource("kind:image:LoadedImage", name, args, &resource, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &resource, nil
+}
+
+// GetLoadedImage gets an existing LoadedImage resource's state with the given name, ID, and optional
+// state properties that are used to uniquely qualify the lookup (nil if not required).
+func GetLoadedImage(ctx *pulumi.Context,
+	name string, id pulumi.IDInput, state *LoadedImageState, opts ...pulumi.ResourceOption) (*LoadedImage, error) {
+	var resource LoadedImage
+	err := ctx.ReadResource("kind:image:LoadedImage", name, id, state, &resource, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &resource, nil
+}
+
+// Input properties used for looking up and filtering LoadedImage resources.
+type loadedImageState struct {
+}
+
+type LoadedImageState struct {
+}
+
+func (LoadedImageState) ElementType() reflect.Type {
+	return reflect.TypeOf((*loadedImageState)(nil)).Elem()
+}
+
+type loadedImageArgs struct {
+	// Path of a local image archive to load, reloaded whenever the images in it change. Either image or archive is required
+	Archive *string `pulumi:"archive"`
+	// Name of the KIND cluster to load the image into
+	ClusterName string `pulumi:"clusterName"`
+	// Local image to load, reloaded whenever its image ID changes. Either image or archive is required
+	Image *string `pulumi:"image"`
+	// Names of the nodes to load the image into. Default: all nodes
+	Nodes []string `pulumi:"nodes"`
+}
+
+// The set of arguments for constructing a LoadedImage resource.
+type LoadedImageArgs struct {
+	// Path of a local image archive to load, reloaded whenever the images in it change. Either image or archive is required
+	Archive pulumi.StringPtrInput
+	// Name of the KIND cluster to load the image into
+	ClusterName pulumi.StringInput
+	// Local image to load, reloaded whenever its image ID changes. Either image or archive is required
+	Image pulumi.StringPtrInput
+	// Names of the nodes to load the image into. Default: all nodes
+	Nodes pulumi.StringArrayInput
+}
+
+func (LoadedImageArgs) ElementType() reflect.Type {
+	return reflect.TypeOf((*loadedImageArgs)(nil)).Elem()
+}
+
+type LoadedImageInput interface {
+	pulumi.Input
+
+	ToLoadedImageOutput() LoadedImageOutput
+	ToLoadedImageOutputWithContext(ctx context.Context) LoadedImageOutput
+}
+
+func (*LoadedImage) ElementType() reflect.Type {
+	return reflect.TypeOf((*LoadedImage)(nil))
+}
+
+func (i *LoadedImage) ToLoadedImageOutput() LoadedImageOutput {
+	return i.ToLoadedImageOutputWithContext(context.Background())
+}
+
+func (i *LoadedImage) ToLoadedImageOutputWithContext(ctx context.Context) LoadedImageOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(LoadedImageOutput)
+}
+
+func (i *LoadedImage) ToLoadedImagePtrOutput() LoadedImagePtrOutput {
+	return i.ToLoadedImagePtrOutputWithContext(context.Background())
+}
+
+func (i *LoadedImage) ToLoadedImagePtrOutputWithContext(ctx context.Context) LoadedImagePtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(LoadedImagePtrOutput)
+}
+
+type LoadedImagePtrInput interface {
+	pulumi.Input
+
+	ToLoadedImagePtrOutput() LoadedImagePtrOutput
+	ToLoadedImagePtrOutputWithContext(ctx context.Context) LoadedImagePtrOutput
+}
+
+type loadedImagePtrType LoadedImageArgs
+
+func (*loadedImagePtrType) ElementType() reflect.Type {
+	return reflect.TypeOf((**LoadedImage)(nil))
+}
+
+func (i *loadedImagePtrType) ToLoadedImagePtrOutput() LoadedImagePtrOutput {
+	return i.ToLoadedImagePtrOutputWithContext(context.Background())
+}
+
+func (i *loadedImagePtrType) ToLoadedImagePtrOutputWithContext(ctx context.Context) LoadedImagePtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(LoadedImagePtrOutput)
+}
+
+// LoadedImageArrayInput is an input type that accepts LoadedImageArray and LoadedImageArrayOutput values.
+// You can construct a concrete instance of `LoadedImageArrayInput` via:
+//
+//          LoadedImageArray{ LoadedImageArgs{...} }
+type LoadedImageArrayInput interface {
+	pulumi.Input
+
+	ToLoadedImageArrayOutput() LoadedImageArrayOutput
+	ToLoadedImageArrayOutputWithContext(context.Context) LoadedImageArrayOutput
+}
+
+type LoadedImageArray []LoadedImageInput
+
+func (LoadedImageArray) ElementType() reflect.Type {
+	return reflect.TypeOf((*[]*LoadedImage)(nil)).Elem()
+}
+
+func (i LoadedImageArray) ToLoadedImageArrayOutput() LoadedImageArrayOutput {
+	return i.ToLoadedImageArrayOutputWithContext(context.Background())
+}
+
+func (i LoadedImageArray) ToLoadedImageArrayOutputWithContext(ctx context.Context) LoadedImageArrayOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(LoadedImageArrayOutput)
+}
+
+// LoadedImageMapInput is an input type that accepts LoadedImageMap and LoadedImageMapOutput values.
+// You can construct a concrete instance of `LoadedImageMapInput` via:
+//
+//          LoadedImageMap{ "key": LoadedImageArgs{...} }
+type LoadedImageMapInput interface {
+	pulumi.Input
+
+	ToLoadedImageMapOutput() LoadedImageMapOutput
+	ToLoadedImageMapOutputWithContext(context.Context) LoadedImageMapOutput
+}
+
+type LoadedImageMap map[string]LoadedImageInput
+
+func (LoadedImageMap) ElementType() reflect.Type {
+	return reflect.TypeOf((*map[string]*LoadedImage)(nil)).Elem()
+}
+
+func (i LoadedImageMap) ToLoadedImageMapOutput() LoadedImageMapOutput {
+	return i.ToLoadedImageMapOutputWithContext(context.Background())
+}
+
+func (i LoadedImageMap) ToLoadedImageMapOutputWithContext(ctx context.Context) LoadedImageMapOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(LoadedImageMapOutput)
+}
+
+type LoadedImageOutput struct{ *pulumi.OutputState }
+
+func (LoadedImageOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*LoadedImage)(nil))
+}
+
+func (o LoadedImageOutput) ToLoadedImageOutput() LoadedImageOutput {
+	return o
+}
+
+func (o LoadedImageOutput) ToLoadedImageOutputWithContext(ctx context.Context) LoadedImageOutput {
+	return o
+}
+
+func (o LoadedImageOutput) ToLoadedImagePtrOutput() LoadedImagePtrOutput {
+	return o.ToLoadedImagePtrOutputWithContext(context.Background())
+}
+
+func (o LoadedImageOutput) ToLoadedImagePtrOutputWithContext(ctx context.Context) LoadedImagePtrOutput {
+	return o.ApplyTWithContext(ctx, func(_ context.Context, v LoadedImage) *LoadedImage {
+		return &v
+	}).(LoadedImagePtrOutput)
+}
+
+type LoadedImagePtrOutput struct{ *pulumi.OutputState }
+
+func (LoadedImagePtrOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((**LoadedImage)(nil))
+}
+
+func (o LoadedImagePtrOutput) ToLoadedImagePtrOutput() LoadedImagePtrOutput {
+	return o
+}
+
+func (o LoadedImagePtrOutput) ToLoadedImagePtrOutputWithContext(ctx context.Context) LoadedImagePtrOutput {
+	return o
+}
+
+func (o LoadedImagePtrOutput) Elem() LoadedImageOutput {
+	return o.ApplyT(func(v *LoadedImage) LoadedImage {
+		if v != nil {
+			return *v
+		}
+		var ret LoadedImage
+		return ret
+	}).(LoadedImageOutput)
+}
+
+type LoadedImageArrayOutput struct{ *pulumi.OutputState }
+
+func (LoadedImageArrayOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*[]LoadedImage)(nil))
+}
+
+func (o LoadedImageArrayOutput) ToLoadedImageArrayOutput() LoadedImageArrayOutput {
+	return o
+}
+
+func (o LoadedImageArrayOutput) ToLoadedImageArrayOutputWithContext(ctx context.Context) LoadedImageArrayOutput {
+	return o
+}
+
+func (o LoadedImageArrayOutput) Index(i pulumi.IntInput) LoadedImageOutput {
+	return pulumi.All(o, i).ApplyT(func(vs []interface{}) LoadedImage {
+		return vs[0].([]LoadedImage)[vs[1].(int)]
+	}).(LoadedImageOutput)
+}
+
+type LoadedImageMapOutput struct{ *pulumi.OutputState }
+
+func (LoadedImageMapOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*map[string]LoadedImage)(nil))
+}
+
+func (o LoadedImageMapOutput) ToLoadedImageMapOutput() LoadedImageMapOutput {
+	return o
+}
+
+func (o LoadedImageMapOutput) ToLoadedImageMapOutputWithContext(ctx context.Context) LoadedImageMapOutput {
+	return o
+}
+
+func (o LoadedImageMapOutput) MapIndex(k pulumi.StringInput) LoadedImageOutput {
+	return pulumi.All(o, k).ApplyT(func(vs []interface{}) LoadedImage {
+		return vs[0].(map[string]LoadedImage)[vs[1].(string)]
+	}).(LoadedImageOutput)
+}
+
+func init() {
+	pulumi.RegisterInputType(reflect.TypeOf((*LoadedImageInput)(nil)).Elem(), &LoadedImage{})
+	pulumi.RegisterInputType(reflect.TypeOf((*LoadedImagePtrInput)(nil)).Elem(), &LoadedImage{})
+	pulumi.RegisterInputType(reflect.TypeOf((*LoadedImageArrayInput)(nil)).Elem(), LoadedImageArray{})
+	pulumi.RegisterInputType(reflect.TypeOf((*LoadedImageMapInput)(nil)).Elem(), LoadedImageMap{})
+	pulumi.RegisterOutputType(LoadedImageOutput{})
+	pulumi.RegisterOutputType(LoadedImagePtrOutput{})
+	pulumi.RegisterOutputType(LoadedImageArrayOutput{})
+	pulumi.RegisterOutputType(LoadedImageMapOutput{})
+}
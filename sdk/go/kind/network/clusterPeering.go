@@ -0,0 +1,287 @@
+// *** WARNING: this file was generated by pulumigen. ***
+// *** Do not edit by hand unless you're certain you know what you are doing! ***
+
+package network
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// Shared docker/podman network the nodes of KIND clusters are attached to, with static routes on every node to the pod and service subnets of the other clusters
+type ClusterPeering struct {
+	pulumi.CustomResourceState
+
+	// names of the peered clusters
+	ClusterNames pulumi.StringArrayOutput `pulumi:"clusterNames"`
+	// clusters attached to the network with their subnets and nodes
+	Clusters PeeredClusterArrayOutput `pulumi:"clusters"`
+	// docker/podman network the nodes are attached to
+	Network pulumi.StringOutput `pulumi:"network"`
+	// IPv4 subnet of the network when it is created
+	Subnet pulumi.StringPtrOutput `pulumi:"subnet"`
+}
+
+// NewClusterPeering registers a new resource with the given unique name, arguments, and options.
+func NewClusterPeering(ctx *pulumi.Context,
+	name string, args *ClusterPeeringArgs, opts ...pulumi.ResourceOption) (*ClusterPeering, error) {
+	if args == nil {
+		return nil, errors.New("missing one or more required arguments")
+	}
+
+	if args.ClusterNames == nil {
+		return nil, errors.New("invalid value for required argument 'ClusterNames'")
+	}
+	var resource ClusterPeering
+	err := ctx.RegisterResource("kind:network:ClusterPeering", name, args, &resource, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &resource, nil
+}
+
+// GetClusterPeering gets an existing ClusterPeering resource's state with the given name, ID, and optional
+// state properties that are used to uniquely qualify the lookup (nil if not required).
+func GetClusterPeering(ctx *pulumi.Context,
+	name string, id pulumi.IDInput, state *ClusterPeeringState, opts ...pulumi.ResourceOption) (*ClusterPeering, error) {
+	var resource ClusterPeering
+	err := ctx.ReadResource("kind:network:ClusterPeering", name, id, state, &resource, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &resource, nil
+}
+
+// Input properties used for looking up and filtering ClusterPeering resources.
+type clusterPeeringState struct {
+}
+
+type ClusterPeeringState struct {
+}
+
+func (ClusterPeeringState) ElementType() reflect.Type {
+	return reflect.TypeOf((*clusterPeeringState)(nil)).Elem()
+}
+
+type clusterPeeringArgs struct {
+	// Names of at least two KIND clusters to peer. Their pod and service subnets, and the subnets of the network, must not overlap. Changes are applied in place
+	ClusterNames []string `pulumi:"clusterNames"`
+	// docker/podman network to attach the nodes to, created when missing and removed with the peering when it was created by the provider. Default: kind-peering
+	Network *string `pulumi:"network"`
+	// IPv4 subnet of the network when it is created, picked by the container runtime otherwise
+	Subnet *string `pulumi:"subnet"`
+}
+
+// The set of arguments for constructing a ClusterPeering resource.
+type ClusterPeeringArgs struct {
+	// Names of at least two KIND clusters to peer. Their pod and service subnets, and the subnets of the network, must not overlap. Changes are applied in place
+	ClusterNames pulumi.StringArrayInput
+	// docker/podman network to attach the nodes to, created when missing and removed with the peering when it was created by the provider. Default: kind-peering
+	Network pulumi.StringPtrInput
+	// IPv4 subnet of the network when it is created, picked by the container runtime otherwise
+	Subnet pulumi.StringPtrInput
+}
+
+func (ClusterPeeringArgs) ElementType() reflect.Type {
+	return reflect.TypeOf((*clusterPeeringArgs)(nil)).Elem()
+}
+
+type ClusterPeeringInput interface {
+	pulumi.Input
+
+	ToClusterPeeringOutput() ClusterPeeringOutput
+	ToClusterPeeringOutputWithContext(ctx context.Context) ClusterPeeringOutput
+}
+
+func (*ClusterPeering) ElementType() reflect.Type {
+	return reflect.TypeOf((*ClusterPeering)(nil))
+}
+
+func (i *ClusterPeering) ToClusterPeeringOutput() ClusterPeeringOutput {
+	return i.ToClusterPeeringOutputWithContext(context.Background())
+}
+
+func (i *ClusterPeering) ToClusterPeeringOutputWithContext(ctx context.Context) ClusterPeeringOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(ClusterPeeringOutput)
+}
+
+func (i *ClusterPeering) ToClusterPeeringPtrOutput() ClusterPeeringPtrOutput {
+	return i.ToClusterPeeringPtrOutputWithContext(context.Background())
+}
+
+func (i *ClusterPeering) ToClusterPeeringPtrOutputWithContext(ctx context.Context) ClusterPeeringPtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(ClusterPeeringPtrOutput)
+}
+
+type ClusterPeeringPtrInput interface {
+	pulumi.Input
+
+	ToClusterPeeringPtrOutput() ClusterPeeringPtrOutput
+	ToClusterPeeringPtrOutputWithContext(ctx context.Context) ClusterPeeringPtrOutput
+}
+
+type clusterPeeringPtrType ClusterPeeringArgs
+
+func (*clusterPeeringPtrType) ElementType() reflect.Type {
+	return reflect.TypeOf((**ClusterPeering)(nil))
+}
+
+func (i *clusterPeeringPtrType) ToClusterPeeringPtrOutput() ClusterPeeringPtrOutput {
+	return i.ToClusterPeeringPtrOutputWithContext(context.Background())
+}
+
+func (i *clusterPeeringPtrType) ToClusterPeeringPtrOutputWithContext(ctx context.Context) ClusterPeeringPtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(ClusterPeeringPtrOutput)
+}
+
+// ClusterPeeringArrayInput is an input type that accepts ClusterPeeringArray and ClusterPeeringArrayOutput values.
+// You can construct a concrete instance of `ClusterPeeringArrayInput` via:
+//
+//          ClusterPeeringArray{ ClusterPeeringArgs{...} }
+type ClusterPeeringArrayInput interface {
+	pulumi.Input
+
+	ToClusterPeeringArrayOutput() ClusterPeeringArrayOutput
+	ToClusterPeeringArrayOutputWithContext(context.Context) ClusterPeeringArrayOutput
+}
+
+type ClusterPeeringArray []ClusterPeeringInput
+
+func (ClusterPeeringArray) ElementType() reflect.Type {
+	return reflect.TypeOf((*[]*ClusterPeering)(nil)).Elem()
+}
+
+func (i ClusterPeeringArray) ToClusterPeeringArrayOutput() ClusterPeeringArrayOutput {
+	return i.ToClusterPeeringArrayOutputWithContext(context.Background())
+}
+
+func (i ClusterPeeringArray) ToClusterPeeringArrayOutputWithContext(ctx context.Context) ClusterPeeringArrayOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(ClusterPeeringArrayOutput)
+}
+
+// ClusterPeeringMapInput is an input type that accepts ClusterPeeringMap and ClusterPeeringMapOutput values.
+// You can construct a concrete instance of `ClusterPeeringMapInput` via:
+//
+//          ClusterPeeringMap{ "key": ClusterPeeringArgs{...} }
+type ClusterPeeringMapInput interface {
+	pulumi.Input
+
+	ToClusterPeeringMapOutput() ClusterPeeringMapOutput
+	ToClusterPeeringMapOutputWithContext(context.Context) ClusterPeeringMapOutput
+}
+
+type ClusterPeeringMap map[string]ClusterPeeringInput
+
+func (ClusterPeeringMap) ElementType() reflect.Type {
+	return reflect.TypeOf((*map[string]*ClusterPeering)(nil)).Elem()
+}
+
+func (i ClusterPeeringMap) ToClusterPeeringMapOutput() ClusterPeeringMapOutput {
+	return i.ToClusterPeeringMapOutputWithContext(context.Background())
+}
+
+func (i ClusterPeeringMap) ToClusterPeeringMapOutputWithContext(ctx context.Context) ClusterPeeringMapOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(ClusterPeeringMapOutput)
+}
+
+type ClusterPeeringOutput struct{ *pulumi.OutputState }
+
+func (ClusterPeeringOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*ClusterPeering)(nil))
+}
+
+func (o ClusterPeeringOutput) ToClusterPeeringOutput() ClusterPeeringOutput {
+	return o
+}
+
+func (o ClusterPeeringOutput) ToClusterPeeringOutputWithContext(ctx context.Context) ClusterPeeringOutput {
+	return o
+}
+
+func (o ClusterPeeringOutput) ToClusterPeeringPtrOutput() ClusterPeeringPtrOutput {
+	return o.ToClusterPeeringPtrOutputWithContext(context.Background())
+}
+
+func (o ClusterPeeringOutput) ToClusterPeeringPtrOutputWithContext(ctx context.Context) ClusterPeeringPtrOutput {
+	return o.ApplyTWithContext(ctx, func(_ context.Context, v ClusterPeering) *ClusterPeering {
+		return &v
+	}).(ClusterPeeringPtrOutput)
+}
+
+type ClusterPeeringPtrOutput struct{ *pulumi.OutputState }
+
+func (ClusterPeeringPtrOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((**ClusterPeering)(nil))
+}
+
+func (o ClusterPeeringPtrOutput) ToClusterPeeringPtrOutput() ClusterPeeringPtrOutput {
+	return o
+}
+
+func (o ClusterPeeringPtrOutput) ToClusterPeeringPtrOutputWithContext(ctx context.Context) ClusterPeeringPtrOutput {
+	return o
+}
+
+func (o ClusterPeeringPtrOutput) Elem() ClusterPeeringOutput {
+	return o.ApplyT(func(v *ClusterPeering) ClusterPeering {
+		if v != nil {
+			return *v
+		}
+		var ret ClusterPeering
+		return ret
+	}).(ClusterPeeringOutput)
+}
+
+type ClusterPeeringArrayOutput struct{ *pulumi.OutputState }
+
+func (ClusterPeeringArrayOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*[]ClusterPeering)(nil))
+}
+
+func (o ClusterPeeringArrayOutput) ToClusterPeeringArrayOutput() ClusterPeeringArrayOutput {
+	return o
+}
+
+func (o ClusterPeeringArrayOutput) ToClusterPeeringArrayOutputWithContext(ctx context.Context) ClusterPeeringArrayOutput {
+	return o
+}
+
+func (o ClusterPeeringArrayOutput) Index(i pulumi.IntInput) ClusterPeeringOutput {
+	return pulumi.All(o, i).ApplyT(func(vs []interface{}) ClusterPeering {
+		return vs[0].([]ClusterPeering)[vs[1].(int)]
+	}).(ClusterPeeringOutput)
+}
+
+type ClusterPeeringMapOutput struct{ *pulumi.OutputState }
+
+func (ClusterPeeringMapOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*map[string]ClusterPeering)(nil))
+}
+
+func (o ClusterPeeringMapOutput) ToClusterPeeringMapOutput() ClusterPeeringMapOutput {
+	return o
+}
+
+func (o ClusterPeeringMapOutput) ToClusterPeeringMapOutputWithContext(ctx context.Context) ClusterPeeringMapOutput {
+	return o
+}
+
+func (o ClusterPeeringMapOutput) MapIndex(k pulumi.StringInput) ClusterPeeringOutput {
+	return pulumi.All(o, k).ApplyT(func(vs []interface{}) ClusterPeering {
+		return vs[0].(map[string]ClusterPeering)[vs[1].(string)]
+	}).(ClusterPeeringOutput)
+}
+
+func init() {
+	pulumi.RegisterInputType(reflect.TypeOf((*ClusterPeeringInput)(nil)).Elem(), &ClusterPeering{})
+	pulumi.RegisterInputType(reflect.TypeOf((*ClusterPeeringPtrInput)(nil)).Elem(), &ClusterPeering{})
+	pulumi.RegisterInputType(reflect.TypeOf((*ClusterPeeringArrayInput)(nil)).Elem(), ClusterPeeringArray{})
+	pulumi.RegisterInputType(reflect.TypeOf((*ClusterPeeringMapInput)(nil)).Elem(), ClusterPeeringMap{})
+	pulumi.RegisterOutputType(ClusterPeeringOutput{})
+	pulumi.RegisterOutputType(ClusterPeeringPtrOutput{})
+	pulumi.RegisterOutputType(ClusterPeeringArrayOutput{})
+	pulumi.RegisterOutputType(ClusterPeeringMapOutput{})
+}
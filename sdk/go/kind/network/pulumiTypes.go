@@ -0,0 +1,276 @@
+// *** WARNING: this file was generated by pulumigen. ***
+// *** Do not edit by hand unless you're certain you know what you are doing! ***
+
+package network
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// cluster attached to the peering network
+type PeeredCluster struct {
+	// cluster name
+	Name string `pulumi:"name"`
+	// nodes of the cluster on the peering network
+	Nodes []PeeredNode `pulumi:"nodes"`
+	// pod subnet of the cluster, comma separated for dual stack clusters
+	PodSubnet string `pulumi:"podSubnet"`
+	// service subnet of the cluster, comma separated for dual stack clusters
+	ServiceSubnet string `pulumi:"serviceSubnet"`
+}
+
+// PeeredClusterInput is an input type that accepts PeeredClusterArgs and PeeredClusterOutput values.
+// You can construct a concrete instance of `PeeredClusterInput` via:
+//
+//          PeeredClusterArgs{...}
+type PeeredClusterInput interface {
+	pulumi.Input
+
+	ToPeeredClusterOutput() PeeredClusterOutput
+	ToPeeredClusterOutputWithContext(context.Context) PeeredClusterOutput
+}
+
+// cluster attached to the peering network
+type PeeredClusterArgs struct {
+	// cluster name
+	Name pulumi.StringInput `pulumi:"name"`
+	// nodes of the cluster on the peering network
+	Nodes PeeredNodeArrayInput `pulumi:"nodes"`
+	// pod subnet of the cluster, comma separated for dual stack clusters
+	PodSubnet pulumi.StringInput `pulumi:"podSubnet"`
+	// service subnet of the cluster, comma separated for dual stack clusters
+	ServiceSubnet pulumi.StringInput `pulumi:"serviceSubnet"`
+}
+
+func (PeeredClusterArgs) ElementType() reflect.Type {
+	return reflect.TypeOf((*PeeredCluster)(nil)).Elem()
+}
+
+func (i PeeredClusterArgs) ToPeeredClusterOutput() PeeredClusterOutput {
+	return i.ToPeeredClusterOutputWithContext(context.Background())
+}
+
+func (i PeeredClusterArgs) ToPeeredClusterOutputWithContext(ctx context.Context) PeeredClusterOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(PeeredClusterOutput)
+}
+
+// PeeredClusterArrayInput is an input type that accepts PeeredClusterArray and PeeredClusterArrayOutput values.
+// You can construct a concrete instance of `PeeredClusterArrayInput` via:
+//
+//          PeeredClusterArray{ PeeredClusterArgs{...} }
+type PeeredClusterArrayInput interface {
+	pulumi.Input
+
+	ToPeeredClusterArrayOutput() PeeredClusterArrayOutput
+	ToPeeredClusterArrayOutputWithContext(context.Context) PeeredClusterArrayOutput
+}
+
+type PeeredClusterArray []PeeredClusterInput
+
+func (PeeredClusterArray) ElementType() reflect.Type {
+	return reflect.TypeOf((*[]PeeredCluster)(nil)).Elem()
+}
+
+func (i PeeredClusterArray) ToPeeredClusterArrayOutput() PeeredClusterArrayOutput {
+	return i.ToPeeredClusterArrayOutputWithContext(context.Background())
+}
+
+func (i PeeredClusterArray) ToPeeredClusterArrayOutputWithContext(ctx context.Context) PeeredClusterArrayOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(PeeredClusterArrayOutput)
+}
+
+// cluster attached to the peering network
+type PeeredClusterOutput struct{ *pulumi.OutputState }
+
+func (PeeredClusterOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*PeeredCluster)(nil)).Elem()
+}
+
+func (o PeeredClusterOutput) ToPeeredClusterOutput() PeeredClusterOutput {
+	return o
+}
+
+func (o PeeredClusterOutput) ToPeeredClusterOutputWithContext(ctx context.Context) PeeredClusterOutput {
+	return o
+}
+
+// cluster name
+func (o PeeredClusterOutput) Name() pulumi.StringOutput {
+	return o.ApplyT(func(v PeeredCluster) string { return v.Name }).(pulumi.StringOutput)
+}
+
+// nodes of the cluster on the peering network
+func (o PeeredClusterOutput) Nodes() PeeredNodeArrayOutput {
+	return o.ApplyT(func(v PeeredCluster) []PeeredNode { return v.Nodes }).(PeeredNodeArrayOutput)
+}
+
+// pod subnet of the cluster, comma separated for dual stack clusters
+func (o PeeredClusterOutput) PodSubnet() pulumi.StringOutput {
+	return o.ApplyT(func(v PeeredCluster) string { return v.PodSubnet }).(pulumi.StringOutput)
+}
+
+// service subnet of the cluster, comma separated for dual stack clusters
+func (o PeeredClusterOutput) ServiceSubnet() pulumi.StringOutput {
+	return o.ApplyT(func(v PeeredCluster) string { return v.ServiceSubnet }).(pulumi.StringOutput)
+}
+
+type PeeredClusterArrayOutput struct{ *pulumi.OutputState }
+
+func (PeeredClusterArrayOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*[]PeeredCluster)(nil)).Elem()
+}
+
+func (o PeeredClusterArrayOutput) ToPeeredClusterArrayOutput() PeeredClusterArrayOutput {
+	return o
+}
+
+func (o PeeredClusterArrayOutput) ToPeeredClusterArrayOutputWithContext(ctx context.Context) PeeredClusterArrayOutput {
+	return o
+}
+
+func (o PeeredClusterArrayOutput) Index(i pulumi.IntInput) PeeredClusterOutput {
+	return pulumi.All(o, i).ApplyT(func(vs []interface{}) PeeredCluster {
+		return vs[0].([]PeeredCluster)[vs[1].(int)]
+	}).(PeeredClusterOutput)
+}
+
+// node attached to the peering network
+type PeeredNode struct {
+	// IPv4 address of the node on the peering network
+	Ipv4Address *string `pulumi:"ipv4Address"`
+	// IPv6 address of the node on the peering network
+	Ipv6Address *string `pulumi:"ipv6Address"`
+	// node container name
+	Name string `pulumi:"name"`
+	// pod CIDRs assigned to the node, routed via its address by the nodes of the other clusters
+	PodCidrs []string `pulumi:"podCidrs"`
+}
+
+// PeeredNodeInput is an input type that accepts PeeredNodeArgs and PeeredNodeOutput values.
+// You can construct a concrete instance of `PeeredNodeInput` via:
+//
+//          PeeredNodeArgs{...}
+type PeeredNodeInput interface {
+	pulumi.Input
+
+	ToPeeredNodeOutput() PeeredNodeOutput
+	ToPeeredNodeOutputWithContext(context.Context) PeeredNodeOutput
+}
+
+// node attached to the peering network
+type PeeredNodeArgs struct {
+	// IPv4 address of the node on the peering network
+	Ipv4Address pulumi.StringPtrInput `pulumi:"ipv4Address"`
+	// IPv6 address of the node on the peering network
+	Ipv6Address pulumi.StringPtrInput `pulumi:"ipv6Address"`
+	// node container name
+	Name pulumi.StringInput `pulumi:"name"`
+	// pod CIDRs assigned to the node, routed via its address by the nodes of the other clusters
+	PodCidrs pulumi.StringArrayInput `pulumi:"podCidrs"`
+}
+
+func (PeeredNodeArgs) ElementType() reflect.Type {
+	return reflect.TypeOf((*PeeredNode)(nil)).Elem()
+}
+
+func (i PeeredNodeArgs) ToPeeredNodeOutput() PeeredNodeOutput {
+	return i.ToPeeredNodeOutputWithContext(context.Background())
+}
+
+func (i PeeredNodeArgs) ToPeeredNodeOutputWithContext(ctx context.Context) PeeredNodeOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(PeeredNodeOutput)
+}
+
+// PeeredNodeArrayInput is an input type that accepts PeeredNodeArray and PeeredNodeArrayOutput values.
+// You can construct a concrete instance of `PeeredNodeArrayInput` via:
+//
+//          PeeredNodeArray{ PeeredNodeArgs{...} }
+type PeeredNodeArrayInput interface {
+	pulumi.Input
+
+	ToPeeredNodeArrayOutput() PeeredNodeArrayOutput
+	ToPeeredNodeArrayOutputWithContext(context.Context) PeeredNodeArrayOutput
+}
+
+type PeeredNodeArray []PeeredNodeInput
+
+func (PeeredNodeArray) ElementType() reflect.Type {
+	return reflect.TypeOf((*[]PeeredNode)(nil)).Elem()
+}
+
+func (i PeeredNodeArray) ToPeeredNodeArrayOutput() PeeredNodeArrayOutput {
+	return i.ToPeeredNodeArrayOutputWithContext(context.Background())
+}
+
+func (i PeeredNodeArray) ToPeeredNodeArrayOutputWithContext(ctx context.Context) PeeredNodeArrayOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(PeeredNodeArrayOutput)
+}
+
+// node attached to the peering network
+type PeeredNodeOutput struct{ *pulumi.OutputState }
+
+func (PeeredNodeOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*PeeredNode)(nil)).Elem()
+}
+
+func (o PeeredNodeOutput) ToPeeredNodeOutput() PeeredNodeOutput {
+	return o
+}
+
+func (o PeeredNodeOutput) ToPeeredNodeOutputWithContext(ctx context.Context) PeeredNodeOutput {
+	return o
+}
+
+// IPv4 address of the node on the peering network
+func (o PeeredNodeOutput) Ipv4Address() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v PeeredNode) *string { return v.Ipv4Address }).(pulumi.StringPtrOutput)
+}
+
+// IPv6 address of the node on the peering network
+func (o PeeredNodeOutput) Ipv6Address() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v PeeredNode) *string { return v.Ipv6Address }).(pulumi.StringPtrOutput)
+}
+
+// node container name
+func (o PeeredNodeOutput) Name() pulumi.StringOutput {
+	return o.ApplyT(func(v PeeredNode) string { return v.Name }).(pulumi.StringOutput)
+}
+
+// pod CIDRs assigned to the node, routed via its address by the nodes of the other clusters
+func (o PeeredNodeOutput) PodCidrs() pulumi.StringArrayOutput {
+	return o.ApplyT(func(v PeeredNode) []string { return v.PodCidrs }).(pulumi.StringArrayOutput)
+}
+
+type PeeredNodeArrayOutput struct{ *pulumi.OutputState }
+
+func (PeeredNodeArrayOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*[]PeeredNode)(nil)).Elem()
+}
+
+func (o PeeredNodeArrayOutput) ToPeeredNodeArrayOutput() PeeredNodeArrayOutput {
+	return o
+}
+
+func (o PeeredNodeArrayOutput) ToPeeredNodeArrayOutputWithContext(ctx context.Context) PeeredNodeArrayOutput {
+	return o
+}
+
+func (o PeeredNodeArrayOutput) Index(i pulumi.IntInput) PeeredNodeOutput {
+	return pulumi.All(o, i).ApplyT(func(vs []interface{}) PeeredNode {
+		return vs[0].([]PeeredNode)[vs[1].(int)]
+	}).(PeeredNodeOutput)
+}
+
+func init() {
+	pulumi.RegisterInputType(reflect.TypeOf((*PeeredClusterInput)(nil)).Elem(), PeeredClusterArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*PeeredClusterArrayInput)(nil)).Elem(), PeeredClusterArray{})
+	pulumi.RegisterInputType(reflect.TypeOf((*PeeredNodeInput)(nil)).Elem(), PeeredNodeArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*PeeredNodeArrayInput)(nil)).Elem(), PeeredNodeArray{})
+	pulumi.RegisterOutputType(PeeredClusterOutput{})
+	pulumi.RegisterOutputType(PeeredClusterArrayOutput{})
+	pulumi.RegisterOutputType(PeeredNodeOutput{})
+	pulumi.RegisterOutputType(PeeredNodeArrayOutput{})
+}
@@ -13,6 +13,241 @@ import (
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
 )
 
+// kubelet settings of a node
+type Kubelet struct {
+	// hard eviction thresholds by signal like memory.available, as quantities like 100Mi or percentages like 10%
+	EvictionHard map[string]string `pulumi:"evictionHard"`
+	// soft eviction thresholds by signal, as quantities or percentages
+	EvictionSoft map[string]string `pulumi:"evictionSoft"`
+	// grace periods of the soft eviction thresholds by signal, as durations like 1m30s
+	EvictionSoftGracePeriod map[string]string `pulumi:"evictionSoftGracePeriod"`
+	// kubelet flags without the leading dashes, overriding the flags rendered from the other settings
+	ExtraArgs map[string]string `pulumi:"extraArgs"`
+	// maximum number of pods on the node
+	MaxPods *int `pulumi:"maxPods"`
+	// labels the node registers with, conflicts with the labels of the node
+	NodeLabels map[string]string `pulumi:"nodeLabels"`
+}
+
+// KubeletInput is an input type that accepts KubeletArgs and KubeletOutput values.
+// You can construct a concrete instance of `KubeletInput` via:
+//
+//          KubeletArgs{...}
+type KubeletInput interface {
+	pulumi.Input
+
+	ToKubeletOutput() KubeletOutput
+	ToKubeletOutputWithContext(context.Context) KubeletOutput
+}
+
+// kubelet settings of a node
+type KubeletArgs struct {
+	// hard eviction thresholds by signal like memory.available, as quantities like 100Mi or percentages like 10%
+	EvictionHard pulumi.StringMapInput `pulumi:"evictionHard"`
+	// soft eviction thresholds by signal, as quantities or percentages
+	EvictionSoft pulumi.StringMapInput `pulumi:"evictionSoft"`
+	// grace periods of the soft eviction thresholds by signal, as durations like 1m30s
+	EvictionSoftGracePeriod pulumi.StringMapInput `pulumi:"evictionSoftGracePeriod"`
+	// kubelet flags without the leading dashes, overriding the flags rendered from the other settings
+	ExtraArgs pulumi.StringMapInput `pulumi:"extraArgs"`
+	// maximum number of pods on the node
+	MaxPods pulumi.IntPtrInput `pulumi:"maxPods"`
+	// labels the node registers with, conflicts with the labels of the node
+	NodeLabels pulumi.StringMapInput `pulumi:"nodeLabels"`
+}
+
+func (KubeletArgs) ElementType() reflect.Type {
+	return reflect.TypeOf((*Kubelet)(nil)).Elem()
+}
+
+func (i KubeletArgs) ToKubeletOutput() KubeletOutput {
+	return i.ToKubeletOutputWithContext(context.Background())
+}
+
+func (i KubeletArgs) ToKubeletOutputWithContext(ctx context.Context) KubeletOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(KubeletOutput)
+}
+
+func (i KubeletArgs) ToKubeletPtrOutput() KubeletPtrOutput {
+	return i.ToKubeletPtrOutputWithContext(context.Background())
+}
+
+func (i KubeletArgs) ToKubeletPtrOutputWithContext(ctx context.Context) KubeletPtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(KubeletOutput).ToKubeletPtrOutputWithContext(ctx)
+}
+
+// KubeletPtrInput is an input type that accepts KubeletArgs, KubeletPtr and KubeletPtrOutput values.
+// You can construct a concrete instance of `KubeletPtrInput` via:
+//
+//          KubeletArgs{...}
+//
+//  or:
+//
+//          nil
+type KubeletPtrInput interface {
+	pulumi.Input
+
+	ToKubeletPtrOutput() KubeletPtrOutput
+	ToKubeletPtrOutputWithContext(context.Context) KubeletPtrOutput
+}
+
+type kubeletPtrType KubeletArgs
+
+func KubeletPtr(v *KubeletArgs) KubeletPtrInput {
+	return (*kubeletPtrType)(v)
+}
+
+func (*kubeletPtrType) ElementType() reflect.Type {
+	return reflect.TypeOf((**Kubelet)(nil)).Elem()
+}
+
+func (i *kubeletPtrType) ToKubeletPtrOutput() KubeletPtrOutput {
+	return i.ToKubeletPtrOutputWithContext(context.Background())
+}
+
+func (i *kubeletPtrType) ToKubeletPtrOutputWithContext(ctx context.Context) KubeletPtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(KubeletPtrOutput)
+}
+
+// kubelet settings of a node
+type KubeletOutput struct{ *pulumi.OutputState }
+
+func (KubeletOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*Kubelet)(nil)).Elem()
+}
+
+func (o KubeletOutput) ToKubeletOutput() KubeletOutput {
+	return o
+}
+
+func (o KubeletOutput) ToKubeletOutputWithContext(ctx context.Context) KubeletOutput {
+	return o
+}
+
+func (o KubeletOutput) ToKubeletPtrOutput() KubeletPtrOutput {
+	return o.ToKubeletPtrOutputWithContext(context.Background())
+}
+
+func (o KubeletOutput) ToKubeletPtrOutputWithContext(ctx context.Context) KubeletPtrOutput {
+	return o.ApplyTWithContext(ctx, func(_ context.Context, v Kubelet) *Kubelet {
+		return &v
+	}).(KubeletPtrOutput)
+}
+
+// hard eviction thresholds by signal like memory.available, as quantities like 100Mi or percentages like 10%
+func (o KubeletOutput) EvictionHard() pulumi.StringMapOutput {
+	return o.ApplyT(func(v Kubelet) map[string]string { return v.EvictionHard }).(pulumi.StringMapOutput)
+}
+
+// soft eviction thresholds by signal, as quantities or percentages
+func (o KubeletOutput) EvictionSoft() pulumi.StringMapOutput {
+	return o.ApplyT(func(v Kubelet) map[string]string { return v.EvictionSoft }).(pulumi.StringMapOutput)
+}
+
+// grace periods of the soft eviction thresholds by signal, as durations like 1m30s
+func (o KubeletOutput) EvictionSoftGracePeriod() pulumi.StringMapOutput {
+	return o.ApplyT(func(v Kubelet) map[string]string { return v.EvictionSoftGracePeriod }).(pulumi.StringMapOutput)
+}
+
+// kubelet flags without the leading dashes, overriding the flags rendered from the other settings
+func (o KubeletOutput) ExtraArgs() pulumi.StringMapOutput {
+	return o.ApplyT(func(v Kubelet) map[string]string { return v.ExtraArgs }).(pulumi.StringMapOutput)
+}
+
+// maximum number of pods on the node
+func (o KubeletOutput) MaxPods() pulumi.IntPtrOutput {
+	return o.ApplyT(func(v Kubelet) *int { return v.MaxPods }).(pulumi.IntPtrOutput)
+}
+
+// labels the node registers with, conflicts with the labels of the node
+func (o KubeletOutput) NodeLabels() pulumi.StringMapOutput {
+	return o.ApplyT(func(v Kubelet) map[string]string { return v.NodeLabels }).(pulumi.StringMapOutput)
+}
+
+type KubeletPtrOutput struct{ *pulumi.OutputState }
+
+func (KubeletPtrOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((**Kubelet)(nil)).Elem()
+}
+
+func (o KubeletPtrOutput) ToKubeletPtrOutput() KubeletPtrOutput {
+	return o
+}
+
+func (o KubeletPtrOutput) ToKubeletPtrOutputWithContext(ctx context.Context) KubeletPtrOutput {
+	return o
+}
+
+func (o KubeletPtrOutput) Elem() KubeletOutput {
+	return o.ApplyT(func(v *Kubelet) Kubelet {
+		if v != nil {
+			return *v
+		}
+		var ret Kubelet
+		return ret
+	}).(KubeletOutput)
+}
+
+// hard eviction thresholds by signal like memory.available, as quantities like 100Mi or percentages like 10%
+func (o KubeletPtrOutput) EvictionHard() pulumi.StringMapOutput {
+	return o.ApplyT(func(v *Kubelet) map[string]string {
+		if v == nil {
+			return nil
+		}
+		return v.EvictionHard
+	}).(pulumi.StringMapOutput)
+}
+
+// soft eviction thresholds by signal, as quantities or percentages
+func (o KubeletPtrOutput) EvictionSoft() pulumi.StringMapOutput {
+	return o.ApplyT(func(v *Kubelet) map[string]string {
+		if v == nil {
+			return nil
+		}
+		return v.EvictionSoft
+	}).(pulumi.StringMapOutput)
+}
+
+// grace periods of the soft eviction thresholds by signal, as durations like 1m30s
+func (o KubeletPtrOutput) EvictionSoftGracePeriod() pulumi.StringMapOutput {
+	return o.ApplyT(func(v *Kubelet) map[string]string {
+		if v == nil {
+			return nil
+		}
+		return v.EvictionSoftGracePeriod
+	}).(pulumi.StringMapOutput)
+}
+
+// kubelet flags without the leading dashes, overriding the flags rendered from the other settings
+func (o KubeletPtrOutput) ExtraArgs() pulumi.StringMapOutput {
+	return o.ApplyT(func(v *Kubelet) map[string]string {
+		if v == nil {
+			return nil
+		}
+		return v.ExtraArgs
+	}).(pulumi.StringMapOutput)
+}
+
+// maximum number of pods on the node
+func (o KubeletPtrOutput) MaxPods() pulumi.IntPtrOutput {
+	return o.ApplyT(func(v *Kubelet) *int {
+		if v == nil {
+			return nil
+		}
+		return v.MaxPods
+	}).(pulumi.IntPtrOutput)
+}
+
+// labels the node registers with, conflicts with the labels of the node
+func (o KubeletPtrOutput) NodeLabels() pulumi.StringMapOutput {
+	return o.ApplyT(func(v *Kubelet) map[string]string {
+		if v == nil {
+			return nil
+		}
+		return v.NodeLabels
+	}).(pulumi.StringMapOutput)
+}
+
 // KIND Node type
 type Node struct {
 	ExtraMounts                  []mount.Mount                 `pulumi:"extraMounts"`
@@ -20,9 +255,15 @@ type Node struct {
 	Image                        *string                       `pulumi:"image"`
 	KubeadmConfigPatches         []string                      `pulumi:"kubeadmConfigPatches"`
 	KubeadmConfigPatchesJSON6902 []patchjson6902.PatchJSON6902 `pulumi:"kubeadmConfigPatchesJSON6902"`
-	Labels                       map[string]string             `pulumi:"labels"`
+	// kubelet settings of the node, rendered into the kubeadm config of the node for its role when it joins the cluster
+	Kubelet *Kubelet `pulumi:"kubelet"`
+	// Kubernetes version like 1.21 or v1.21.1 of the node, resolved to the node image pinned by digest for the KIND release of the provider. Conflicts with image
+	KubernetesVersion *string           `pulumi:"kubernetesVersion"`
+	Labels            map[string]string `pulumi:"labels"`
 	// node role type
 	Role *string `pulumi:"role"`
+	// taints the node registers with, on top of the taint kubeadm gives control plane nodes
+	Taints []Taint `pulumi:"taints"`
 }
 
 // NodeInput is an input type that accepts NodeArgs and NodeOutput values.
@@ -43,9 +284,15 @@ type NodeArgs struct {
 	Image                        pulumi.StringPtrInput                 `pulumi:"image"`
 	KubeadmConfigPatches         pulumi.StringArrayInput               `pulumi:"kubeadmConfigPatches"`
 	KubeadmConfigPatchesJSON6902 patchjson6902.PatchJSON6902ArrayInput `pulumi:"kubeadmConfigPatchesJSON6902"`
-	Labels                       pulumi.StringMapInput                 `pulumi:"labels"`
+	// kubelet settings of the node, rendered into the kubeadm config of the node for its role when it joins the cluster
+	Kubelet KubeletPtrInput `pulumi:"kubelet"`
+	// Kubernetes version like 1.21 or v1.21.1 of the node, resolved to the node image pinned by digest for the KIND release of the provider. Conflicts with image
+	KubernetesVersion pulumi.StringPtrInput `pulumi:"kubernetesVersion"`
+	Labels            pulumi.StringMapInput `pulumi:"labels"`
 	// node role type
 	Role pulumi.StringPtrInput `pulumi:"role"`
+	// taints the node registers with, on top of the taint kubeadm gives control plane nodes
+	Taints TaintArrayInput `pulumi:"taints"`
 }
 
 func (NodeArgs) ElementType() reflect.Type {
@@ -120,6 +367,16 @@ func (o NodeOutput) KubeadmConfigPatchesJSON6902() patchjson6902.PatchJSON6902Ar
 	return o.ApplyT(func(v Node) []patchjson6902.PatchJSON6902 { return v.KubeadmConfigPatchesJSON6902 }).(patchjson6902.PatchJSON6902ArrayOutput)
 }
 
+// kubelet settings of the node, rendered into the kubeadm config of the node for its role when it joins the cluster
+func (o NodeOutput) Kubelet() KubeletPtrOutput {
+	return o.ApplyT(func(v Node) *Kubelet { return v.Kubelet }).(KubeletPtrOutput)
+}
+
+// Kubernetes version like 1.21 or v1.21.1 of the node, resolved to the node image pinned by digest for the KIND release of the provider. Conflicts with image
+func (o NodeOutput) KubernetesVersion() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v Node) *string { return v.KubernetesVersion }).(pulumi.StringPtrOutput)
+}
+
 func (o NodeOutput) Labels() pulumi.StringMapOutput {
 	return o.ApplyT(func(v Node) map[string]string { return v.Labels }).(pulumi.StringMapOutput)
 }
@@ -129,6 +386,11 @@ func (o NodeOutput) Role() pulumi.StringPtrOutput {
 	return o.ApplyT(func(v Node) *string { return v.Role }).(pulumi.StringPtrOutput)
 }
 
+// taints the node registers with, on top of the taint kubeadm gives control plane nodes
+func (o NodeOutput) Taints() TaintArrayOutput {
+	return o.ApplyT(func(v Node) []Taint { return v.Taints }).(TaintArrayOutput)
+}
+
 type NodeArrayOutput struct{ *pulumi.OutputState }
 
 func (NodeArrayOutput) ElementType() reflect.Type {
@@ -149,9 +411,135 @@ func (o NodeArrayOutput) Index(i pulumi.IntInput) NodeOutput {
 	}).(NodeOutput)
 }
 
+// taint of a node
+type Taint struct {
+	// NoSchedule, PreferNoSchedule or NoExecute
+	Effect string `pulumi:"effect"`
+	// taint key
+	Key string `pulumi:"key"`
+	// taint value
+	Value *string `pulumi:"value"`
+}
+
+// TaintInput is an input type that accepts TaintArgs and TaintOutput values.
+// You can construct a concrete instance of `TaintInput` via:
+//
+//          TaintArgs{...}
+type TaintInput interface {
+	pulumi.Input
+
+	ToTaintOutput() TaintOutput
+	ToTaintOutputWithContext(context.Context) TaintOutput
+}
+
+// taint of a node
+type TaintArgs struct {
+	// NoSchedule, PreferNoSchedule or NoExecute
+	Effect pulumi.StringInput `pulumi:"effect"`
+	// taint key
+	Key pulumi.StringInput `pulumi:"key"`
+	// taint value
+	Value pulumi.StringPtrInput `pulumi:"value"`
+}
+
+func (TaintArgs) ElementType() reflect.Type {
+	return reflect.TypeOf((*Taint)(nil)).Elem()
+}
+
+func (i TaintArgs) ToTaintOutput() TaintOutput {
+	return i.ToTaintOutputWithContext(context.Background())
+}
+
+func (i TaintArgs) ToTaintOutputWithContext(ctx context.Context) TaintOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(TaintOutput)
+}
+
+// TaintArrayInput is an input type that accepts TaintArray and TaintArrayOutput values.
+// You can construct a concrete instance of `TaintArrayInput` via:
+//
+//          TaintArray{ TaintArgs{...} }
+type TaintArrayInput interface {
+	pulumi.Input
+
+	ToTaintArrayOutput() TaintArrayOutput
+	ToTaintArrayOutputWithContext(context.Context) TaintArrayOutput
+}
+
+type TaintArray []TaintInput
+
+func (TaintArray) ElementType() reflect.Type {
+	return reflect.TypeOf((*[]Taint)(nil)).Elem()
+}
+
+func (i TaintArray) ToTaintArrayOutput() TaintArrayOutput {
+	return i.ToTaintArrayOutputWithContext(context.Background())
+}
+
+func (i TaintArray) ToTaintArrayOutputWithContext(ctx context.Context) TaintArrayOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(TaintArrayOutput)
+}
+
+// taint of a node
+type TaintOutput struct{ *pulumi.OutputState }
+
+func (TaintOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*Taint)(nil)).Elem()
+}
+
+func (o TaintOutput) ToTaintOutput() TaintOutput {
+	return o
+}
+
+func (o TaintOutput) ToTaintOutputWithContext(ctx context.Context) TaintOutput {
+	return o
+}
+
+// NoSchedule, PreferNoSchedule or NoExecute
+func (o TaintOutput) Effect() pulumi.StringOutput {
+	return o.ApplyT(func(v Taint) string { return v.Effect }).(pulumi.StringOutput)
+}
+
+// taint key
+func (o TaintOutput) Key() pulumi.StringOutput {
+	return o.ApplyT(func(v Taint) string { return v.Key }).(pulumi.StringOutput)
+}
+
+// taint value
+func (o TaintOutput) Value() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v Taint) *string { return v.Value }).(pulumi.StringPtrOutput)
+}
+
+type TaintArrayOutput struct{ *pulumi.OutputState }
+
+func (TaintArrayOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*[]Taint)(nil)).Elem()
+}
+
+func (o TaintArrayOutput) ToTaintArrayOutput() TaintArrayOutput {
+	return o
+}
+
+func (o TaintArrayOutput) ToTaintArrayOutputWithContext(ctx context.Context) TaintArrayOutput {
+	return o
+}
+
+func (o TaintArrayOutput) Index(i pulumi.IntInput) TaintOutput {
+	return pulumi.All(o, i).ApplyT(func(vs []interface{}) Taint {
+		return vs[0].([]Taint)[vs[1].(int)]
+	}).(TaintOutput)
+}
+
 func init() {
+	pulumi.RegisterInputType(reflect.TypeOf((*KubeletInput)(nil)).Elem(), KubeletArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*KubeletPtrInput)(nil)).Elem(), KubeletArgs{})
 	pulumi.RegisterInputType(reflect.TypeOf((*NodeInput)(nil)).Elem(), NodeArgs{})
 	pulumi.RegisterInputType(reflect.TypeOf((*NodeArrayInput)(nil)).Elem(), NodeArray{})
+	pulumi.RegisterInputType(reflect.TypeOf((*TaintInput)(nil)).Elem(), TaintArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*TaintArrayInput)(nil)).Elem(), TaintArray{})
+	pulumi.RegisterOutputType(KubeletOutput{})
+	pulumi.RegisterOutputType(KubeletPtrOutput{})
 	pulumi.RegisterOutputType(NodeOutput{})
 	pulumi.RegisterOutputType(NodeArrayOutput{})
+	pulumi.RegisterOutputType(TaintOutput{})
+	pulumi.RegisterOutputType(TaintArrayOutput{})
 }
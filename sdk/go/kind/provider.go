@@ -31,38 +31,58 @@ func NewProvider(ctx *pulumi.Context,
 }
 
 type providerArgs struct {
+	// Derive the suffix of generated cluster names from the stack and resource name instead of picking it at random. Default: false. Optional
+	AutonameDeterministic *bool `pulumi:"autonameDeterministic"`
+	// Maximum length of generated cluster names, longer resource names are truncated. Default: 40. Maximum: 50. Optional
+	AutonameMaxLength *int `pulumi:"autonameMaxLength"`
+	// Prefix to add to generated cluster names. Optional
+	AutonamePrefix *string `pulumi:"autonamePrefix"`
+	// Length of the random suffix added to generated cluster names, 0 disables the suffix. Default: 8. Optional
+	AutonameSuffixLength *int `pulumi:"autonameSuffixLength"`
 	// Kind config file to use. Optional
 	ConfigFile *string `pulumi:"configFile"`
 	// File to save generated kubeconfig. Default: not set. Optional
 	KubeconfigFile *string `pulumi:"kubeconfigFile"`
 	// Node image to use. Optional
 	NodeImage *string `pulumi:"nodeImage"`
+	// Never pull images. Node and registry images that are not available locally fail the preview instead. Default: false. Optional
+	Offline *bool `pulumi:"offline"`
 	// Provider to use. Supports docker/podman. Default: docker. Optional
 	Provider *string `pulumi:"provider"`
 	// Whether to retain the nodes when creation fails. Needs manual cleanup when set to true Default: false. Optional
 	RetainNodesOnFailure *bool `pulumi:"retainNodesOnFailure"`
 	// Stop before running kubeadm commands. This would need the user to manually retrieve the Kubeconfig. Default: false. Optional
 	StopBeforeSettingK8s *bool `pulumi:"stopBeforeSettingK8s"`
-	// Time in seconds to wait for nodes to become ready. Default: none. Optional
-	WaitForNodeReady *int `pulumi:"waitForNodeReady"`
+	// Time to wait for nodes to become ready, either in seconds or as a duration like 90s or 5m. Default: none. Optional
+	WaitForNodeReady interface{} `pulumi:"waitForNodeReady"`
 }
 
 // The set of arguments for constructing a Provider resource.
 type ProviderArgs struct {
+	// Derive the suffix of generated cluster names from the stack and resource name instead of picking it at random. Default: false. Optional
+	AutonameDeterministic pulumi.BoolPtrInput
+	// Maximum length of generated cluster names, longer resource names are truncated. Default: 40. Maximum: 50. Optional
+	AutonameMaxLength pulumi.IntPtrInput
+	// Prefix to add to generated cluster names. Optional
+	AutonamePrefix pulumi.StringPtrInput
+	// Length of the random suffix added to generated cluster names, 0 disables the suffix. Default: 8. Optional
+	AutonameSuffixLength pulumi.IntPtrInput
 	// Kind config file to use. Optional
 	ConfigFile pulumi.StringPtrInput
 	// File to save generated kubeconfig. Default: not set. Optional
 	KubeconfigFile pulumi.StringPtrInput
 	// Node image to use. Optional
 	NodeImage pulumi.StringPtrInput
+	// Never pull images. Node and registry images that are not available locally fail the preview instead. Default: false. Optional
+	Offline pulumi.BoolPtrInput
 	// Provider to use. Supports docker/podman. Default: docker. Optional
 	Provider pulumi.StringPtrInput
 	// Whether to retain the nodes when creation fails. Needs manual cleanup when set to true Default: false. Optional
 	RetainNodesOnFailure pulumi.BoolPtrInput
 	// Stop before running kubeadm commands. This would need the user to manually retrieve the Kubeconfig. Default: false. Optional
 	StopBeforeSettingK8s pulumi.BoolPtrInput
-	// Time in seconds to wait for nodes to become ready. Default: none. Optional
-	WaitForNodeReady pulumi.IntPtrInput
+	// Time to wait for nodes to become ready, either in seconds or as a duration like 90s or 5m. Default: none. Optional
+	WaitForNodeReady pulumi.Input
 }
 
 func (ProviderArgs) ElementType() reflect.Type {
@@ -0,0 +1,305 @@
+// *** WARNING: this file was generated by pulumigen. ***
+// *** Do not edit by hand unless you're certain you know what you are doing! ***
+
+package registry
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// Registry container on the KIND network that the nodes of KIND clusters pull the images of its host address from
+type LocalRegistry struct {
+	pulumi.CustomResourceState
+
+	// host:port of the registry on the KIND network
+	ClusterAddress pulumi.StringOutput `pulumi:"clusterAddress"`
+	// names of the clusters using the registry
+	ClusterNames pulumi.StringArrayOutput `pulumi:"clusterNames"`
+	// names of the clusters the registry is configured in
+	ConfiguredClusters pulumi.StringArrayOutput `pulumi:"configuredClusters"`
+	// host:port of the registry on the host, images tagged with it are pulled from the registry by the nodes
+	HostAddress pulumi.StringOutput `pulumi:"hostAddress"`
+	// host port the registry is published on, 0 for a random port
+	HostPort pulumi.IntOutput `pulumi:"hostPort"`
+	// registry image
+	Image pulumi.StringOutput `pulumi:"image"`
+	// host address the registry is published on
+	ListenAddress pulumi.StringOutput `pulumi:"listenAddress"`
+	// registry container name
+	Name pulumi.StringOutput `pulumi:"name"`
+	// docker/podman network the registry joins
+	Network pulumi.StringOutput `pulumi:"network"`
+}
+
+// NewLocalRegistry registers a new resource with the given unique name, arguments, and options.
+func NewLocalRegistry(ctx *pulumi.Context,
+	name string, args *LocalRegistryArgs, opts ...pulumi.ResourceOption) (*LocalRegistry, error) {
+	if args == nil {
+		args = &LocalRegistryArgs{}
+	}
+
+	var resource LocalRegistry
+	err := ctx.RegisterResource("kind:registry:LocalRegistry", name, args, &resource, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &resource, nil
+}
+
+// GetLocalRegistry gets an existing LocalRegistry resource's state with the given name, ID, and optional
+// state properties that are used to uniquely qualify the lookup (nil if not required).
+func GetLocalRegistry(ctx *pulumi.Context,
+	name string, id pulumi.IDInput, state *LocalRegistryState, opts ...pulumi.ResourceOption) (*LocalRegistry, error) {
+	var resource LocalRegistry
+	err := ctx.ReadResource("kind:registry:LocalRegistry", name, id, state, &resource, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &resource, nil
+}
+
+// Input properties used for looking up and filtering LocalRegistry resources.
+type localRegistryState struct {
+}
+
+type LocalRegistryState struct {
+}
+
+func (LocalRegistryState) ElementType() reflect.Type {
+	return reflect.TypeOf((*localRegistryState)(nil)).Elem()
+}
+
+type localRegistryArgs struct {
+	// Names of the KIND clusters whose nodes pull the images of the host address from the registry, configured with a containerd mirror and the local-registry-hosting ConfigMap
+	ClusterNames []string `pulumi:"clusterNames"`
+	// Host port to publish the registry on, 0 for a random port. Default: 5001
+	HostPort *int `pulumi:"hostPort"`
+	// Registry image. Default: registry:2
+	Image *string `pulumi:"image"`
+	// Host address to publish the registry on. Default: 127.0.0.1
+	ListenAddress *string `pulumi:"listenAddress"`
+	// Name of the registry container. Default: kind-registry
+	Name *string `pulumi:"name"`
+	// docker/podman network of the KIND clusters, which has to exist. Default: kind, or KIND_EXPERIMENTAL_DOCKER_NETWORK when set
+	Network *string `pulumi:"network"`
+}
+
+// The set of arguments for constructing a LocalRegistry resource.
+type LocalRegistryArgs struct {
+	// Names of the KIND clusters whose nodes pull the images of the host address from the registry, configured with a containerd mirror and the local-registry-hosting ConfigMap
+	ClusterNames pulumi.StringArrayInput
+	// Host port to publish the registry on, 0 for a random port. Default: 5001
+	HostPort pulumi.IntPtrInput
+	// Registry image. Default: registry:2
+	Image pulumi.StringPtrInput
+	// Host address to publish the registry on. Default: 127.0.0.1
+	ListenAddress pulumi.StringPtrInput
+	// Name of the registry container. Default: kind-registry
+	Name pulumi.StringPtrInput
+	// docker/podman network of the KIND clusters, which has to exist. Default: kind, or KIND_EXPERIMENTAL_DOCKER_NETWORK when set
+	Network pulumi.StringPtrInput
+}
+
+func (LocalRegistryArgs) ElementType() reflect.Type {
+	return reflect.TypeOf((*localRegistryArgs)(nil)).Elem()
+}
+
+type LocalRegistryInput interface {
+	pulumi.Input
+
+	ToLocalRegistryOutput() LocalRegistryOutput
+	ToLocalRegistryOutputWithContext(ctx context.Context) LocalRegistryOutput
+}
+
+func (*LocalRegistry) ElementType() reflect.Type {
+	return reflect.TypeOf((*LocalRegistry)(nil))
+}
+
+func (i *LocalRegistry) ToLocalRegistryOutput() LocalRegistryOutput {
+	return i.ToLocalRegistryOutputWithContext(context.Background())
+}
+
+func (i *LocalRegistry) ToLocalRegistryOutputWithContext(ctx context.Context) LocalRegistryOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(LocalRegistryOutput)
+}
+
+func (i *LocalRegistry) ToLocalRegistryPtrOutput() LocalRegistryPtrOutput {
+	return i.ToLocalRegistryPtrOutputWithContext(context.Background())
+}
+
+func (i *LocalRegistry) ToLocalRegistryPtrOutputWithContext(ctx context.Context) LocalRegistryPtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(LocalRegistryPtrOutput)
+}
+
+type LocalRegistryPtrInput interface {
+	pulumi.Input
+
+	ToLocalRegistryPtrOutput() LocalRegistryPtrOutput
+	ToLocalRegistryPtrOutputWithContext(ctx context.Context) LocalRegistryPtrOutput
+}
+
+type localRegistryPtrType LocalRegistryArgs
+
+func (*localRegistryPtrType) ElementType() reflect.Type {
+	return reflect.TypeOf((**LocalRegistry)(nil))
+}
+
+func (i *localRegistryPtrType) ToLocalRegistryPtrOutput() LocalRegistryPtrOutput {
+	return i.ToLocalRegistryPtrOutputWithContext(context.Background())
+}
+
+func (i *localRegistryPtrType) ToLocalRegistryPtrOutputWithContext(ctx context.Context) LocalRegistryPtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(LocalRegistryPtrOutput)
+}
+
+// LocalRegistryArrayInput is an input type that accepts LocalRegistryArray and LocalRegistryArrayOutput values.
+// You can construct a concrete instance of `LocalRegistryArrayInput` via:
+//
+//          LocalRegistryArray{ LocalRegistryArgs{...} }
+type LocalRegistryArrayInput interface {
+	pulumi.Input
+
+	ToLocalRegistryArrayOutput() LocalRegistryArrayOutput
+	ToLocalRegistryArrayOutputWithContext(context.Context) LocalRegistryArrayOutput
+}
+
+type LocalRegistryArray []LocalRegistryInput
+
+func (LocalRegistryArray) ElementType() reflect.Type {
+	return reflect.TypeOf((*[]*LocalRegistry)(nil)).Elem()
+}
+
+func (i LocalRegistryArray) ToLocalRegistryArrayOutput() LocalRegistryArrayOutput {
+	return i.ToLocalRegistryArrayOutputWithContext(context.Background())
+}
+
+func (i LocalRegistryArray) ToLocalRegistryArrayOutputWithContext(ctx context.Context) LocalRegistryArrayOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(LocalRegistryArrayOutput)
+}
+
+// LocalRegistryMapInput is an input type that accepts LocalRegistryMap and LocalRegistryMapOutput values.
+// You can construct a concrete instance of `LocalRegistryMapInput` via:
+//
+//          LocalRegistryMap{ "key": LocalRegistryArgs{...} }
+type LocalRegistryMapInput interface {
+	pulumi.Input
+
+	ToLocalRegistryMapOutput() LocalRegistryMapOutput
+	ToLocalRegistryMapOutputWithContext(context.Context) LocalRegistryMapOutput
+}
+
+type LocalRegistryMap map[string]LocalRegistryInput
+
+func (LocalRegistryMap) ElementType() reflect.Type {
+	return reflect.TypeOf((*map[string]*LocalRegistry)(nil)).Elem()
+}
+
+func (i LocalRegistryMap) ToLocalRegistryMapOutput() LocalRegistryMapOutput {
+	return i.ToLocalRegistryMapOutputWithContext(context.Background())
+}
+
+func (i LocalRegistryMap) ToLocalRegistryMapOutputWithContext(ctx context.Context) LocalRegistryMapOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(LocalRegistryMapOutput)
+}
+
+type LocalRegistryOutput struct{ *pulumi.OutputState }
+
+func (LocalRegistryOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*LocalRegistry)(nil))
+}
+
+func (o LocalRegistryOutput) ToLocalRegistryOutput() LocalRegistryOutput {
+	return o
+}
+
+func (o LocalRegistryOutput) ToLocalRegistryOutputWithContext(ctx context.Context) LocalRegistryOutput {
+	return o
+}
+
+func (o LocalRegistryOutput) ToLocalRegistryPtrOutput() LocalRegistryPtrOutput {
+	return o.ToLocalRegistryPtrOutputWithContext(context.Background())
+}
+
+func (o LocalRegistryOutput) ToLocalRegistryPtrOutputWithContext(ctx context.Context) LocalRegistryPtrOutput {
+	return o.ApplyTWithContext(ctx, func(_ context.Context, v LocalRegistry) *LocalRegistry {
+		return &v
+	}).(LocalRegistryPtrOutput)
+}
+
+type LocalRegistryPtrOutput struct{ *pulumi.OutputState }
+
+func (LocalRegistryPtrOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((**LocalRegistry)(nil))
+}
+
+func (o LocalRegistryPtrOutput) ToLocalRegistryPtrOutput() LocalRegistryPtrOutput {
+	return o
+}
+
+func (o LocalRegistryPtrOutput) ToLocalRegistryPtrOutputWithContext(ctx context.Context) LocalRegistryPtrOutput {
+	return o
+}
+
+func (o LocalRegistryPtrOutput) Elem() LocalRegistryOutput {
+	return o.ApplyT(func(v *LocalRegistry) LocalRegistry {
+		if v != nil {
+			return *v
+		}
+		var ret LocalRegistry
+		return ret
+	}).(LocalRegistryOutput)
+}
+
+type LocalRegistryArrayOutput struct{ *pulumi.OutputState }
+
+func (LocalRegistryArrayOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*[]LocalRegistry)(nil))
+}
+
+func (o LocalRegistryArrayOutput) ToLocalRegistryArrayOutput() LocalRegistryArrayOutput {
+	return o
+}
+
+func (o LocalRegistryArrayOutput) ToLocalRegistryArrayOutputWithContext(ctx context.Context) LocalRegistryArrayOutput {
+	return o
+}
+
+func (o LocalRegistryArrayOutput) Index(i pulumi.IntInput) LocalRegistryOutput {
+	return pulumi.All(o, i).ApplyT(func(vs []interface{}) LocalRegistry {
+		return vs[0].([]LocalRegistry)[vs[1].(int)]
+	}).(LocalRegistryOutput)
+}
+
+type LocalRegistryMapOutput struct{ *pulumi.OutputState }
+
+func (LocalRegistryMapOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*map[string]LocalRegistry)(nil))
+}
+
+func (o LocalRegistryMapOutput) ToLocalRegistryMapOutput() LocalRegistryMapOutput {
+	return o
+}
+
+func (o LocalRegistryMapOutput) ToLocalRegistryMapOutputWithContext(ctx context.Context) LocalRegistryMapOutput {
+	return o
+}
+
+func (o LocalRegistryMapOutput) MapIndex(k pulumi.StringInput) LocalRegistryOutput {
+	return pulumi.All(o, k).ApplyT(func(vs []interface{}) LocalRegistry {
+		return vs[0].(map[string]LocalRegistry)[vs[1].(string)]
+	}).(LocalRegistryOutput)
+}
+
+func init() {
+	pulumi.RegisterInputType(reflect.TypeOf((*LocalRegistryInput)(nil)).Elem(), &LocalRegistry{})
+	pulumi.RegisterInputType(reflect.TypeOf((*LocalRegistryPtrInput)(nil)).Elem(), &LocalRegistry{})
+	pulumi.RegisterInputType(reflect.TypeOf((*LocalRegistryArrayInput)(nil)).Elem(), LocalRegistryArray{})
+	pulumi.RegisterInputType(reflect.TypeOf((*LocalRegistryMapInput)(nil)).Elem(), LocalRegistryMap{})
+	pulumi.RegisterOutputType(LocalRegistryOutput{})
+	pulumi.RegisterOutputType(LocalRegistryPtrOutput{})
+	pulumi.RegisterOutputType(LocalRegistryArrayOutput{})
+	pulumi.RegisterOutputType(LocalRegistryMapOutput{})
+}
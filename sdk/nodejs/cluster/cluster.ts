@@ -35,6 +35,22 @@ export class Cluster extends pulumi.CustomResource {
         return obj['__pulumiType'] === Cluster.__pulumiType;
     }
 
+    /**
+     * API server endpoint reachable from the host
+     */
+    public /*out*/ readonly apiServerEndpoint!: pulumi.Output<string | undefined>;
+    /**
+     * host port of the API server, resolved when networking.apiServerPort is 0
+     */
+    public /*out*/ readonly apiServerPort!: pulumi.Output<number | undefined>;
+    /**
+     * PEM encoded certificate authority of the cluster
+     */
+    public /*out*/ readonly caCertificate!: pulumi.Output<string | undefined>;
+    /**
+     * API server endpoint reachable from containers on the cluster network
+     */
+    public /*out*/ readonly internalApiServerEndpoint!: pulumi.Output<string | undefined>;
     /**
      * kubeconfig content
      */
@@ -43,6 +59,22 @@ export class Cluster extends pulumi.CustomResource {
      * cluster name
      */
     public readonly name!: pulumi.Output<string>;
+    /**
+     * name of the docker/podman network the nodes joined
+     */
+    public /*out*/ readonly networkName!: pulumi.Output<string | undefined>;
+    /**
+     * subnets of the docker/podman network the nodes joined
+     */
+    public /*out*/ readonly networkSubnets!: pulumi.Output<string[] | undefined>;
+    /**
+     * live information of the node containers of the cluster
+     */
+    public /*out*/ readonly nodeDetails!: pulumi.Output<outputs.cluster.NodeDetail[]>;
+    /**
+     * live state of the node containers, stopped if any of them is not running
+     */
+    public readonly state!: pulumi.Output<string>;
 
     /**
      * Create a Cluster resource with the given unique name, arguments, and options.
@@ -55,21 +87,50 @@ export class Cluster extends pulumi.CustomResource {
         let inputs: pulumi.Inputs = {};
         opts = opts || {};
         if (!opts.id) {
+            inputs["adoptExisting"] = args ? args.adoptExisting : undefined;
+            inputs["apiServer"] = args ? args.apiServer : undefined;
             inputs["apiVersion"] = args ? args.apiVersion : undefined;
             inputs["containerdConfigPatches"] = args ? args.containerdConfigPatches : undefined;
             inputs["containerdConfigPatchesJSON6902"] = args ? args.containerdConfigPatchesJSON6902 : undefined;
+            inputs["extraCACertificates"] = args ? args.extraCACertificates : undefined;
             inputs["featureGates"] = args ? args.featureGates : undefined;
+            inputs["hostAliases"] = args ? args.hostAliases : undefined;
             inputs["kind"] = args ? args.kind : undefined;
             inputs["kubeadmConfigPatches"] = args ? args.kubeadmConfigPatches : undefined;
             inputs["kubeadmConfigPatchesJSON6902"] = args ? args.kubeadmConfigPatchesJSON6902 : undefined;
+            inputs["kubeconfigContext"] = args ? args.kubeconfigContext : undefined;
+            inputs["kubeconfigExport"] = args ? args.kubeconfigExport : undefined;
+            inputs["kubeconfigPath"] = args ? args.kubeconfigPath : undefined;
+            inputs["kubernetesVersion"] = args ? args.kubernetesVersion : undefined;
             inputs["name"] = args ? args.name : undefined;
+            inputs["network"] = args ? args.network : undefined;
             inputs["networking"] = args ? args.networking : undefined;
             inputs["nodes"] = args ? args.nodes : undefined;
+            inputs["preloadImages"] = args ? args.preloadImages : undefined;
+            inputs["proxy"] = args ? args.proxy : undefined;
+            inputs["registryMirrors"] = args ? args.registryMirrors : undefined;
             inputs["runtimeConfig"] = args ? args.runtimeConfig : undefined;
+            inputs["state"] = args ? args.state : undefined;
+            inputs["upgradeStrategy"] = args ? args.upgradeStrategy : undefined;
+            inputs["apiServerEndpoint"] = undefined /*out*/;
+            inputs["apiServerPort"] = undefined /*out*/;
+            inputs["caCertificate"] = undefined /*out*/;
+            inputs["internalApiServerEndpoint"] = undefined /*out*/;
             inputs["kubeconfig"] = undefined /*out*/;
+            inputs["networkName"] = undefined /*out*/;
+            inputs["networkSubnets"] = undefined /*out*/;
+            inputs["nodeDetails"] = undefined /*out*/;
         } else {
+            inputs["apiServerEndpoint"] = undefined /*out*/;
+            inputs["apiServerPort"] = undefined /*out*/;
+            inputs["caCertificate"] = undefined /*out*/;
+            inputs["internalApiServerEndpoint"] = undefined /*out*/;
             inputs["kubeconfig"] = undefined /*out*/;
             inputs["name"] = undefined /*out*/;
+            inputs["networkName"] = undefined /*out*/;
+            inputs["networkSubnets"] = undefined /*out*/;
+            inputs["nodeDetails"] = undefined /*out*/;
+            inputs["state"] = undefined /*out*/;
         }
         if (!opts.version) {
             opts = pulumi.mergeOptions(opts, { version: utilities.getVersion()});
@@ -82,15 +143,71 @@ export class Cluster extends pulumi.CustomResource {
  * The set of arguments for constructing a Cluster resource.
  */
 export interface ClusterArgs {
+    /**
+     * Take over a pre-existing KIND cluster with the same name if its nodes match the config instead of failing. Default: false
+     */
+    adoptExisting?: pulumi.Input<boolean>;
+    /**
+     * Configuration of the API servers rendered into kubeadm patches after kubeadmConfigPatches, with the files it reads mounted into the control plane nodes. Changes recreate the cluster
+     */
+    apiServer?: pulumi.Input<inputs.cluster.APIServerArgs>;
     apiVersion?: pulumi.Input<string>;
     containerdConfigPatches?: pulumi.Input<pulumi.Input<string>[]>;
     containerdConfigPatchesJSON6902?: pulumi.Input<pulumi.Input<string>[]>;
+    /**
+     * PEM encoded CA certificates, or paths of PEM files, trusted by the nodes and containerd from the moment the nodes start. Changes to the list are installed in place, changes to the content of the files are not detected
+     */
+    extraCACertificates?: pulumi.Input<pulumi.Input<string>[]>;
     featureGates?: pulumi.Input<{[key: string]: pulumi.Input<string>}>;
+    /**
+     * host names resolving to IP addresses in the nodes and in the cluster, written to /etc/hosts of the nodes and to the CoreDNS config. Changes are applied in place
+     */
+    hostAliases?: pulumi.Input<pulumi.Input<inputs.cluster.HostAliasArgs>[]>;
     kind?: pulumi.Input<string>;
     kubeadmConfigPatches?: pulumi.Input<pulumi.Input<string>[]>;
     kubeadmConfigPatchesJSON6902?: pulumi.Input<pulumi.Input<inputs.patchjson6902.PatchJSON6902Args>[]>;
+    /**
+     * Name of the context, cluster and user entries of the cluster in the kubeconfig. Default: kind-<name>
+     */
+    kubeconfigContext?: pulumi.Input<string>;
+    /**
+     * Where to export the kubeconfig of the cluster: none keeps it only in the kubeconfig output, file writes it to kubeconfigPath and merge merges it into kubeconfigPath without switching the current context. Default: KIND's behaviour of merging into the default kubeconfig and switching the current context
+     */
+    kubeconfigExport?: pulumi.Input<string | enums.cluster.KubeconfigExport>;
+    /**
+     * Kubeconfig file used by the file and merge kubeconfig exports. Required for file. Default for merge: the default kubeconfig
+     */
+    kubeconfigPath?: pulumi.Input<string>;
+    /**
+     * Kubernetes version like 1.21 or v1.21.1 of the nodes without an image or kubernetesVersion of their own, resolved to the node image pinned by digest for the KIND release of the provider
+     */
+    kubernetesVersion?: pulumi.Input<string>;
     name?: pulumi.Input<string>;
+    /**
+     * docker/podman network of the node containers instead of the kind network or KIND_EXPERIMENTAL_DOCKER_NETWORK, created when missing and removed with the last cluster using it if the provider created it. Changes recreate the cluster
+     */
+    network?: pulumi.Input<inputs.cluster.NetworkSettingsArgs>;
     networking?: pulumi.Input<inputs.networking.NetworkingArgs>;
     nodes?: pulumi.Input<pulumi.Input<inputs.node.NodeArgs>[]>;
+    /**
+     * Local images or paths of local image archives imported into every node right after the cluster is created, and into new nodes
+     */
+    preloadImages?: pulumi.Input<pulumi.Input<string>[]>;
+    /**
+     * Proxy settings of the node containers and containerd instead of the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables of the provider. NO_PROXY is extended with the pod and service subnets, the node network and the node names. Changes are applied to containerd in place, the node containers keep the settings they were created with
+     */
+    proxy?: pulumi.Input<inputs.cluster.ProxySettingsArgs>;
+    /**
+     * Mirrors of registries by registry host like docker.io, rendered into the containerd config of every node after containerdConfigPatches. Changes recreate the cluster
+     */
+    registryMirrors?: pulumi.Input<{[key: string]: pulumi.Input<inputs.cluster.RegistryMirrorArgs>}>;
     runtimeConfig?: pulumi.Input<{[key: string]: pulumi.Input<string>}>;
+    /**
+     * Desired state of the node containers: running or stopped. Stopped clusters keep their nodes and can be started again. Default: running
+     */
+    state?: pulumi.Input<string | enums.cluster.ClusterState>;
+    /**
+     * How node image changes are rolled out: replace recreates the cluster, inPlace upgrades the control plane and then the workers with kubeadm when all nodes move to the same version at most one minor version newer and recreates the cluster otherwise. Default: replace
+     */
+    upgradeStrategy?: pulumi.Input<string | enums.cluster.UpgradeStrategy>;
 }
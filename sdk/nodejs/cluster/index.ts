@@ -7,6 +7,9 @@ import * as utilities from "../utilities";
 // Export members:
 export * from "./cluster";
 
+// Export enums:
+export * from "../types/enums/cluster";
+
 // Import resources to register:
 import { Cluster } from "./cluster";
 
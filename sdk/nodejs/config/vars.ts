@@ -7,6 +7,50 @@ import * as utilities from "../utilities";
 declare var exports: any;
 const __config = new pulumi.Config("kind");
 
+/**
+ * Derive the suffix of generated cluster names from the stack and resource name instead of picking it at random. Default: false. Optional
+ */
+export declare const autonameDeterministic: boolean | undefined;
+Object.defineProperty(exports, "autonameDeterministic", {
+    get() {
+        return __config.getObject<boolean>("autonameDeterministic");
+    },
+    enumerable: true,
+});
+
+/**
+ * Maximum length of generated cluster names, longer resource names are truncated. Default: 40. Maximum: 50. Optional
+ */
+export declare const autonameMaxLength: number | undefined;
+Object.defineProperty(exports, "autonameMaxLength", {
+    get() {
+        return __config.getObject<number>("autonameMaxLength");
+    },
+    enumerable: true,
+});
+
+/**
+ * Prefix to add to generated cluster names. Optional
+ */
+export declare const autonamePrefix: string | undefined;
+Object.defineProperty(exports, "autonamePrefix", {
+    get() {
+        return __config.get("autonamePrefix");
+    },
+    enumerable: true,
+});
+
+/**
+ * Length of the random suffix added to generated cluster names, 0 disables the suffix. Default: 8. Optional
+ */
+export declare const autonameSuffixLength: number | undefined;
+Object.defineProperty(exports, "autonameSuffixLength", {
+    get() {
+        return __config.getObject<number>("autonameSuffixLength");
+    },
+    enumerable: true,
+});
+
 /**
  * Kind config file to use. Optional
  */
@@ -40,6 +84,17 @@ Object.defineProperty(exports, "nodeImage", {
     enumerable: true,
 });
 
+/**
+ * Never pull images. Node and registry images that are not available locally fail the preview instead. Default: false. Optional
+ */
+export declare const offline: boolean | undefined;
+Object.defineProperty(exports, "offline", {
+    get() {
+        return __config.getObject<boolean>("offline");
+    },
+    enumerable: true,
+});
+
 /**
  * Provider to use. Supports docker/podman. Default: docker. Optional
  */
@@ -74,12 +129,12 @@ Object.defineProperty(exports, "stopBeforeSettingK8s", {
 });
 
 /**
- * Time in seconds to wait for nodes to become ready. Default: none. Optional
+ * Time to wait for nodes to become ready, either in seconds or as a duration like 90s or 5m. Default: none. Optional
  */
-export declare const waitForNodeReady: number | undefined;
+export declare const waitForNodeReady: number | string | undefined;
 Object.defineProperty(exports, "waitForNodeReady", {
     get() {
-        return __config.getObject<number>("waitForNodeReady");
+        return __config.getObject<number | string>("waitForNodeReady");
     },
     enumerable: true,
 });
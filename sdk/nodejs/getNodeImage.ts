@@ -0,0 +1,51 @@
+// *** WARNING: this file was generated by pulumigen. ***
+// *** Do not edit by hand unless you're certain you know what you are doing! ***
+
+import * as pulumi from "@pulumi/pulumi";
+import * as utilities from "./utilities";
+
+/**
+ * Node image pinned by digest for a Kubernetes version, for the KIND release of the provider
+ */
+export function getNodeImage(args?: GetNodeImageArgs, opts?: pulumi.InvokeOptions): Promise<GetNodeImageResult> {
+    args = args || {};
+    if (!opts) {
+        opts = {}
+    }
+
+    if (!opts.version) {
+        opts.version = utilities.getVersion();
+    }
+    return pulumi.runtime.invoke("kind:index:getNodeImage", {
+        "kubernetesVersion": args.kubernetesVersion,
+    }, opts);
+}
+
+export interface GetNodeImageArgs {
+    /**
+     * Kubernetes version like 1.21 or v1.21.1. Default: the version of KIND's default node image
+     */
+    kubernetesVersion?: string;
+}
+
+export interface GetNodeImageResult {
+    /**
+     * node image reference with its digest
+     */
+    readonly image: string;
+    /**
+     * exact Kubernetes version of the node image
+     */
+    readonly kubernetesVersion: string;
+}
+
+export function getNodeImageOutput(args?: GetNodeImageOutputArgs, opts?: pulumi.InvokeOptions): pulumi.Output<GetNodeImageResult> {
+    return pulumi.output(args).apply(a => getNodeImage(a, opts))
+}
+
+export interface GetNodeImageOutputArgs {
+    /**
+     * Kubernetes version like 1.21 or v1.21.1. Default: the version of KIND's default node image
+     */
+    kubernetesVersion?: pulumi.Input<string>;
+}
@@ -0,0 +1,123 @@
+// *** WARNING: this file was generated by pulumigen. ***
+// *** Do not edit by hand unless you're certain you know what you are doing! ***
+
+import * as pulumi from "@pulumi/pulumi";
+import * as utilities from "../utilities";
+
+/**
+ * Local image or image archive loaded into the nodes of a KIND cluster, like kind load
+ */
+export class LoadedImage extends pulumi.CustomResource {
+    /**
+     * Get an existing LoadedImage resource's state with the given name, ID, and optional extra
+     * properties used to qualify the lookup.
+     *
+     * @param name The _unique_ name of the resulting resource.
+     * @param id The _unique_ provider ID of the resource to lookup.
+     * @param opts Optional settings to control the behavior of the CustomResource.
+     */
+    public static get(name: string, id: pulumi.Input<pulumi.ID>, opts?: pulumi.CustomResourceOptions): LoadedImage {
+        return new LoadedImage(name, undefined as any, { ...opts, id: id });
+    }
+
+    /** @internal */
+    public static readonly __pulumiType = 'kind:image:LoadedImage';
+
+    /**
+     * Returns true if the given object is an instance of LoadedImage.  This is designed to work even
+     * when multiple copies of the Pulumi SDK have been loaded into the same process.
+     */
+    public static isInstance(obj: any): obj is LoadedImage {
+        if (obj === undefined || obj === null) {
+            return false;
+        }
+        return obj['__pulumiType'] === LoadedImage.__pulumiType;
+    }
+
+    /**
+     * path of the local image archive
+     */
+    public readonly archive!: pulumi.Output<string | undefined>;
+    /**
+     * cluster name
+     */
+    public readonly clusterName!: pulumi.Output<string>;
+    /**
+     * local image ID, or the IDs of the images in the archive
+     */
+    public /*out*/ readonly digest!: pulumi.Output<string | undefined>;
+    /**
+     * local image reference
+     */
+    public readonly image!: pulumi.Output<string | undefined>;
+    /**
+     * names of the nodes the image was loaded into
+     */
+    public /*out*/ readonly loadedNodes!: pulumi.Output<string[]>;
+    /**
+     * names of the nodes the image is loaded into
+     */
+    public readonly nodes!: pulumi.Output<string[] | undefined>;
+    /**
+     * references the image is known by in the nodes
+     */
+    public /*out*/ readonly references!: pulumi.Output<string[]>;
+
+    /**
+     * Create a LoadedImage resource with the given unique name, arguments, and options.
+     *
+     * @param name The _unique_ name of the resource.
+     * @param args The arguments to use to populate this resource's properties.
+     * @param opts A bag of options that control this resource's behavior.
+     */
+    constructor(name: string, args: LoadedImageArgs, opts?: pulumi.CustomResourceOptions) {
+        let inputs: pulumi.Inputs = {};
+        opts = opts || {};
+        if (!opts.id) {
+            if ((!args || args.clusterName === undefined) && !opts.urn) {
+                throw new Error("Missing required property 'clusterName'");
+            }
+            inputs["archive"] = args ? args.archive : undefined;
+            inputs["clusterName"] = args ? args.clusterName : undefined;
+            inputs["image"] = args ? args.image : undefined;
+            inputs["nodes"] = args ? args.nodes : undefined;
+            inputs["digest"] = undefined /*out*/;
+            inputs["loadedNodes"] = undefined /*out*/;
+            inputs["references"] = undefined /*out*/;
+        } else {
+            inputs["archive"] = undefined /*out*/;
+            inputs["clusterName"] = undefined /*out*/;
+            inputs["digest"] = undefined /*out*/;
+            inputs["image"] = undefined /*out*/;
+            inputs["loadedNodes"] = undefined /*out*/;
+            inputs["nodes"] = undefined /*out*/;
+            inputs["references"] = undefined /*out*/;
+        }
+        if (!opts.version) {
+            opts = pulumi.mergeOptions(opts, { version: utilities.getVersion()});
+        }
+        super(LoadedImage.__pulumiType, name, inputs, opts);
+    }
+}
+
+/**
+ * The set of arguments for constructing a LoadedImage resource.
+ */
+export interface LoadedImageArgs {
+    /**
+     * Path of a local image archive to load, reloaded whenever the images in it change. Either image or archive is required
+     */
+    archive?: pulumi.Input<string>;
+    /**
+     * Name of the KIND cluster to load the image into
+     */
+    clusterName: pulumi.Input<string>;
+    /**
+     * Local image to load, reloaded whenever its image ID changes. Either image or archive is required
+     */
+    image?: pulumi.Input<string>;
+    /**
+     * Names of the nodes to load the image into. Default: all nodes
+     */
+    nodes?: pulumi.Input<pulumi.Input<string>[]>;
+}
@@ -5,18 +5,25 @@ import * as pulumi from "@pulumi/pulumi";
 import * as utilities from "./utilities";
 
 // Export members:
+export * from "./getNodeImage";
 export * from "./provider";
 
 // Export sub-modules:
 import * as cluster from "./cluster";
 import * as config from "./config";
+import * as image from "./image";
+import * as network from "./network";
 import * as node from "./node";
+import * as registry from "./registry";
 import * as types from "./types";
 
 export {
     cluster,
     config,
+    image,
+    network,
     node,
+    registry,
     types,
 };
 
@@ -0,0 +1,102 @@
+// *** WARNING: this file was generated by pulumigen. ***
+// *** Do not edit by hand unless you're certain you know what you are doing! ***
+
+import * as pulumi from "@pulumi/pulumi";
+import { input as inputs, output as outputs, enums } from "../types";
+import * as utilities from "../utilities";
+
+/**
+ * Shared docker/podman network the nodes of KIND clusters are attached to, with static routes on every node to the pod and service subnets of the other clusters
+ */
+export class ClusterPeering extends pulumi.CustomResource {
+    /**
+     * Get an existing ClusterPeering resource's state with the given name, ID, and optional extra
+     * properties used to qualify the lookup.
+     *
+     * @param name The _unique_ name of the resulting resource.
+     * @param id The _unique_ provider ID of the resource to lookup.
+     * @param opts Optional settings to control the behavior of the CustomResource.
+     */
+    public static get(name: string, id: pulumi.Input<pulumi.ID>, opts?: pulumi.CustomResourceOptions): ClusterPeering {
+        return new ClusterPeering(name, undefined as any, { ...opts, id: id });
+    }
+
+    /** @internal */
+    public static readonly __pulumiType = 'kind:network:ClusterPeering';
+
+    /**
+     * Returns true if the given object is an instance of ClusterPeering.  This is designed to work even
+     * when multiple copies of the Pulumi SDK have been loaded into the same process.
+     */
+    public static isInstance(obj: any): obj is ClusterPeering {
+        if (obj === undefined || obj === null) {
+            return false;
+        }
+        return obj['__pulumiType'] === ClusterPeering.__pulumiType;
+    }
+
+    /**
+     * names of the peered clusters
+     */
+    public readonly clusterNames!: pulumi.Output<string[]>;
+    /**
+     * clusters attached to the network with their subnets and nodes
+     */
+    public /*out*/ readonly clusters!: pulumi.Output<outputs.network.PeeredCluster[] | undefined>;
+    /**
+     * docker/podman network the nodes are attached to
+     */
+    public readonly network!: pulumi.Output<string>;
+    /**
+     * IPv4 subnet of the network when it is created
+     */
+    public readonly subnet!: pulumi.Output<string | undefined>;
+
+    /**
+     * Create a ClusterPeering resource with the given unique name, arguments, and options.
+     *
+     * @param name The _unique_ name of the resource.
+     * @param args The arguments to use to populate this resource's properties.
+     * @param opts A bag of options that control this resource's behavior.
+     */
+    constructor(name: string, args: ClusterPeeringArgs, opts?: pulumi.CustomResourceOptions) {
+        let inputs: pulumi.Inputs = {};
+        opts = opts || {};
+        if (!opts.id) {
+            if ((!args || args.clusterNames === undefined) && !opts.urn) {
+                throw new Error("Missing required property 'clusterNames'");
+            }
+            inputs["clusterNames"] = args ? args.clusterNames : undefined;
+            inputs["network"] = args ? args.network : undefined;
+            inputs["subnet"] = args ? args.subnet : undefined;
+            inputs["clusters"] = undefined /*out*/;
+        } else {
+            inputs["clusterNames"] = undefined /*out*/;
+            inputs["clusters"] = undefined /*out*/;
+            inputs["network"] = undefined /*out*/;
+            inputs["subnet"] = undefined /*out*/;
+        }
+        if (!opts.version) {
+            opts = pulumi.mergeOptions(opts, { version: utilities.getVersion()});
+        }
+        super(ClusterPeering.__pulumiType, name, inputs, opts);
+    }
+}
+
+/**
+ * The set of arguments for constructing a ClusterPeering resource.
+ */
+export interface ClusterPeeringArgs {
+    /**
+     * Names of at least two KIND clusters to peer. Their pod and service subnets, and the subnets of the network, must not overlap. Changes are applied in place
+     */
+    clusterNames: pulumi.Input<pulumi.Input<string>[]>;
+    /**
+     * docker/podman network to attach the nodes to, created when missing and removed with the peering when it was created by the provider. Default: kind-peering
+     */
+    network?: pulumi.Input<string>;
+    /**
+     * IPv4 subnet of the network when it is created, picked by the container runtime otherwise
+     */
+    subnet?: pulumi.Input<string>;
+}
@@ -34,13 +34,18 @@ export class Provider extends pulumi.ProviderResource {
         let inputs: pulumi.Inputs = {};
         opts = opts || {};
         {
+            inputs["autonameDeterministic"] = pulumi.output(args ? args.autonameDeterministic : undefined).apply(JSON.stringify);
+            inputs["autonameMaxLength"] = pulumi.output(args ? args.autonameMaxLength : undefined).apply(JSON.stringify);
+            inputs["autonamePrefix"] = args ? args.autonamePrefix : undefined;
+            inputs["autonameSuffixLength"] = pulumi.output(args ? args.autonameSuffixLength : undefined).apply(JSON.stringify);
             inputs["configFile"] = args ? args.configFile : undefined;
             inputs["kubeconfigFile"] = args ? args.kubeconfigFile : undefined;
             inputs["nodeImage"] = args ? args.nodeImage : undefined;
+            inputs["offline"] = pulumi.output(args ? args.offline : undefined).apply(JSON.stringify);
             inputs["provider"] = args ? args.provider : undefined;
             inputs["retainNodesOnFailure"] = pulumi.output(args ? args.retainNodesOnFailure : undefined).apply(JSON.stringify);
             inputs["stopBeforeSettingK8s"] = pulumi.output(args ? args.stopBeforeSettingK8s : undefined).apply(JSON.stringify);
-            inputs["waitForNodeReady"] = pulumi.output(args ? args.waitForNodeReady : undefined).apply(JSON.stringify);
+            inputs["waitForNodeReady"] = args ? args.waitForNodeReady : undefined;
         }
         if (!opts.version) {
             opts = pulumi.mergeOptions(opts, { version: utilities.getVersion()});
@@ -53,6 +58,22 @@ export class Provider extends pulumi.ProviderResource {
  * The set of arguments for constructing a Provider resource.
  */
 export interface ProviderArgs {
+    /**
+     * Derive the suffix of generated cluster names from the stack and resource name instead of picking it at random. Default: false. Optional
+     */
+    autonameDeterministic?: pulumi.Input<boolean>;
+    /**
+     * Maximum length of generated cluster names, longer resource names are truncated. Default: 40. Maximum: 50. Optional
+     */
+    autonameMaxLength?: pulumi.Input<number>;
+    /**
+     * Prefix to add to generated cluster names. Optional
+     */
+    autonamePrefix?: pulumi.Input<string>;
+    /**
+     * Length of the random suffix added to generated cluster names, 0 disables the suffix. Default: 8. Optional
+     */
+    autonameSuffixLength?: pulumi.Input<number>;
     /**
      * Kind config file to use. Optional
      */
@@ -65,6 +86,10 @@ export interface ProviderArgs {
      * Node image to use. Optional
      */
     nodeImage?: pulumi.Input<string>;
+    /**
+     * Never pull images. Node and registry images that are not available locally fail the preview instead. Default: false. Optional
+     */
+    offline?: pulumi.Input<boolean>;
     /**
      * Provider to use. Supports docker/podman. Default: docker. Optional
      */
@@ -78,7 +103,7 @@ export interface ProviderArgs {
      */
     stopBeforeSettingK8s?: pulumi.Input<boolean>;
     /**
-     * Time in seconds to wait for nodes to become ready. Default: none. Optional
+     * Time to wait for nodes to become ready, either in seconds or as a duration like 90s or 5m. Default: none. Optional
      */
-    waitForNodeReady?: pulumi.Input<number>;
+    waitForNodeReady?: pulumi.Input<number | string>;
 }
@@ -0,0 +1,140 @@
+// *** WARNING: this file was generated by pulumigen. ***
+// *** Do not edit by hand unless you're certain you know what you are doing! ***
+
+import * as pulumi from "@pulumi/pulumi";
+import * as utilities from "../utilities";
+
+/**
+ * Registry container on the KIND network that the nodes of KIND clusters pull the images of its host address from
+ */
+export class LocalRegistry extends pulumi.CustomResource {
+    /**
+     * Get an existing LocalRegistry resource's state with the given name, ID, and optional extra
+     * properties used to qualify the lookup.
+     *
+     * @param name The _unique_ name of the resulting resource.
+     * @param id The _unique_ provider ID of the resource to lookup.
+     * @param opts Optional settings to control the behavior of the CustomResource.
+     */
+    public static get(name: string, id: pulumi.Input<pulumi.ID>, opts?: pulumi.CustomResourceOptions): LocalRegistry {
+        return new LocalRegistry(name, undefined as any, { ...opts, id: id });
+    }
+
+    /** @internal */
+    public static readonly __pulumiType = 'kind:registry:LocalRegistry';
+
+    /**
+     * Returns true if the given object is an instance of LocalRegistry.  This is designed to work even
+     * when multiple copies of the Pulumi SDK have been loaded into the same process.
+     */
+    public static isInstance(obj: any): obj is LocalRegistry {
+        if (obj === undefined || obj === null) {
+            return false;
+        }
+        return obj['__pulumiType'] === LocalRegistry.__pulumiType;
+    }
+
+    /**
+     * host:port of the registry on the KIND network
+     */
+    public /*out*/ readonly clusterAddress!: pulumi.Output<string>;
+    /**
+     * names of the clusters using the registry
+     */
+    public readonly clusterNames!: pulumi.Output<string[] | undefined>;
+    /**
+     * names of the clusters the registry is configured in
+     */
+    public /*out*/ readonly configuredClusters!: pulumi.Output<string[] | undefined>;
+    /**
+     * host:port of the registry on the host, images tagged with it are pulled from the registry by the nodes
+     */
+    public /*out*/ readonly hostAddress!: pulumi.Output<string>;
+    /**
+     * host port the registry is published on, 0 for a random port
+     */
+    public readonly hostPort!: pulumi.Output<number>;
+    /**
+     * registry image
+     */
+    public readonly image!: pulumi.Output<string>;
+    /**
+     * host address the registry is published on
+     */
+    public readonly listenAddress!: pulumi.Output<string>;
+    /**
+     * registry container name
+     */
+    public readonly name!: pulumi.Output<string>;
+    /**
+     * docker/podman network the registry joins
+     */
+    public readonly network!: pulumi.Output<string>;
+
+    /**
+     * Create a LocalRegistry resource with the given unique name, arguments, and options.
+     *
+     * @param name The _unique_ name of the resource.
+     * @param args The arguments to use to populate this resource's properties.
+     * @param opts A bag of options that control this resource's behavior.
+     */
+    constructor(name: string, args?: LocalRegistryArgs, opts?: pulumi.CustomResourceOptions) {
+        let inputs: pulumi.Inputs = {};
+        opts = opts || {};
+        if (!opts.id) {
+            inputs["clusterNames"] = args ? args.clusterNames : undefined;
+            inputs["hostPort"] = args ? args.hostPort : undefined;
+            inputs["image"] = args ? args.image : undefined;
+            inputs["listenAddress"] = args ? args.listenAddress : undefined;
+            inputs["name"] = args ? args.name : undefined;
+            inputs["network"] = args ? args.network : undefined;
+            inputs["clusterAddress"] = undefined /*out*/;
+            inputs["configuredClusters"] = undefined /*out*/;
+            inputs["hostAddress"] = undefined /*out*/;
+        } else {
+            inputs["clusterAddress"] = undefined /*out*/;
+            inputs["clusterNames"] = undefined /*out*/;
+            inputs["configuredClusters"] = undefined /*out*/;
+            inputs["hostAddress"] = undefined /*out*/;
+            inputs["hostPort"] = undefined /*out*/;
+            inputs["image"] = undefined /*out*/;
+            inputs["listenAddress"] = undefined /*out*/;
+            inputs["name"] = undefined /*out*/;
+            inputs["network"] = undefined /*out*/;
+        }
+        if (!opts.version) {
+            opts = pulumi.mergeOptions(opts, { version: utilities.getVersion()});
+        }
+        super(LocalRegistry.__pulumiType, name, inputs, opts);
+    }
+}
+
+/**
+ * The set of arguments for constructing a LocalRegistry resource.
+ */
+export interface LocalRegistryArgs {
+    /**
+     * Names of the KIND clusters whose nodes pull the images of the host address from the registry, configured with a containerd mirror and the local-registry-hosting ConfigMap
+     */
+    clusterNames?: pulumi.Input<pulumi.Input<string>[]>;
+    /**
+     * Host port to publish the registry on, 0 for a random port. Default: 5001
+     */
+    hostPort?: pulumi.Input<number>;
+    /**
+     * Registry image. Default: registry:2
+     */
+    image?: pulumi.Input<string>;
+    /**
+     * Host address to publish the registry on. Default: 127.0.0.1
+     */
+    listenAddress?: pulumi.Input<string>;
+    /**
+     * Name of the registry container. Default: kind-registry
+     */
+    name?: pulumi.Input<string>;
+    /**
+     * docker/podman network of the KIND clusters, which has to exist. Default: kind, or KIND_EXPERIMENTAL_DOCKER_NETWORK when set
+     */
+    network?: pulumi.Input<string>;
+}
@@ -0,0 +1,46 @@
+// *** WARNING: this file was generated by pulumigen. ***
+// *** Do not edit by hand unless you're certain you know what you are doing! ***
+
+
+export const ClusterState = {
+    /**
+     * all node containers are running
+     */
+    Running: "running",
+    /**
+     * the node containers are stopped
+     */
+    Stopped: "stopped",
+} as const;
+
+export type ClusterState = (typeof ClusterState)[keyof typeof ClusterState];
+
+export const KubeconfigExport = {
+    /**
+     * only keep the kubeconfig in the resource outputs
+     */
+    None: "none",
+    /**
+     * write the kubeconfig to a dedicated file
+     */
+    File: "file",
+    /**
+     * merge the kubeconfig into a file without switching the current context
+     */
+    Merge: "merge",
+} as const;
+
+export type KubeconfigExport = (typeof KubeconfigExport)[keyof typeof KubeconfigExport];
+
+export const UpgradeStrategy = {
+    /**
+     * recreate the cluster with the new node images
+     */
+    Replace: "replace",
+    /**
+     * upgrade the nodes with kubeadm
+     */
+    InPlace: "inPlace",
+} as const;
+
+export type UpgradeStrategy = (typeof UpgradeStrategy)[keyof typeof UpgradeStrategy];
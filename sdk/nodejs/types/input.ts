@@ -4,6 +4,170 @@
 import * as pulumi from "@pulumi/pulumi";
 import { input as inputs, output as outputs, enums } from "../types";
 
+export namespace cluster {
+    /**
+     * configuration of the API servers of the control plane nodes
+     */
+    export interface APIServerArgs {
+        /**
+         * audit policy YAML, enables audit logging to /var/log/kubernetes/audit/audit.log in the control plane nodes
+         */
+        auditPolicy?: pulumi.Input<string>;
+        /**
+         * extra IP addresses and DNS names of the API server serving certificate
+         */
+        certSANs?: pulumi.Input<pulumi.Input<string>[]>;
+        /**
+         * EncryptionConfiguration YAML for the encryption of resources at rest
+         */
+        encryptionConfig?: pulumi.Input<string>;
+        /**
+         * API server flags without the leading dashes, overriding the flags rendered from the other settings. feature-gates and runtime-config are set with the featureGates and runtimeConfig inputs
+         */
+        extraArgs?: pulumi.Input<{[key: string]: pulumi.Input<string>}>;
+        /**
+         * OpenID Connect authentication
+         */
+        oidc?: pulumi.Input<inputs.cluster.OIDCArgs>;
+    }
+
+    /**
+     * host names resolving to an IP address
+     */
+    export interface HostAliasArgs {
+        /**
+         * host names resolving to the IP address
+         */
+        hostnames: pulumi.Input<pulumi.Input<string>[]>;
+        /**
+         * IP address the host names resolve to
+         */
+        ip: pulumi.Input<string>;
+    }
+
+    /**
+     * container network of the node containers
+     */
+    export interface NetworkSettingsArgs {
+        /**
+         * IPv6 subnet of the network when it is created, required for ipv6 and dual stack clusters
+         */
+        ipv6Subnet?: pulumi.Input<string>;
+        /**
+         * MTU of the network when it is created
+         */
+        mtu?: pulumi.Input<number>;
+        /**
+         * network name
+         */
+        name: pulumi.Input<string>;
+        /**
+         * IPv4 subnet of the network when it is created, picked by the container runtime otherwise
+         */
+        subnet?: pulumi.Input<string>;
+    }
+
+    /**
+     * OpenID Connect settings of the API server
+     */
+    export interface OIDCArgs {
+        /**
+         * PEM encoded CA certificate of the provider, or the path of a PEM file
+         */
+        caCertificate?: pulumi.Input<string>;
+        /**
+         * client ID the tokens are issued for
+         */
+        clientId: pulumi.Input<string>;
+        /**
+         * claim used as the groups of the user
+         */
+        groupsClaim?: pulumi.Input<string>;
+        /**
+         * prefix of the groups
+         */
+        groupsPrefix?: pulumi.Input<string>;
+        /**
+         * https URL of the provider
+         */
+        issuerUrl: pulumi.Input<string>;
+        /**
+         * claims the tokens must have with the values
+         */
+        requiredClaims?: pulumi.Input<{[key: string]: pulumi.Input<string>}>;
+        /**
+         * accepted signing algorithms
+         */
+        signingAlgs?: pulumi.Input<pulumi.Input<string>[]>;
+        /**
+         * claim used as the user name
+         */
+        usernameClaim?: pulumi.Input<string>;
+        /**
+         * prefix of the user names
+         */
+        usernamePrefix?: pulumi.Input<string>;
+    }
+
+    /**
+     * proxy settings of the nodes
+     */
+    export interface ProxySettingsArgs {
+        /**
+         * proxy URL for HTTP requests
+         */
+        httpProxy?: pulumi.Input<string>;
+        /**
+         * proxy URL for HTTPS requests
+         */
+        httpsProxy?: pulumi.Input<string>;
+        /**
+         * comma separated hosts, domains and CIDRs that are not proxied
+         */
+        noProxy?: pulumi.Input<string>;
+    }
+
+    /**
+     * credentials containerd sends to registry mirror endpoints, either username and password or an identity token
+     */
+    export interface RegistryAuthArgs {
+        /**
+         * identity token
+         */
+        identityToken?: pulumi.Input<string>;
+        /**
+         * password of the user
+         */
+        password?: pulumi.Input<string>;
+        /**
+         * user name
+         */
+        username?: pulumi.Input<string>;
+    }
+
+    /**
+     * pull-through mirror of a registry
+     */
+    export interface RegistryMirrorArgs {
+        /**
+         * credentials of the endpoints
+         */
+        auth?: pulumi.Input<inputs.cluster.RegistryAuthArgs>;
+        /**
+         * absolute path in the nodes of the CA certificate of the endpoints, mounted with extraMounts
+         */
+        caFile?: pulumi.Input<string>;
+        /**
+         * http or https URLs of the mirrors, tried in order before the registry itself
+         */
+        endpoints: pulumi.Input<pulumi.Input<string>[]>;
+        /**
+         * skip the TLS verification of the endpoints
+         */
+        insecureSkipVerify?: pulumi.Input<boolean>;
+    }
+}
+
 export namespace mount {
     /**
      * KIND Mount type
@@ -17,6 +181,9 @@ export namespace mount {
     }
 }
 
+export namespace network {
+}
+
 export namespace networking {
     /**
      * KIND Networking type
@@ -33,6 +200,36 @@ export namespace networking {
 }
 
 export namespace node {
+    /**
+     * kubelet settings of a node
+     */
+    export interface KubeletArgs {
+        /**
+         * hard eviction thresholds by signal like memory.available, as quantities like 100Mi or percentages like 10%
+         */
+        evictionHard?: pulumi.Input<{[key: string]: pulumi.Input<string>}>;
+        /**
+         * soft eviction thresholds by signal, as quantities or percentages
+         */
+        evictionSoft?: pulumi.Input<{[key: string]: pulumi.Input<string>}>;
+        /**
+         * grace periods of the soft eviction thresholds by signal, as durations like 1m30s
+         */
+        evictionSoftGracePeriod?: pulumi.Input<{[key: string]: pulumi.Input<string>}>;
+        /**
+         * kubelet flags without the leading dashes, overriding the flags rendered from the other settings
+         */
+        extraArgs?: pulumi.Input<{[key: string]: pulumi.Input<string>}>;
+        /**
+         * maximum number of pods on the node
+         */
+        maxPods?: pulumi.Input<number>;
+        /**
+         * labels the node registers with, conflicts with the labels of the node
+         */
+        nodeLabels?: pulumi.Input<{[key: string]: pulumi.Input<string>}>;
+    }
+
     /**
      * KIND Node type
      */
@@ -42,11 +239,41 @@ export namespace node {
         image?: pulumi.Input<string>;
         kubeadmConfigPatches?: pulumi.Input<pulumi.Input<string>[]>;
         kubeadmConfigPatchesJSON6902?: pulumi.Input<pulumi.Input<inputs.patchjson6902.PatchJSON6902Args>[]>;
+        /**
+         * kubelet settings of the node, rendered into the kubeadm config of the node for its role when it joins the cluster
+         */
+        kubelet?: pulumi.Input<inputs.node.KubeletArgs>;
+        /**
+         * Kubernetes version like 1.21 or v1.21.1 of the node, resolved to the node image pinned by digest for the KIND release of the provider. Conflicts with image
+         */
+        kubernetesVersion?: pulumi.Input<string>;
         labels?: pulumi.Input<{[key: string]: pulumi.Input<string>}>;
         /**
          * node role type
          */
         role?: pulumi.Input<string | enums.node.RoleType>;
+        /**
+         * taints the node registers with, on top of the taint kubeadm gives control plane nodes
+         */
+        taints?: pulumi.Input<pulumi.Input<inputs.node.TaintArgs>[]>;
+    }
+
+    /**
+     * taint of a node
+     */
+    export interface TaintArgs {
+        /**
+         * NoSchedule, PreferNoSchedule or NoExecute
+         */
+        effect: pulumi.Input<string>;
+        /**
+         * taint key
+         */
+        key: pulumi.Input<string>;
+        /**
+         * taint value
+         */
+        value?: pulumi.Input<string>;
     }
 }
 
@@ -4,9 +4,115 @@
 import * as pulumi from "@pulumi/pulumi";
 import { input as inputs, output as outputs, enums } from "../types";
 
+export namespace cluster {
+    /**
+     * live information of a KIND node container
+     */
+    export interface NodeDetail {
+        /**
+         * host address and port of the published container ports keyed by <containerPort>/<protocol> like 80/tcp, with the ports allocated for a hostPort of 0
+         */
+        hostPorts?: {[key: string]: string};
+        /**
+         * node image the container was created from
+         */
+        image: string;
+        /**
+         * IPv4 address of the node on the KIND network
+         */
+        ipv4Address?: string;
+        /**
+         * IPv6 address of the node on the KIND network
+         */
+        ipv6Address?: string;
+        /**
+         * node container name
+         */
+        name: string;
+        /**
+         * container ports published on the host with the host ports allocated by the container runtime
+         */
+        portMappings?: outputs.cluster.NodePortMapping[];
+        /**
+         * node role
+         */
+        role: string;
+    }
+
+    /**
+     * container port of a KIND node published on the host
+     */
+    export interface NodePortMapping {
+        /**
+         * port in the node container
+         */
+        containerPort: number;
+        /**
+         * host port the container port is published on
+         */
+        hostPort: number;
+        /**
+         * host address the port is published on
+         */
+        listenAddress: string;
+        /**
+         * port protocol
+         */
+        protocol: string;
+    }
+
+}
+
 export namespace mount {
 }
 
+export namespace network {
+    /**
+     * cluster attached to the peering network
+     */
+    export interface PeeredCluster {
+        /**
+         * cluster name
+         */
+        name: string;
+        /**
+         * nodes of the cluster on the peering network
+         */
+        nodes: outputs.network.PeeredNode[];
+        /**
+         * pod subnet of the cluster, comma separated for dual stack clusters
+         */
+        podSubnet: string;
+        /**
+         * service subnet of the cluster, comma separated for dual stack clusters
+         */
+        serviceSubnet: string;
+    }
+
+    /**
+     * node attached to the peering network
+     */
+    export interface PeeredNode {
+        /**
+         * IPv4 address of the node on the peering network
+         */
+        ipv4Address?: string;
+        /**
+         * IPv6 address of the node on the peering network
+         */
+        ipv6Address?: string;
+        /**
+         * node container name
+         */
+        name: string;
+        /**
+         * pod CIDRs assigned to the node, routed via its address by the nodes of the other clusters
+         */
+        podCidrs?: string[];
+    }
+
+}
+
 export namespace networking {
 }
 